@@ -57,6 +57,12 @@ const (
 
 	debounceScaleDownThreshold = 5 // Number of consecutive triggers before scaling down
 
+	responseTimeWindowSize = 10 // Number of recent response times kept per handler for variability analysis
+
 	// TODO this comment
 	AcceptableAverageResponseTime = 100 * time.Millisecond
+
+	// DefaultPermitAcquireTimeout is the longest a caller waits for a concurrency permit when the
+	// context passed to AcquireConcurrencyPermit carries no deadline of its own.
+	DefaultPermitAcquireTimeout = 10 * time.Second
 )
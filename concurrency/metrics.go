@@ -4,10 +4,9 @@ package concurrency
 import (
 	"math"
 	"net/http"
-	"strconv"
-	"sync"
 	"time"
 
+	"github.com/deploymenttheory/go-api-http-client/ratehandler"
 	"go.uber.org/zap"
 )
 
@@ -47,6 +46,11 @@ var metricWeights = map[string]float64{
 //
 // Note: This function does not return any value; it performs actions based on internal assessments and logs outcomes.
 func (ch *ConcurrencyHandler) EvaluateAndAdjustConcurrency(resp *http.Response, responseTime time.Duration) {
+	ch.Metrics.Lock()
+	ch.Metrics.TotalResponseTime += responseTime
+	ch.Metrics.TotalResponses++
+	ch.Metrics.Unlock()
+
 	rateLimitFeedback := ch.MonitorRateLimitHeaders(resp)
 	responseCodeFeedback := ch.MonitorServerResponseCodes(resp)
 	responseTimeFeedback := ch.MonitorResponseTimeVariability(responseTime)
@@ -60,7 +64,7 @@ func (ch *ConcurrencyHandler) EvaluateAndAdjustConcurrency(resp *http.Response,
 	cumulativeScore := weightedRateLimitScore + weightedResponseCodeScore + weightedResponseTimeScore
 
 	// Detailed debugging output
-	ch.logger.Debug("Evaluate and Adjust Concurrency",
+	ch.log().Debug("Evaluate and Adjust Concurrency",
 		zap.String("event", "EvaluateConcurrency"),
 		zap.Float64("weightedRateLimitScore", weightedRateLimitScore),
 		zap.Float64("weightedResponseCodeScore", weightedResponseCodeScore),
@@ -74,7 +78,7 @@ func (ch *ConcurrencyHandler) EvaluateAndAdjustConcurrency(resp *http.Response,
 
 	// Check for successful response and log appropriately
 	if responseCodeFeedback == 1 { // Assuming 1 indicates success
-		ch.logger.Info("Successful response noted, checking for scaling necessity.",
+		ch.log().Info("Successful response noted, checking for scaling necessity.",
 			zap.String("API Response", "Success"),
 			zap.Int("StatusCode", resp.StatusCode),
 		)
@@ -83,7 +87,7 @@ func (ch *ConcurrencyHandler) EvaluateAndAdjustConcurrency(resp *http.Response,
 	// Check critical thresholds
 	if rateLimitFeedback <= RateLimitCriticalThreshold || responseCodeFeedback < 0 {
 		if weightedRateLimitScore >= ErrorResponseThreshold || weightedResponseCodeScore >= ErrorResponseThreshold {
-			ch.logger.Warn("Scaling down due to critical threshold breach",
+			ch.log().Warn("Scaling down due to critical threshold breach",
 				zap.String("event", "CriticalThresholdBreach"),
 				zap.Int("rateLimitFeedback", rateLimitFeedback),
 				zap.Float64("errorResponseRate", weightedResponseCodeScore),
@@ -95,34 +99,34 @@ func (ch *ConcurrencyHandler) EvaluateAndAdjustConcurrency(resp *http.Response,
 
 	// Evaluate cumulative impact and make a scaling decision based on the cumulative score and other metrics.
 	if cumulativeScore < 0 {
-		utilizedBefore := len(ch.sem) // Tokens in use before scaling down.
+		utilizedBefore := ch.sem.inUse() // Tokens in use before scaling down.
 		ch.ScaleDown()
-		utilizedAfter := len(ch.sem) // Tokens in use after scaling down.
-		ch.logger.Info("Concurrency scaling decision: scale down.",
+		utilizedAfter := ch.sem.inUse() // Tokens in use after scaling down.
+		ch.log().Info("Concurrency scaling decision: scale down.",
 			zap.Float64("cumulativeScore", cumulativeScore),
 			zap.Int("utilizedTokensBefore", utilizedBefore),
 			zap.Int("utilizedTokensAfter", utilizedAfter),
-			zap.Int("availableTokensBefore", cap(ch.sem)-utilizedBefore),
-			zap.Int("availableTokensAfter", cap(ch.sem)-utilizedAfter),
+			zap.Int("availableTokensBefore", ch.sem.limit()-utilizedBefore),
+			zap.Int("availableTokensAfter", ch.sem.limit()-utilizedAfter),
 			zap.String("reason", "Cumulative impact of metrics suggested an overload."),
 		)
 	} else if cumulativeScore > 0 {
-		utilizedBefore := len(ch.sem) // Tokens in use before scaling up.
+		utilizedBefore := ch.sem.inUse() // Tokens in use before scaling up.
 		ch.ScaleUp()
-		utilizedAfter := len(ch.sem) // Tokens in use after scaling up.
-		ch.logger.Info("Concurrency scaling decision: scale up.",
+		utilizedAfter := ch.sem.inUse() // Tokens in use after scaling up.
+		ch.log().Info("Concurrency scaling decision: scale up.",
 			zap.Float64("cumulativeScore", cumulativeScore),
 			zap.Int("utilizedTokensBefore", utilizedBefore),
 			zap.Int("utilizedTokensAfter", utilizedAfter),
-			zap.Int("availableTokensBefore", cap(ch.sem)-utilizedBefore),
-			zap.Int("availableTokensAfter", cap(ch.sem)-utilizedAfter),
+			zap.Int("availableTokensBefore", ch.sem.limit()-utilizedBefore),
+			zap.Int("availableTokensAfter", ch.sem.limit()-utilizedAfter),
 			zap.String("reason", "Metrics indicate available resources to handle more load."),
 		)
 	} else {
-		ch.logger.Info("Concurrency scaling decision: no change.",
+		ch.log().Info("Concurrency scaling decision: no change.",
 			zap.Float64("cumulativeScore", cumulativeScore),
-			zap.Int("currentUtilizedTokens", len(ch.sem)),
-			zap.Int("currentAvailableTokens", cap(ch.sem)-len(ch.sem)),
+			zap.Int("currentUtilizedTokens", ch.sem.inUse()),
+			zap.Int("currentAvailableTokens", ch.sem.limit()-ch.sem.inUse()),
 			zap.String("reason", "Metrics are stable, maintaining current concurrency level."),
 		)
 	}
@@ -130,22 +134,18 @@ func (ch *ConcurrencyHandler) EvaluateAndAdjustConcurrency(resp *http.Response,
 
 // MonitorRateLimitHeaders monitors the rate limit headers in the response and suggests a concurrency adjustment.
 func (ch *ConcurrencyHandler) MonitorRateLimitHeaders(resp *http.Response) int {
-	remaining := resp.Header.Get("X-RateLimit-Remaining")
-	retryAfter := resp.Header.Get("Retry-After")
-	if remaining == "" && retryAfter == "" {
+	info := ratehandler.ParseRateLimitInfo(resp, ch.log())
+	if !info.HasRemaining && !info.HasRetryAfter {
 		// No rate limit information available, return a neutral score
 		return 0
 	}
 
 	suggestion := 0
-	if remaining != "" {
-		remainingValue, err := strconv.Atoi(remaining)
-		if err == nil && remainingValue < 10 {
-			suggestion = -1 // Suggest decrease concurrency if critically low
-		}
+	if info.HasRemaining && info.Remaining < 10 {
+		suggestion = -1 // Suggest decrease concurrency if critically low
 	}
 
-	if retryAfter != "" {
+	if info.HasRetryAfter {
 		suggestion = -1 // Suggest decrease concurrency if Retry-After is specified
 	}
 
@@ -179,7 +179,7 @@ func (ch *ConcurrencyHandler) MonitorServerResponseCodes(resp *http.Response) in
 	errorRate := totalErrors / totalRequests
 	ch.Metrics.ResponseCodeMetrics.ErrorRate = errorRate
 
-	ch.logger.Debug("Server Response Code Monitoring",
+	ch.log().Debug("Server Response Code Monitoring",
 		zap.Int("StatusCode", statusCode),
 		zap.Float64("TotalRequests", totalRequests),
 		zap.Float64("TotalErrors", totalErrors),
@@ -193,13 +193,9 @@ func (ch *ConcurrencyHandler) MonitorServerResponseCodes(resp *http.Response) in
 	return 0 // Default to no change if error rate is within acceptable limits
 }
 
-// A slice to hold the last n response times for averaging
-var responseTimes []time.Duration
-var responseTimesLock sync.Mutex
-
 // MonitorResponseTimeVariability assesses the response time variability from a series of HTTP requests and decides whether to adjust the concurrency level of outgoing requests. This function is integral to maintaining optimal system performance under varying load conditions.
 //
-// The function first appends the latest response time to a sliding window of the last 10 response times to maintain a recent history. It then calculates the standard deviation and the average of these times. The standard deviation helps determine the variability or consistency of response times, while the average gives a central tendency.
+// The function first adds the latest response time to the handler's history, either a sliding window of the last 10 response times or an exponential moving average (see ResponseTimeSmoothing). It then takes the standard deviation and the average of that history. The standard deviation helps determine the variability or consistency of response times, while the average gives a central tendency.
 //
 // Based on these calculated metrics, the function employs a multi-factor decision mechanism:
 // - If the standard deviation exceeds a pre-defined threshold and the average response time is greater than an acceptable maximum, a debounce counter is incremented. This counter must reach a predefined threshold (debounceScaleDownThreshold) before a decision to decrease concurrency is made, ensuring that only sustained negative trends lead to a scale down.
@@ -215,15 +211,7 @@ func (ch *ConcurrencyHandler) MonitorResponseTimeVariability(responseTime time.D
 	ch.Metrics.ResponseTimeVariability.Lock()
 	defer ch.Metrics.ResponseTimeVariability.Unlock()
 
-	responseTimesLock.Lock()
-	responseTimes = append(responseTimes, responseTime)
-	if len(responseTimes) > 10 {
-		responseTimes = responseTimes[1:]
-	}
-	responseTimesLock.Unlock()
-
-	stdDev := calculateStdDev(responseTimes)
-	averageResponseTime := calculateAverage(responseTimes)
+	averageResponseTime, stdDev := ch.recordResponseTime(responseTime)
 
 	if stdDev > ch.Metrics.ResponseTimeVariability.StdDevThreshold && averageResponseTime > AcceptableAverageResponseTime {
 		ch.Metrics.ResponseTimeVariability.DebounceScaleDownCount++
@@ -0,0 +1,89 @@
+// concurrency/metrics_test.go
+package concurrency
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestMonitorResponseTimeVariability_IndependentWindows(t *testing.T) {
+	handlerA := NewConcurrencyHandler(5, zap.NewNop().Sugar(), &ConcurrencyMetrics{})
+	handlerB := NewConcurrencyHandler(5, zap.NewNop().Sugar(), &ConcurrencyMetrics{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			handlerA.MonitorResponseTimeVariability(10 * time.Millisecond)
+		}()
+		go func() {
+			defer wg.Done()
+			handlerB.MonitorResponseTimeVariability(2 * time.Second)
+		}()
+	}
+	wg.Wait()
+
+	tests := []struct {
+		name    string
+		handler *ConcurrencyHandler
+		want    time.Duration
+	}{
+		{
+			name:    "handler A only holds its own samples",
+			handler: handlerA,
+			want:    10 * time.Millisecond,
+		},
+		{
+			name:    "handler B only holds its own samples",
+			handler: handlerB,
+			want:    2 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.handler.responseTimesLock.Lock()
+			defer tt.handler.responseTimesLock.Unlock()
+
+			if got := len(tt.handler.responseTimes); got != responseTimeWindowSize {
+				t.Fatalf("window size = %d, want %d", got, responseTimeWindowSize)
+			}
+			for _, rt := range tt.handler.responseTimes {
+				if rt != tt.want {
+					t.Errorf("response time = %v, want %v", rt, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMonitorRateLimitHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{name: "no headers", want: 0},
+		{name: "plenty remaining", headers: map[string]string{"X-RateLimit-Remaining": "500"}, want: 0},
+		{name: "critically low remaining", headers: map[string]string{"X-RateLimit-Remaining": "3"}, want: -1},
+		{name: "retry after", headers: map[string]string{"X-RateLimit-Remaining": "500", "Retry-After": "10"}, want: -1},
+		{name: "malformed remaining", headers: map[string]string{"X-RateLimit-Remaining": "unknown"}, want: 0},
+	}
+
+	ch := NewConcurrencyHandler(5, zap.NewNop().Sugar(), &ConcurrencyMetrics{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			for name, value := range tt.headers {
+				resp.Header.Set(name, value)
+			}
+			if got := ch.MonitorRateLimitHeaders(resp); got != tt.want {
+				t.Errorf("MonitorRateLimitHeaders() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
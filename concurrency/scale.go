@@ -8,13 +8,11 @@ func (ch *ConcurrencyHandler) ScaleDown() {
 	ch.Lock()
 	defer ch.Unlock()
 
-	currentSize := cap(ch.sem)
+	currentSize := ch.sem.limit()
 	if currentSize > MinConcurrency {
-		newSize := currentSize - 1
-		ch.logger.Info("Reducing request concurrency", zap.Int("currentSize", currentSize), zap.Int("newSize", newSize))
-		ch.ResizeSemaphore(newSize)
+		ch.ResizeSemaphore(currentSize - 1)
 	} else {
-		ch.logger.Info("Concurrency already at minimum level; cannot reduce further", zap.Int("currentSize", currentSize))
+		ch.log().Info("Concurrency already at minimum level; cannot reduce further", zap.Int("currentSize", currentSize))
 	}
 }
 
@@ -23,41 +21,25 @@ func (ch *ConcurrencyHandler) ScaleUp() {
 	ch.Lock()
 	defer ch.Unlock()
 
-	currentSize := cap(ch.sem)
+	currentSize := ch.sem.limit()
 	if currentSize < MaxConcurrency {
-		newSize := currentSize + 1
-		ch.logger.Info("Increasing request concurrency", zap.Int("currentSize", currentSize), zap.Int("newSize", newSize))
-		ch.ResizeSemaphore(newSize)
+		ch.ResizeSemaphore(currentSize + 1)
 	} else {
-		ch.logger.Info("Concurrency already at maximum level; cannot increase further", zap.Int("currentSize", currentSize))
+		ch.log().Info("Concurrency already at maximum level; cannot increase further", zap.Int("currentSize", currentSize))
 	}
 }
 
-// ResizeSemaphore adjusts the size of the semaphore used to control concurrency. This method creates a new
-// semaphore with the specified new size and closes the old semaphore to ensure that no further tokens can
-// be acquired from it. This approach helps manage the transition from the old concurrency level to the new one
-// without affecting ongoing operations significantly.
+// ResizeSemaphore changes the concurrency limit to newSize. Permits already held stay accounted against the
+// semaphore, so when shrinking below the number of tokens in use no new permits are granted until enough
+// in-flight requests have released theirs. Releases always return their tokens regardless of intervening resizes.
 //
 // Parameters:
 //   - newSize: The new size for the semaphore, representing the updated limit on concurrent requests.
-//
-// This function should be called from within synchronization contexts, such as AdjustConcurrency, to avoid
-// race conditions and ensure that changes to the semaphore are consistent with the observed metrics.
 func (ch *ConcurrencyHandler) ResizeSemaphore(newSize int) {
-	newSem := make(chan struct{}, newSize)
-
-	for {
-		select {
-		case token := <-ch.sem:
-			select {
-			case newSem <- token:
-			default:
-				ch.sem <- token
-			}
-		default:
-			close(ch.sem)
-			ch.sem = newSem
-			return
-		}
+	oldSize := ch.sem.limit()
+	if newSize != oldSize {
+		ch.log().Infow("Concurrency limit changed", zap.Int("oldLimit", oldSize), zap.Int("newLimit", newSize))
 	}
+
+	ch.sem.resize(newSize)
 }
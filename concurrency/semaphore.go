@@ -6,51 +6,111 @@ package concurrency
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// ErrPermitAcquireTimeout is returned by AcquireConcurrencyPermit when the context expires or is cancelled
+// before a concurrency permit becomes available.
+var ErrPermitAcquireTimeout = errors.New("timed out waiting for concurrency permit")
+
 // AcquireConcurrencyPermit acquires a concurrency permit to manage the number of simultaneous
 // operations within predefined limits. This method ensures system stability and compliance
 // with concurrency policies by regulating the execution of concurrent operations.
 //
 // Parameters:
-//   - ctx: A parent context which is used as the basis for permit acquisition. This allows
-//     for proper handling of timeouts and cancellation in line with best practices.
+//   - ctx: A parent context which is used as the basis for permit acquisition. Its deadline and
+//     cancellation are respected while waiting for a permit. If the context carries no deadline,
+//     DefaultPermitAcquireTimeout is applied so callers never wait indefinitely.
 //
 // Returns:
 //   - context.Context: A new context derived from the original, including a unique request ID.
 //     This context is used to trace and manage operations under the acquired concurrency permit.
 //   - uuid.UUID: The unique request ID generated during the permit acquisition process.
-//   - error: An error object that indicates failure to acquire a permit within the allotted
-//     timeout, or other system-related issues.
+//   - error: ErrPermitAcquireTimeout (wrapping the context error) if no permit became available
+//     before the context expired or was cancelled.
 //
 // Usage:
 // This function should be used before initiating any operation that requires concurrency control.
 // The returned context should be passed to subsequent operations to maintain consistency in
-// concurrency tracking.
+// concurrency tracking. Time spent waiting is recorded in Metrics.PermitWaitTime whether or not
+// a permit is acquired.
 func (ch *ConcurrencyHandler) AcquireConcurrencyPermit(ctx context.Context) (context.Context, uuid.UUID, error) {
-	log := ch.logger
+	return ch.AcquireWeightedConcurrencyPermit(ctx, 1)
+}
+
+// AcquireWeightedConcurrencyPermit acquires a permit costing weight tokens, so heavy requests (e.g. large uploads)
+// take a larger share of the concurrency limit than light ones. It behaves like AcquireConcurrencyPermit otherwise.
+// A weight below 1 is treated as 1, and a weight above the current limit is capped at the limit so the permit can
+// always eventually be granted. ReleaseConcurrencyPermit returns the same weight that was acquired, even if the
+// limit has been resized in the meantime.
+//
+// Weighted acquisitions are serialised, so a heavy request gathering tokens cannot deadlock against another heavy
+// request holding part of the tokens it needs.
+func (ch *ConcurrencyHandler) AcquireWeightedConcurrencyPermit(ctx context.Context, weight int) (context.Context, uuid.UUID, error) {
+	log := ch.log()
 	tokenAcquisitionStart := time.Now()
 	requestID := uuid.New()
 
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
+	waitCtx := ctx
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, DefaultPermitAcquireTimeout)
+		defer cancel()
+	}
 
 	select {
-	case ch.sem <- struct{}{}:
-		tokenAcquisitionDuration := time.Since(tokenAcquisitionStart)
-		ch.trackResourceAcquisition(tokenAcquisitionDuration, requestID)
-
-		ctxWithRequestID := context.WithValue(ctx, RequestIDKey{}, requestID)
-		return ctxWithRequestID, requestID, nil
+	case ch.acquireLock <- struct{}{}:
+		defer func() { <-ch.acquireLock }()
+	case <-waitCtx.Done():
+		log.Error("Failed to acquire concurrency permit", zap.Error(waitCtx.Err()))
+		return ch.permitAcquireFailed(ctx, requestID, tokenAcquisitionStart, waitCtx.Err())
+	}
 
-	case <-ctxWithTimeout.Done():
-		log.Error("Failed to acquire concurrency permit", zap.Error(ctxWithTimeout.Err()))
-		return ctx, requestID, ctxWithTimeout.Err()
+	if weight < 1 {
+		weight = 1
+	}
+	weight, err := ch.sem.acquire(waitCtx, weight)
+	if err != nil {
+		log.Error("Failed to acquire concurrency permit", zap.Error(err))
+		return ch.permitAcquireFailed(ctx, requestID, tokenAcquisitionStart, err)
 	}
+
+	// The caller can only release the permit once this function returns, so hand the tokens back
+	// if bookkeeping panics before then.
+	defer func() {
+		if r := recover(); r != nil {
+			ch.Lock()
+			delete(ch.weights, requestID)
+			ch.Unlock()
+			ch.sem.release(weight)
+			panic(r)
+		}
+	}()
+
+	ch.Lock()
+	ch.weights[requestID] = weight
+	ch.Unlock()
+
+	tokenAcquisitionDuration := time.Since(tokenAcquisitionStart)
+	ch.trackResourceAcquisition(tokenAcquisitionDuration, requestID)
+
+	ctxWithRequestID := context.WithValue(ctx, RequestIDKey{}, requestID)
+	return ctxWithRequestID, requestID, nil
+}
+
+// permitAcquireFailed records the time lost waiting for a permit that was never granted and
+// builds the ErrPermitAcquireTimeout error returned to the caller.
+func (ch *ConcurrencyHandler) permitAcquireFailed(ctx context.Context, requestID uuid.UUID, start time.Time, cause error) (context.Context, uuid.UUID, error) {
+	ch.Metrics.Lock()
+	ch.Metrics.PermitWaitTime += time.Since(start)
+	ch.Metrics.Unlock()
+
+	return ctx, requestID, fmt.Errorf("%w: %w", ErrPermitAcquireTimeout, cause)
 }
 
 // trackResourceAcquisition logs and updates metrics associated with the acquisition of concurrency tokens.
@@ -73,9 +133,9 @@ func (ch *ConcurrencyHandler) trackResourceAcquisition(duration time.Duration, r
 	ch.Metrics.TotalRequests++
 	ch.Metrics.Unlock()
 
-	utilizedPermits := len(ch.sem)
-	availablePermits := cap(ch.sem) - utilizedPermits
-	ch.logger.Debug("Resource acquired", zap.String("RequestID", requestID.String()), zap.Duration("Duration", duration), zap.Int("UtilizedPermits", utilizedPermits), zap.Int("AvailablePermits", availablePermits))
+	utilizedPermits := ch.sem.inUse()
+	availablePermits := ch.sem.limit() - utilizedPermits
+	ch.log().Debug("Resource acquired", zap.String("RequestID", requestID.String()), zap.Duration("Duration", duration), zap.Int("UtilizedPermits", utilizedPermits), zap.Int("AvailablePermits", availablePermits))
 }
 
 // ReleaseConcurrencyPermit releases a concurrency permit back to the semaphore, making it available for other
@@ -96,26 +156,44 @@ func (ch *ConcurrencyHandler) trackResourceAcquisition(duration time.Duration, r
 // This usage ensures that the permit is released in a deferred manner at the end of the operation, regardless of
 // how the operation exits (normal completion or error path).
 func (ch *ConcurrencyHandler) ReleaseConcurrencyPermit(requestID uuid.UUID) {
-	select {
-	case <-ch.sem:
-	default:
-		ch.logger.Error("Attempted to release a non-existent concurrency permit", zap.String("RequestID", requestID.String()))
-		return
-	}
-
 	ch.Lock()
 	defer ch.Unlock()
 
+	weight, ok := ch.weights[requestID]
+	delete(ch.weights, requestID)
+
+	if !ok || ch.sem.release(weight) == 0 {
+		ch.log().Error("Attempted to release a non-existent concurrency permit", zap.String("RequestID", requestID.String()))
+		return
+	}
+
 	ch.Metrics.Lock()
 	ch.Metrics.TotalRequests--
 	ch.Metrics.Unlock()
 
-	utilizedPermits := len(ch.sem)
-	availablePermits := cap(ch.sem) - utilizedPermits
+	utilizedPermits := ch.sem.inUse()
+	availablePermits := ch.sem.limit() - utilizedPermits
 
-	ch.logger.Debug("Released concurrency permit",
+	ch.log().Debug("Released concurrency permit",
 		zap.String("RequestID", requestID.String()),
+		zap.Int("Weight", weight),
 		zap.Int("UtilizedPermits", utilizedPermits),
 		zap.Int("AvailablePermits", availablePermits),
 	)
 }
+
+// PermitsInUse returns the number of concurrency tokens currently held, counting each weighted permit at its weight.
+func (ch *ConcurrencyHandler) PermitsInUse() int {
+	return ch.sem.inUse()
+}
+
+// CurrentLimit returns the current concurrency limit, which ScaleUp and ScaleDown adjust over time.
+func (ch *ConcurrencyHandler) CurrentLimit() int {
+	return ch.sem.limit()
+}
+
+// Utilization returns the fraction of the current concurrency limit held by outstanding permits. It can briefly
+// exceed 1 after the limit shrinks below the permits still in flight.
+func (ch *ConcurrencyHandler) Utilization() float64 {
+	return ch.sem.utilization()
+}
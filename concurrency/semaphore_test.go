@@ -0,0 +1,224 @@
+// concurrency/semaphore_test.go
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAcquireConcurrencyPermit_ContextTimeout(t *testing.T) {
+	metrics := &ConcurrencyMetrics{}
+	ch := NewConcurrencyHandler(1, zap.NewNop().Sugar(), metrics)
+
+	_, heldID, err := ch.AcquireConcurrencyPermit(context.Background())
+	if err != nil {
+		t.Fatalf("first acquisition failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, _, err = ch.AcquireConcurrencyPermit(ctx)
+	if !errors.Is(err, ErrPermitAcquireTimeout) {
+		t.Fatalf("error = %v, want ErrPermitAcquireTimeout", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want it to wrap context.DeadlineExceeded", err)
+	}
+
+	metrics.Lock()
+	waited := metrics.PermitWaitTime
+	metrics.Unlock()
+	if waited < 20*time.Millisecond {
+		t.Errorf("PermitWaitTime = %v, want at least the time spent queueing", waited)
+	}
+
+	ch.ReleaseConcurrencyPermit(heldID)
+
+	if got := ch.PermitsInUse(); got != 0 {
+		t.Fatalf("tokens in use after release = %d, want 0 (cancelled acquisition leaked a token)", got)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, _, err := ch.AcquireConcurrencyPermit(ctx); err != nil {
+		t.Errorf("acquisition after release failed: %v", err)
+	}
+}
+
+func TestAcquireConcurrencyPermit_CancelledContext(t *testing.T) {
+	ch := NewConcurrencyHandler(1, zap.NewNop().Sugar(), &ConcurrencyMetrics{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := ch.AcquireConcurrencyPermit(ctx)
+	if !errors.Is(err, ErrPermitAcquireTimeout) {
+		t.Fatalf("error = %v, want ErrPermitAcquireTimeout", err)
+	}
+	if got := ch.PermitsInUse(); got != 0 {
+		t.Errorf("tokens in use = %d, want 0", got)
+	}
+}
+
+func TestAcquireWeightedConcurrencyPermit_PanicReleasesTokens(t *testing.T) {
+	// A logger hook which panics while the acquisition is being recorded, after the tokens have been taken.
+	panicking := true
+	hook := zap.Hooks(func(entry zapcore.Entry) error {
+		if panicking && strings.HasPrefix(entry.Message, "Resource acquired") {
+			panic("logger hook failed")
+		}
+		return nil
+	})
+	logger := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(io.Discard), zapcore.DebugLevel), hook)
+	ch := NewConcurrencyHandler(2, logger.Sugar(), &ConcurrencyMetrics{})
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("AcquireWeightedConcurrencyPermit() did not re-panic")
+			}
+		}()
+		ch.AcquireWeightedConcurrencyPermit(context.Background(), 2)
+	}()
+
+	if got := ch.PermitsInUse(); got != 0 {
+		t.Fatalf("tokens in use after the panic = %d, want 0", got)
+	}
+	ch.Lock()
+	tracked := len(ch.weights)
+	ch.Unlock()
+	if tracked != 0 {
+		t.Errorf("tracked permits after the panic = %d, want 0", tracked)
+	}
+
+	panicking = false
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, id, err := ch.AcquireWeightedConcurrencyPermit(ctx, 2)
+	if err != nil {
+		t.Fatalf("acquisition after the panic failed: %v", err)
+	}
+	ch.ReleaseConcurrencyPermit(id)
+}
+
+func TestAcquireWeightedConcurrencyPermit(t *testing.T) {
+	ch := NewConcurrencyHandler(4, zap.NewNop().Sugar(), &ConcurrencyMetrics{})
+
+	_, heavyID, err := ch.AcquireWeightedConcurrencyPermit(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("heavy acquisition failed: %v", err)
+	}
+	if got := ch.PermitsInUse(); got != 3 {
+		t.Fatalf("tokens in use = %d, want 3", got)
+	}
+
+	_, lightID, err := ch.AcquireConcurrencyPermit(context.Background())
+	if err != nil {
+		t.Fatalf("light acquisition failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := ch.AcquireWeightedConcurrencyPermit(ctx, 2); !errors.Is(err, ErrPermitAcquireTimeout) {
+		t.Fatalf("over-limit acquisition error = %v, want ErrPermitAcquireTimeout", err)
+	}
+	if got := ch.PermitsInUse(); got != 4 {
+		t.Fatalf("tokens in use after failed acquisition = %d, want 4 (partial tokens leaked)", got)
+	}
+
+	ch.ReleaseConcurrencyPermit(heavyID)
+	if got := ch.PermitsInUse(); got != 1 {
+		t.Errorf("tokens in use after releasing heavy permit = %d, want 1", got)
+	}
+	ch.ReleaseConcurrencyPermit(lightID)
+
+	// Weights above the limit are capped so they can still be granted.
+	_, cappedID, err := ch.AcquireWeightedConcurrencyPermit(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("capped acquisition failed: %v", err)
+	}
+	if got := ch.PermitsInUse(); got != 4 {
+		t.Errorf("tokens in use for capped permit = %d, want 4", got)
+	}
+
+	ch.ScaleDown()
+	ch.ReleaseConcurrencyPermit(cappedID)
+	if got := ch.PermitsInUse(); got != 0 {
+		t.Errorf("tokens in use after scale down and release = %d, want 0", got)
+	}
+}
+
+func TestConcurrencyHandler_CurrentLimitAndUtilization(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	ch := NewConcurrencyHandler(4, zap.New(core).Sugar(), &ConcurrencyMetrics{})
+
+	if got := ch.CurrentLimit(); got != 4 {
+		t.Fatalf("CurrentLimit() = %d, want 4", got)
+	}
+	if got := ch.Utilization(); got != 0 {
+		t.Fatalf("Utilization() = %v, want 0", got)
+	}
+
+	_, requestID, err := ch.AcquireWeightedConcurrencyPermit(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("acquisition failed: %v", err)
+	}
+	if got := ch.Utilization(); got != 0.5 {
+		t.Errorf("Utilization() = %v, want 0.5", got)
+	}
+
+	ch.ScaleUp()
+	if got := ch.CurrentLimit(); got != 5 {
+		t.Errorf("CurrentLimit() after ScaleUp = %d, want 5", got)
+	}
+
+	changes := logs.FilterMessage("Concurrency limit changed").All()
+	if len(changes) != 1 {
+		t.Fatalf("limit change log lines = %d, want 1", len(changes))
+	}
+	fields := changes[0].ContextMap()
+	if fields["oldLimit"] != int64(4) || fields["newLimit"] != int64(5) {
+		t.Errorf("limit change fields = %v, want oldLimit=4 newLimit=5", fields)
+	}
+
+	ch.ReleaseConcurrencyPermit(requestID)
+}
+
+func TestConcurrencyHandler_AccessorsRaceWithScaling(t *testing.T) {
+	ch := NewConcurrencyHandler(MaxConcurrency/2, zap.NewNop().Sugar(), &ConcurrencyMetrics{})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if i%2 == 0 {
+				ch.ScaleUp()
+			} else {
+				ch.ScaleDown()
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if limit := ch.CurrentLimit(); limit < MinConcurrency || limit > MaxConcurrency {
+				t.Errorf("CurrentLimit() = %d, outside [%d, %d]", limit, MinConcurrency, MaxConcurrency)
+			}
+			if u := ch.Utilization(); u < 0 {
+				t.Errorf("Utilization() = %v, want >= 0", u)
+			}
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,101 @@
+// concurrency/smoothing.go
+package concurrency
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ResponseTimeSmoothing selects how MonitorResponseTimeVariability averages response times before comparing them
+// against its thresholds.
+//
+// The sliding window reacts quickly: every sample carries equal weight among the last few, so a short burst of slow
+// responses moves the average and standard deviation immediately. That suits low request rates, but at high
+// throughput a brief spike dominates the window and scaling decisions flap.
+//
+// The exponential moving average has no hard window. Each sample moves the average by ResponseTimeEMAAlpha of its
+// distance from it, so older samples fade out gradually. A small alpha gives a stable signal that ignores transient
+// spikes but takes longer to notice a sustained slowdown; a large alpha approaches the window's responsiveness.
+type ResponseTimeSmoothing string
+
+const (
+	// ResponseTimeSmoothingWindow averages the last responseTimeWindowSize response times (the default).
+	ResponseTimeSmoothingWindow ResponseTimeSmoothing = ""
+	// ResponseTimeSmoothingEMA uses an exponentially weighted moving average and variance.
+	ResponseTimeSmoothingEMA ResponseTimeSmoothing = "ema"
+)
+
+// DefaultResponseTimeEMAAlpha is the EMA smoothing factor used when none is configured. Roughly, the last 1/alpha
+// samples dominate the average.
+const DefaultResponseTimeEMAAlpha = 0.1
+
+// Validate reports an error for unknown smoothing modes.
+func (s ResponseTimeSmoothing) Validate() error {
+	switch s {
+	case ResponseTimeSmoothingWindow, ResponseTimeSmoothingEMA:
+		return nil
+	default:
+		return fmt.Errorf("unknown response time smoothing %q", string(s))
+	}
+}
+
+// SetResponseTimeSmoothing selects how response times are averaged. For ResponseTimeSmoothingEMA, alpha is the
+// weight given to each new sample, between 0 (exclusive) and 1; values outside that range use
+// DefaultResponseTimeEMAAlpha. Switching modes discards the history gathered so far.
+func (ch *ConcurrencyHandler) SetResponseTimeSmoothing(smoothing ResponseTimeSmoothing, alpha float64) {
+	if alpha <= 0 || alpha > 1 {
+		alpha = DefaultResponseTimeEMAAlpha
+	}
+
+	ch.responseTimesLock.Lock()
+	defer ch.responseTimesLock.Unlock()
+
+	ch.smoothing = smoothing
+	ch.ema = responseTimeEMA{alpha: alpha}
+	ch.responseTimes = nil
+}
+
+// responseTimeEMA tracks an exponentially weighted mean and variance of response times, in seconds.
+type responseTimeEMA struct {
+	alpha    float64
+	mean     float64
+	variance float64
+	primed   bool
+}
+
+// add folds sample into the running mean and variance using the incremental form of the exponentially weighted
+// variance, so no history needs to be stored.
+func (e *responseTimeEMA) add(sample time.Duration) {
+	x := sample.Seconds()
+	if !e.primed {
+		e.mean, e.variance, e.primed = x, 0, true
+		return
+	}
+
+	diff := x - e.mean
+	increment := e.alpha * diff
+	e.mean += increment
+	e.variance = (1 - e.alpha) * (e.variance + diff*increment)
+}
+
+// recordResponseTime adds responseTime to the configured history and returns the resulting average response time
+// and standard deviation in seconds.
+func (ch *ConcurrencyHandler) recordResponseTime(responseTime time.Duration) (time.Duration, float64) {
+	ch.responseTimesLock.Lock()
+	defer ch.responseTimesLock.Unlock()
+
+	if ch.smoothing == ResponseTimeSmoothingEMA {
+		if ch.ema.alpha == 0 {
+			ch.ema.alpha = DefaultResponseTimeEMAAlpha
+		}
+		ch.ema.add(responseTime)
+		return time.Duration(ch.ema.mean * float64(time.Second)), math.Sqrt(ch.ema.variance)
+	}
+
+	ch.responseTimes = append(ch.responseTimes, responseTime)
+	if len(ch.responseTimes) > responseTimeWindowSize {
+		ch.responseTimes = ch.responseTimes[1:]
+	}
+	return calculateAverage(ch.responseTimes), calculateStdDev(ch.responseTimes)
+}
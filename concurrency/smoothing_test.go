@@ -0,0 +1,75 @@
+// concurrency/smoothing_test.go
+package concurrency
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestRecordResponseTime_SpikeSmoothing(t *testing.T) {
+	tests := []struct {
+		name      string
+		smoothing ResponseTimeSmoothing
+		minAvg    time.Duration
+		maxAvg    time.Duration
+	}{
+		// One 2s spike among 10ms samples lifts the 10 sample window's average by ~200ms.
+		{name: "sliding window", smoothing: ResponseTimeSmoothingWindow, minAvg: 200 * time.Millisecond, maxAvg: 250 * time.Millisecond},
+		// With alpha 0.05 the spike moves the EMA by ~5% of its distance and then decays.
+		{name: "ema", smoothing: ResponseTimeSmoothingEMA, minAvg: 100 * time.Millisecond, maxAvg: 120 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := NewConcurrencyHandler(5, zap.NewNop().Sugar(), &ConcurrencyMetrics{})
+			ch.SetResponseTimeSmoothing(tt.smoothing, 0.05)
+
+			for i := 0; i < 50; i++ {
+				ch.recordResponseTime(10 * time.Millisecond)
+			}
+			avg, _ := ch.recordResponseTime(2 * time.Second)
+			if avg < tt.minAvg || avg > tt.maxAvg {
+				t.Errorf("average after spike = %v, want between %v and %v", avg, tt.minAvg, tt.maxAvg)
+			}
+		})
+	}
+}
+
+func TestRecordResponseTime_EMAConverges(t *testing.T) {
+	ch := NewConcurrencyHandler(5, zap.NewNop().Sugar(), &ConcurrencyMetrics{})
+	ch.SetResponseTimeSmoothing(ResponseTimeSmoothingEMA, 0)
+
+	var (
+		avg    time.Duration
+		stdDev float64
+	)
+	for i := 0; i < 200; i++ {
+		avg, stdDev = ch.recordResponseTime(50 * time.Millisecond)
+	}
+	if avg < 49*time.Millisecond || avg > 51*time.Millisecond {
+		t.Errorf("average = %v, want ~50ms", avg)
+	}
+	if stdDev > 0.001 {
+		t.Errorf("standard deviation = %v, want ~0 for constant samples", stdDev)
+	}
+
+	// A sustained slowdown still pulls the average up.
+	for i := 0; i < 100; i++ {
+		avg, _ = ch.recordResponseTime(500 * time.Millisecond)
+	}
+	if avg < 450*time.Millisecond {
+		t.Errorf("average after sustained slowdown = %v, want close to 500ms", avg)
+	}
+}
+
+func TestResponseTimeSmoothing_Validate(t *testing.T) {
+	for _, s := range []ResponseTimeSmoothing{ResponseTimeSmoothingWindow, ResponseTimeSmoothingEMA} {
+		if err := s.Validate(); err != nil {
+			t.Errorf("Validate(%q) = %v, want nil", s, err)
+		}
+	}
+	if err := ResponseTimeSmoothing("median").Validate(); err == nil {
+		t.Error("Validate(\"median\") = nil, want an error")
+	}
+}
@@ -0,0 +1,41 @@
+// concurrency/snapshot.go
+package concurrency
+
+import "time"
+
+// ConcurrencyMetricsSnapshot is a point in time, lock free copy of ConcurrencyMetrics, safe to read
+// and pass around for dashboards or exporters such as Prometheus collectors.
+type ConcurrencyMetricsSnapshot struct {
+	TotalRequests        int64
+	TotalRetries         int64
+	TotalRateLimitErrors int64
+	TotalResponseTime    time.Duration
+	TotalResponses       int64
+	PermitWaitTime       time.Duration
+	ErrorRate            float64
+}
+
+// Snapshot locks the metrics and returns a copy of their current values.
+func (m *ConcurrencyMetrics) Snapshot() ConcurrencyMetricsSnapshot {
+	m.Lock()
+	snapshot := ConcurrencyMetricsSnapshot{
+		TotalRequests:        m.TotalRequests,
+		TotalRetries:         m.TotalRetries,
+		TotalRateLimitErrors: m.TotalRateLimitErrors,
+		TotalResponseTime:    m.TotalResponseTime,
+		TotalResponses:       m.TotalResponses,
+		PermitWaitTime:       m.PermitWaitTime,
+		ErrorRate:            m.ResponseCodeMetrics.ErrorRate,
+	}
+	m.Unlock()
+
+	return snapshot
+}
+
+// AverageResponseTime returns the mean response time across all recorded responses, or 0 if none have been recorded.
+func (s ConcurrencyMetricsSnapshot) AverageResponseTime() time.Duration {
+	if s.TotalResponses == 0 {
+		return 0
+	}
+	return s.TotalResponseTime / time.Duration(s.TotalResponses)
+}
@@ -0,0 +1,43 @@
+// concurrency/snapshot_test.go
+package concurrency
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestConcurrencyMetrics_Snapshot(t *testing.T) {
+	metrics := &ConcurrencyMetrics{}
+	ch := NewConcurrencyHandler(5, zap.NewNop().Sugar(), metrics)
+
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+
+	var wg sync.WaitGroup
+	for _, d := range []time.Duration{100 * time.Millisecond, 300 * time.Millisecond} {
+		wg.Add(1)
+		go func(d time.Duration) {
+			defer wg.Done()
+			ch.EvaluateAndAdjustConcurrency(resp, d)
+			_ = metrics.Snapshot()
+		}(d)
+	}
+	wg.Wait()
+
+	snapshot := metrics.Snapshot()
+	if snapshot.TotalResponses != 2 {
+		t.Errorf("TotalResponses = %d, want 2", snapshot.TotalResponses)
+	}
+	if snapshot.TotalResponseTime != 400*time.Millisecond {
+		t.Errorf("TotalResponseTime = %v, want 400ms", snapshot.TotalResponseTime)
+	}
+	if got := snapshot.AverageResponseTime(); got != 200*time.Millisecond {
+		t.Errorf("AverageResponseTime() = %v, want 200ms", got)
+	}
+	if got := (ConcurrencyMetricsSnapshot{}).AverageResponseTime(); got != 0 {
+		t.Errorf("AverageResponseTime() with no responses = %v, want 0", got)
+	}
+}
@@ -3,18 +3,26 @@ package concurrency
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // ConcurrencyHandler controls the number of concurrent HTTP requests.
 type ConcurrencyHandler struct {
-	sem                      chan struct{}
-	logger                   *zap.SugaredLogger
+	sem                      *weightedSemaphore
+	acquireLock              chan struct{}                     // Serialises weighted acquisitions; a channel so waiting honours contexts.
+	weights                  map[uuid.UUID]int                 // Weight held by each outstanding permit.
+	logger                   atomic.Pointer[zap.SugaredLogger] // Swapped by SetLogger while requests are in flight.
 	AcquisitionTimes         []time.Duration
 	lastTokenAcquisitionTime time.Time
 	Metrics                  *ConcurrencyMetrics
+	responseTimes            []time.Duration       // Sliding window of the last n response times for this handler.
+	smoothing                ResponseTimeSmoothing // How response times are averaged; guarded by responseTimesLock.
+	ema                      responseTimeEMA       // Exponential moving average used by ResponseTimeSmoothingEMA.
+	responseTimesLock        sync.Mutex
 	sync.Mutex
 }
 
@@ -24,6 +32,8 @@ type ConcurrencyMetrics struct {
 	TotalRetries         int64
 	TotalRateLimitErrors int64
 	PermitWaitTime       time.Duration
+	TotalResponseTime    time.Duration
+	TotalResponses       int64
 	sync.Mutex
 	TTFB struct {
 		Total time.Duration
@@ -54,14 +64,31 @@ type ConcurrencyMetrics struct {
 // NewConcurrencyHandler initializes a new ConcurrencyHandler with the given
 // concurrency limit, logger, and concurrency metrics. The ConcurrencyHandler ensures
 // no more than a certain number of concurrent requests are made.
-// It uses a semaphore to control concurrency.
+// It uses a resizable weighted semaphore to control concurrency.
 func NewConcurrencyHandler(limit int, logger *zap.SugaredLogger, metrics *ConcurrencyMetrics) *ConcurrencyHandler {
-	return &ConcurrencyHandler{
-		sem:              make(chan struct{}, limit),
-		logger:           logger,
+	ch := &ConcurrencyHandler{
+		sem:              newWeightedSemaphore(limit),
+		acquireLock:      make(chan struct{}, 1),
+		weights:          make(map[uuid.UUID]int),
 		AcquisitionTimes: []time.Duration{},
 		Metrics:          metrics,
 	}
+	ch.logger.Store(logger)
+	return ch
+}
+
+// SetLogger replaces the handler's logger. A nil logger is ignored. It is safe to call while permits are being
+// acquired and released.
+func (ch *ConcurrencyHandler) SetLogger(logger *zap.SugaredLogger) {
+	if logger == nil {
+		return
+	}
+	ch.logger.Store(logger)
+}
+
+// log returns the handler's current logger.
+func (ch *ConcurrencyHandler) log() *zap.SugaredLogger {
+	return ch.logger.Load()
 }
 
 // RequestIDKey is type used as a key for storing and retrieving
@@ -0,0 +1,103 @@
+// concurrency/weighted.go
+package concurrency
+
+import (
+	"context"
+	"sync"
+)
+
+// weightedSemaphore is a counting semaphore whose limit can change while permits are held. Unlike a buffered
+// channel it is never replaced, so releases always land on the semaphore the permit was acquired from. Shrinking
+// the limit below the tokens in use blocks new acquisitions until enough in-flight permits are released.
+type weightedSemaphore struct {
+	mu      sync.Mutex
+	size    int
+	held    int
+	changed chan struct{} // Closed and replaced whenever tokens are released or the limit changes.
+}
+
+// newWeightedSemaphore returns a semaphore allowing size tokens to be held at once.
+func newWeightedSemaphore(size int) *weightedSemaphore {
+	return &weightedSemaphore{size: size, changed: make(chan struct{})}
+}
+
+// acquire blocks until n tokens fit under the current limit or ctx is done. A weight above the limit is capped
+// at the limit so it can always eventually be granted; the weight actually taken is returned.
+func (s *weightedSemaphore) acquire(ctx context.Context, n int) (int, error) {
+	for {
+		s.mu.Lock()
+		weight := n
+		if weight > s.size {
+			weight = s.size
+		}
+		if ctx.Err() == nil && s.held+weight <= s.size {
+			s.held += weight
+			s.mu.Unlock()
+			return weight, nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// release returns up to n tokens, stopping when none are held, and reports how many were returned.
+func (s *weightedSemaphore) release(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n > s.held {
+		n = s.held
+	}
+	s.held -= n
+	if n > 0 {
+		s.notify()
+	}
+	return n
+}
+
+// resize changes the limit. Tokens already held are kept; if they exceed the new limit, acquisitions wait for
+// them to drain.
+func (s *weightedSemaphore) resize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.size = size
+	s.notify()
+}
+
+// notify wakes every waiter so it can re-check the limit. Callers must hold s.mu.
+func (s *weightedSemaphore) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// limit returns the current maximum number of tokens.
+func (s *weightedSemaphore) limit() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// inUse returns the number of tokens currently held.
+func (s *weightedSemaphore) inUse() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.held
+}
+
+// utilization returns held/limit read under a single lock, so a concurrent resize cannot skew the ratio.
+func (s *weightedSemaphore) utilization() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size == 0 {
+		return 0
+	}
+	return float64(s.held) / float64(s.size)
+}
@@ -0,0 +1,146 @@
+// concurrency/weighted_test.go
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestWeightedSemaphore_ShrinkWaitsForInFlight(t *testing.T) {
+	s := newWeightedSemaphore(3)
+	for i := 0; i < 3; i++ {
+		if _, err := s.acquire(context.Background(), 1); err != nil {
+			t.Fatalf("acquire %d failed: %v", i, err)
+		}
+	}
+
+	s.resize(1)
+
+	acquired := make(chan struct{})
+	go func() {
+		if _, err := s.acquire(context.Background(), 1); err == nil {
+			close(acquired)
+		}
+	}()
+
+	// Two releases still leave the held tokens at the new limit, so the waiter must stay blocked.
+	s.release(1)
+	s.release(1)
+	select {
+	case <-acquired:
+		t.Fatal("acquired a token while in-flight permits still filled the shrunk limit")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	s.release(1)
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("waiter was not woken once in-flight permits drained")
+	}
+	if got := s.inUse(); got != 1 {
+		t.Errorf("inUse() = %d, want 1", got)
+	}
+}
+
+func TestWeightedSemaphore_AcquireHonoursContext(t *testing.T) {
+	s := newWeightedSemaphore(1)
+	if _, err := s.acquire(context.Background(), 1); err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.acquire(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("acquire error = %v, want context.DeadlineExceeded", err)
+	}
+	if got := s.inUse(); got != 1 {
+		t.Errorf("inUse() = %d, want 1 (failed acquisition must not hold tokens)", got)
+	}
+}
+
+func TestConcurrencyHandler_ResizeUnderLoad(t *testing.T) {
+	ch := NewConcurrencyHandler(MaxConcurrency/2, zap.NewNop().Sugar(), &ConcurrencyMetrics{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		inFlight atomic.Int64
+		peak     atomic.Int64
+		stop     = make(chan struct{})
+	)
+
+	for w := 0; w < 20; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < 50; i++ {
+				_, requestID, err := ch.AcquireWeightedConcurrencyPermit(ctx, 1+rng.Intn(2))
+				if err != nil {
+					t.Errorf("acquisition failed: %v", err)
+					return
+				}
+				n := inFlight.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(time.Duration(rng.Intn(200)) * time.Microsecond)
+				inFlight.Add(-1)
+				ch.ReleaseConcurrencyPermit(requestID)
+			}
+		}(int64(w))
+	}
+
+	resized := make(chan struct{})
+	go func() {
+		defer close(resized)
+		rng := rand.New(rand.NewSource(99))
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if rng.Intn(2) == 0 {
+				ch.ScaleUp()
+			} else {
+				ch.ScaleDown()
+			}
+			if u := ch.Utilization(); u < 0 {
+				t.Errorf("Utilization() = %v, want >= 0", u)
+			}
+			time.Sleep(100 * time.Microsecond)
+		}
+	}()
+
+	wg.Wait()
+	close(stop)
+	<-resized
+
+	if got := ch.PermitsInUse(); got != 0 {
+		t.Errorf("PermitsInUse() after all releases = %d, want 0", got)
+	}
+	if got := peak.Load(); got > MaxConcurrency {
+		t.Errorf("peak in-flight permits = %d, want at most %d", got, MaxConcurrency)
+	}
+
+	// The semaphore must still grant a full-limit permit once drained.
+	_, requestID, err := ch.AcquireWeightedConcurrencyPermit(ctx, ch.CurrentLimit())
+	if err != nil {
+		t.Fatalf("acquisition after resizing failed: %v", err)
+	}
+	ch.ReleaseConcurrencyPermit(requestID)
+}
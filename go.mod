@@ -7,6 +7,8 @@ require (
 	github.com/google/uuid v1.6.0
 	go.uber.org/zap v1.27.0
 	golang.org/x/net v0.34.0
+	golang.org/x/oauth2 v0.26.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
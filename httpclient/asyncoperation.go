@@ -0,0 +1,216 @@
+// httpclient/asyncoperation.go
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-http-client/ratehandler"
+	"go.uber.org/zap"
+)
+
+// DefaultAsyncMaxPollDuration caps how long an async operation is polled when MaxPollDuration is unset.
+const DefaultAsyncMaxPollDuration = 5 * time.Minute
+
+// ErrAsyncOperationTimeout is returned when an async operation has not completed within MaxPollDuration.
+var ErrAsyncOperationTimeout = errors.New("async operation did not complete in time")
+
+// ErrAsyncOperationFailed is returned, wrapped with the reported status, when an async operation ends failed or
+// canceled.
+var ErrAsyncOperationFailed = errors.New("async operation did not succeed")
+
+// asyncOperationHeaders name the status monitor of an accepted operation, in order of preference.
+var asyncOperationHeaders = []string{"Operation-Location", "Azure-AsyncOperation", "Location"}
+
+// AsyncOperationConfig enables following 202 Accepted responses. When a request is answered with 202 and an
+// Operation-Location (or Azure-AsyncOperation, or Location) header, the client polls that URL until the operation
+// completes and decodes the final resource into the request's out value instead of the 202 body.
+//
+// A poll answered with 202, or with a JSON body whose "status" is e.g. "Running" or "NotStarted", means the
+// operation is still in progress. A terminal "Succeeded" status fetches the resource named by the body's
+// "resourceLocation" or the poll's Location header when present, and otherwise decodes the status body itself;
+// a 200 without a status field is taken to be the final resource. "Failed" and "Canceled" return
+// ErrAsyncOperationFailed.
+//
+// Monitor and resource URLs are requested with the client's credentials, so they are only followed on the scheme
+// and host of the request that named them, or on hosts listed in AllowedHosts; others fail with ErrHostNotAllowed.
+type AsyncOperationConfig struct {
+	// MaxPollDuration caps the total time spent polling one operation. 0 uses DefaultAsyncMaxPollDuration.
+	MaxPollDuration time.Duration `json:"max_poll_duration"`
+
+	// OnStatus, if set, is called after every poll with the operation's intermediate status.
+	OnStatus func(AsyncOperationStatus) `json:"-"`
+}
+
+// AsyncOperationStatus describes one poll of an async operation.
+type AsyncOperationStatus struct {
+	URL        string        // Status monitor URL that was polled.
+	StatusCode int           // HTTP status of the poll.
+	Status     string        // "status" field of the poll's JSON body, if any.
+	Attempt    int           // 1 for the first poll.
+	Elapsed    time.Duration // Time since the operation was accepted.
+}
+
+// asyncStatusBody is the part of a status monitor response the client understands.
+type asyncStatusBody struct {
+	Status           string `json:"status"`
+	ResourceLocation string `json:"resourceLocation"`
+}
+
+// maxPollDuration returns the configured polling cap or its default.
+func (a *AsyncOperationConfig) maxPollDuration() time.Duration {
+	if a.MaxPollDuration > 0 {
+		return a.MaxPollDuration
+	}
+	return DefaultAsyncMaxPollDuration
+}
+
+// asyncOperationURL returns the absolute status monitor URL of a 202 response when following async operations is
+// enabled, or "" when resp should be handled as an ordinary success.
+func (c *Client) asyncOperationURL(resp *http.Response) string {
+	if c.config.AsyncOperations == nil || resp.StatusCode != http.StatusAccepted {
+		return ""
+	}
+
+	for _, name := range asyncOperationHeaders {
+		if location := resolveLocation(resp, resp.Header.Get(name)); location != "" {
+			return location
+		}
+	}
+	return ""
+}
+
+// resolveLocation resolves a possibly relative location against the URL of the request that produced resp.
+func resolveLocation(resp *http.Response, location string) string {
+	if location == "" {
+		return ""
+	}
+	if resp.Request == nil || resp.Request.URL == nil {
+		return location
+	}
+
+	resolved, err := resp.Request.URL.Parse(location)
+	if err != nil {
+		return ""
+	}
+	return resolved.String()
+}
+
+// followAsyncOperation polls statusURL for the operation accepted by resp until it completes, then decodes the final
+// resource into out. The returned response is the last one received.
+func (c *Client) followAsyncOperation(ctx context.Context, resp *http.Response, statusURL string, out interface{}, ro *requestOptions) (*http.Response, error) {
+	cfg := c.config.AsyncOperations
+	log := c.requestLogger(ctx)
+	resp.Body.Close()
+
+	if err := c.checkFollowedURL(resp.Request.URL, statusURL); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	pollCtx, cancel := context.WithTimeout(ctx, cfg.maxPollDuration())
+	defer cancel()
+
+	pollOptions := &requestOptions{weight: 1, requestID: ro.requestID}
+	wait := c.asyncPollDelay(resp, 0)
+
+	for attempt := 1; ; attempt++ {
+		if err := c.backoff.Wait(pollCtx, wait); err != nil {
+			return resp, asyncPollError(ctx, err)
+		}
+
+		pollResp, err := c.request(pollCtx, http.MethodGet, statusURL, nil, pollOptions)
+		if err != nil {
+			return resp, asyncPollError(ctx, err)
+		}
+		resp = pollResp
+
+		if !c.isSuccessStatus(resp.StatusCode) {
+			return nil, c.handleErrorResponse(resp)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return resp, fmt.Errorf("failed to read async operation status: %w", err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		var status asyncStatusBody
+		_ = json.Unmarshal(body, &status)
+
+		if cfg.OnStatus != nil {
+			cfg.OnStatus(AsyncOperationStatus{
+				URL:        statusURL,
+				StatusCode: resp.StatusCode,
+				Status:     status.Status,
+				Attempt:    attempt,
+				Elapsed:    time.Since(start),
+			})
+		}
+		log.Debugw("Async operation polled", zap.String("url", statusURL), zap.Int("status_code", resp.StatusCode), zap.String("status", status.Status), zap.Int("attempt", attempt))
+
+		state := strings.ToLower(status.Status)
+		switch {
+		case resp.StatusCode == http.StatusAccepted || isAsyncInProgress(state):
+			wait = c.asyncPollDelay(resp, attempt)
+			continue
+		case state == "failed" || state == "canceled" || state == "cancelled":
+			return resp, fmt.Errorf("%w: operation at %s reported status %q", ErrAsyncOperationFailed, statusURL, status.Status)
+		}
+
+		location := resolveLocation(resp, status.ResourceLocation)
+		if location == "" && state != "" {
+			location = resolveLocation(resp, resp.Header.Get("Location"))
+		}
+		if location != "" {
+			if err := c.checkFollowedURL(resp.Request.URL, location); err != nil {
+				return resp, err
+			}
+			resp, err = c.request(pollCtx, http.MethodGet, location, nil, pollOptions)
+			if err != nil {
+				return nil, asyncPollError(ctx, err)
+			}
+			if !c.isSuccessStatus(resp.StatusCode) {
+				return nil, c.handleErrorResponse(resp)
+			}
+		}
+
+		log.Infow("Async operation completed", zap.String("url", statusURL), zap.Int("polls", attempt), zap.Duration("elapsed", time.Since(start)))
+		return resp, c.handleSuccessResponse(resp, out, ro)
+	}
+}
+
+// asyncPollDelay returns how long to wait before the next poll: the Retry-After of resp when it sets one, otherwise
+// the retry backoff for attempt.
+func (c *Client) asyncPollDelay(resp *http.Response, attempt int) time.Duration {
+	if wait := ratehandler.ParseRateLimitHeaders(resp, c.Logger()); wait > 0 {
+		return wait
+	}
+	return c.retryBackoff(attempt)
+}
+
+// asyncPollError reports err as ErrAsyncOperationTimeout when it was caused by the polling cap rather than by the
+// caller's own context ending.
+func asyncPollError(parent context.Context, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) && parent.Err() == nil {
+		return fmt.Errorf("%w: %v", ErrAsyncOperationTimeout, err)
+	}
+	return err
+}
+
+// isAsyncInProgress reports whether a lowercased status names an operation that has not finished yet.
+func isAsyncInProgress(state string) bool {
+	switch state {
+	case "notstarted", "running", "inprogress", "pending", "queued", "accepted", "provisioning", "updating", "deleting":
+		return true
+	}
+	return false
+}
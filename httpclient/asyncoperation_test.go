@@ -0,0 +1,201 @@
+// httpclient/asyncoperation_test.go
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoRequestAsyncOperation(t *testing.T) {
+	type widget struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+
+	tests := []struct {
+		name         string
+		async        *AsyncOperationConfig
+		runningPolls int32
+		final        string
+		wantName     string
+		wantStatus   int
+		wantErr      error
+		wantStatuses []string
+	}{
+		{
+			name:         "status monitor with resource location",
+			async:        &AsyncOperationConfig{},
+			runningPolls: 1,
+			final:        `{"status":"Succeeded","resourceLocation":"/widgets/1"}`,
+			wantName:     "created",
+			wantStatus:   http.StatusOK,
+			wantStatuses: []string{"Running", "Succeeded"},
+		},
+		{
+			name:         "final resource returned by the monitor",
+			async:        &AsyncOperationConfig{},
+			final:        `{"name":"inline"}`,
+			wantName:     "inline",
+			wantStatus:   http.StatusOK,
+			wantStatuses: []string{""},
+		},
+		{
+			name:         "failed operation",
+			async:        &AsyncOperationConfig{},
+			final:        `{"status":"Failed"}`,
+			wantErr:      ErrAsyncOperationFailed,
+			wantStatuses: []string{"Failed"},
+		},
+		{
+			name:         "polling cap reached",
+			async:        &AsyncOperationConfig{MaxPollDuration: 150 * time.Millisecond},
+			runningPolls: 1000,
+			wantErr:      ErrAsyncOperationTimeout,
+		},
+		{
+			name:       "disabled returns the accepted response",
+			wantName:   "accepted",
+			wantStatus: http.StatusAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/widgets":
+					w.Header().Set("Operation-Location", "/operations/1")
+					w.WriteHeader(http.StatusAccepted)
+					w.Write([]byte(`{"name":"accepted"}`))
+				case "/operations/1":
+					if polls.Add(1) <= tt.runningPolls {
+						w.Header().Set("Retry-After", "0")
+						w.Write([]byte(`{"status":"Running"}`))
+						return
+					}
+					w.Write([]byte(tt.final))
+				case "/widgets/1":
+					w.Write([]byte(`{"name":"created"}`))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			var mu sync.Mutex
+			var statuses []string
+			if tt.async != nil {
+				tt.async.OnStatus = func(status AsyncOperationStatus) {
+					mu.Lock()
+					defer mu.Unlock()
+					if status.URL != server.URL+"/operations/1" {
+						t.Errorf("status URL = %q, want the resolved Operation-Location", status.URL)
+					}
+					statuses = append(statuses, status.Status)
+				}
+			}
+
+			client := newTestClient(t, server.URL, func(config *ClientConfig) {
+				config.AsyncOperations = tt.async
+			})
+
+			var out widget
+			resp, err := client.DoRequest(http.MethodPost, "/widgets", map[string]string{"name": "new"}, &out)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("DoRequest() error = %v, want %v", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("DoRequest() error = %v", err)
+				}
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("status code = %d, want %d", resp.StatusCode, tt.wantStatus)
+				}
+				if out.Name != tt.wantName {
+					t.Errorf("out.Name = %q, want %q", out.Name, tt.wantName)
+				}
+			}
+
+			if tt.wantStatuses != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				if !reflect.DeepEqual(statuses, tt.wantStatuses) {
+					t.Errorf("reported statuses = %q, want %q", statuses, tt.wantStatuses)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateClientConfigAsyncOperations(t *testing.T) {
+	config := ClientConfig{
+		Integration:     &MockIntegration{BaseURL: "https://example.com"},
+		AsyncOperations: &AsyncOperationConfig{MaxPollDuration: -time.Second},
+	}
+	if err := config.validateClientConfig(); err == nil {
+		t.Fatal("validateClientConfig() accepted a negative MaxPollDuration")
+	}
+}
+
+func TestDoRequestAsyncOperationCrossHost(t *testing.T) {
+	tests := []struct {
+		name         string
+		allowedHosts []string
+		wantErr      error
+		wantPolled   bool
+	}{
+		{name: "foreign monitor is refused", wantErr: ErrHostNotAllowed},
+		{name: "monitor allowed by AllowedHosts", allowedHosts: []string{"127.0.0.1"}, wantPolled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polled atomic.Bool
+			monitor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				polled.Store(true)
+				if len(tt.allowedHosts) == 0 && r.Header.Get("Authorization") != "" {
+					t.Errorf("foreign monitor received Authorization %q", r.Header.Get("Authorization"))
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"name":"done"}`))
+			}))
+			defer monitor.Close()
+
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Operation-Location", monitor.URL+"/operations/1")
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer api.Close()
+
+			client := newTestClient(t, api.URL, func(config *ClientConfig) {
+				config.AsyncOperations = &AsyncOperationConfig{}
+				config.AllowedHosts = tt.allowedHosts
+			})
+
+			var out struct {
+				Name string `json:"name"`
+			}
+			_, err := client.DoRequest(http.MethodPost, "/widgets", nil, &out)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("DoRequest() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil || out.Name != "done" {
+				t.Fatalf("DoRequest() = %+v, %v; want the monitor's resource", out, err)
+			}
+			if polled.Load() != tt.wantPolled {
+				t.Errorf("monitor polled = %v, want %v", polled.Load(), tt.wantPolled)
+			}
+		})
+	}
+}
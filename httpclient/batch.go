@@ -0,0 +1,146 @@
+// httpclient/batch.go
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/deploymenttheory/go-api-http-client/ratehandler"
+	"go.uber.org/zap"
+)
+
+const (
+	// BatchEndpoint is the JSON batching endpoint, relative to the integration's base URL (e.g. Microsoft Graph's /v1.0).
+	BatchEndpoint = "/$batch"
+
+	// MaxBatchSize is the largest number of sub-requests sent in a single batch call. Larger batches are split.
+	MaxBatchSize = 20
+)
+
+// BatchRequest is a single sub-request packed into a JSON batch call.
+type BatchRequest struct {
+	// ID correlates the sub-request with its response. Defaults to the request's position in the batch when empty.
+	ID      string            `json:"id"`
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body,omitempty"`
+}
+
+// BatchResponse is the outcome of a single sub-request, as returned inside a batch response.
+type BatchResponse struct {
+	ID      string            `json:"id"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// batchEnvelope is the JSON document exchanged with the batch endpoint in both directions.
+type batchEnvelope struct {
+	Requests  []BatchRequest  `json:"requests,omitempty"`
+	Responses []BatchResponse `json:"responses,omitempty"`
+}
+
+// DoBatch packs requests into JSON batch calls (the Microsoft Graph $batch format) to cut round trips, sending
+// at most MaxBatchSize sub-requests per call to BatchEndpoint. Responses are returned in the order of requests,
+// each keeping its own status code and body. Sub-requests throttled with a 429 inside the batch are resent, after
+// the longest Retry-After they reported, up to MaxRetryAttempts times; any still throttled are returned as 429.
+func (c *Client) DoBatch(requests []BatchRequest) ([]BatchResponse, error) {
+	ids := make([]string, len(requests))
+	pending := make([]BatchRequest, len(requests))
+	for i, req := range requests {
+		if req.ID == "" {
+			req.ID = strconv.Itoa(i + 1)
+		}
+		ids[i] = req.ID
+		pending[i] = req
+	}
+
+	results := make(map[string]BatchResponse, len(requests))
+	for start := 0; start < len(pending); start += MaxBatchSize {
+		end := start + MaxBatchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+
+		if err := c.sendBatch(pending[start:end], results); err != nil {
+			return nil, err
+		}
+	}
+
+	responses := make([]BatchResponse, len(ids))
+	for i, id := range ids {
+		resp, ok := results[id]
+		if !ok {
+			return nil, fmt.Errorf("batch response missing sub-request id: %s", id)
+		}
+		responses[i] = resp
+	}
+
+	return responses, nil
+}
+
+// sendBatch sends one batch call, storing each sub-response in results by id and resending throttled sub-requests.
+func (c *Client) sendBatch(requests []BatchRequest, results map[string]BatchResponse) error {
+	byID := make(map[string]BatchRequest, len(requests))
+	for _, req := range requests {
+		if _, duplicate := byID[req.ID]; duplicate {
+			return fmt.Errorf("duplicate batch sub-request id: %s", req.ID)
+		}
+		byID[req.ID] = req
+	}
+
+	for attempt := 0; ; attempt++ {
+		var out batchEnvelope
+		resp, err := c.DoRequest(http.MethodPost, BatchEndpoint, batchEnvelope{Requests: requests}, &out)
+		if err != nil {
+			return fmt.Errorf("batch request failed: %w", err)
+		}
+		resp.Body.Close()
+
+		var throttled []BatchRequest
+		var wait time.Duration
+		for _, item := range out.Responses {
+			results[item.ID] = item
+
+			if item.Status != http.StatusTooManyRequests || attempt >= c.config.MaxRetryAttempts {
+				continue
+			}
+			if req, ok := byID[item.ID]; ok {
+				throttled = append(throttled, req)
+				if itemWait := c.batchItemRetryAfter(item, attempt); itemWait > wait {
+					wait = itemWait
+				}
+			}
+		}
+
+		if len(throttled) == 0 {
+			return nil
+		}
+
+		c.warnSampled("Batch sub-requests throttled, retrying", zap.Int("throttled", len(throttled)), zap.Int("attempt", attempt+1), zap.Duration("wait", wait))
+		if err := c.backoff.Wait(context.Background(), wait); err != nil {
+			return err
+		}
+		requests = throttled
+	}
+}
+
+// batchItemRetryAfter returns how long to wait before resending a throttled sub-request, honouring its
+// Retry-After header when present and otherwise backing off exponentially.
+func (c *Client) batchItemRetryAfter(item BatchResponse, attempt int) time.Duration {
+	header := make(http.Header, len(item.Headers))
+	for key, value := range item.Headers {
+		header.Set(key, value)
+	}
+
+	if wait := ratehandler.ParseRateLimitHeaders(&http.Response{Header: header}, c.Logger()); wait > 0 {
+		return wait
+	}
+
+	return c.retryBackoff(attempt + 1)
+}
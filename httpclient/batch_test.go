@@ -0,0 +1,129 @@
+// httpclient/batch_test.go
+package httpclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestDoBatch(t *testing.T) {
+	var mu sync.Mutex
+	var calls []int
+	throttledOnce := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != BatchEndpoint {
+			t.Errorf("path = %s, want %s", r.URL.Path, BatchEndpoint)
+		}
+
+		var in batchEnvelope
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Errorf("decode batch: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, len(in.Requests))
+
+		var out batchEnvelope
+		// Answer in reverse order to check responses are matched by id.
+		for i := len(in.Requests) - 1; i >= 0; i-- {
+			req := in.Requests[i]
+			item := BatchResponse{ID: req.ID, Status: http.StatusOK, Body: json.RawMessage(fmt.Sprintf(`{"url":%q}`, req.URL))}
+			if req.ID == "3" && !throttledOnce {
+				throttledOnce = true
+				item = BatchResponse{ID: req.ID, Status: http.StatusTooManyRequests, Headers: map[string]string{"Retry-After": "0"}}
+			}
+			out.Responses = append(out.Responses, item)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.MaxRetryAttempts = 2
+	})
+
+	var requests []BatchRequest
+	for i := 0; i < 25; i++ {
+		requests = append(requests, BatchRequest{Method: http.MethodGet, URL: fmt.Sprintf("/users/%d", i)})
+	}
+
+	responses, err := client.DoBatch(requests)
+	if err != nil {
+		t.Fatalf("DoBatch() error = %v", err)
+	}
+
+	if len(responses) != len(requests) {
+		t.Fatalf("responses = %d, want %d", len(responses), len(requests))
+	}
+	for i, resp := range responses {
+		want := fmt.Sprintf(`{"url":"/users/%d"}`, i)
+		if resp.Status != http.StatusOK || string(resp.Body) != want {
+			t.Errorf("response %d = %d %s, want 200 %s", i, resp.Status, resp.Body, want)
+		}
+	}
+
+	// 20 + the throttled retry of 1, then the remaining 5.
+	if fmt.Sprint(calls) != "[20 1 5]" {
+		t.Errorf("batch calls = %v, want [20 1 5]", calls)
+	}
+}
+
+func TestDoBatchMultiStatus(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in batchEnvelope
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Errorf("decode batch: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+
+		var out batchEnvelope
+		for _, req := range in.Requests {
+			switch {
+			case req.ID == "1":
+				out.Responses = append(out.Responses, BatchResponse{ID: req.ID, Status: http.StatusNotFound})
+			case calls == 1:
+				out.Responses = append(out.Responses, BatchResponse{ID: req.ID, Status: http.StatusTooManyRequests, Headers: map[string]string{"Retry-After": "0"}})
+			default:
+				out.Responses = append(out.Responses, BatchResponse{ID: req.ID, Status: http.StatusOK})
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMultiStatus)
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.MaxRetryAttempts = 2
+	})
+
+	responses, err := client.DoBatch([]BatchRequest{
+		{Method: http.MethodGet, URL: "/users/missing"},
+		{Method: http.MethodGet, URL: "/users/throttled"},
+	})
+	if err != nil {
+		t.Fatalf("DoBatch() error = %v, want the 207 handled by DoBatch", err)
+	}
+
+	if responses[0].Status != http.StatusNotFound || responses[1].Status != http.StatusOK {
+		t.Errorf("statuses = %d, %d, want 404 and the retried 200", responses[0].Status, responses[1].Status)
+	}
+	if calls != 2 {
+		t.Errorf("batch calls = %d, want 2 (the throttled item resent)", calls)
+	}
+}
@@ -0,0 +1,69 @@
+// httpclient/body.go
+package httpclient
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.uber.org/zap"
+)
+
+// ErrInvalidRequestBody is returned, wrapping the body's own error, when a request body implementing
+// RequestBodyValidator fails validation. The request is not sent.
+var ErrInvalidRequestBody = errors.New("invalid request body")
+
+// RequestBodyValidator is implemented by request bodies which can check themselves before they are marshalled,
+// giving fast local feedback instead of an opaque 400 from the server. Bodies which don't implement it are sent
+// unchecked.
+type RequestBodyValidator interface {
+	Validate() error
+}
+
+// prepareRequestBody returns the body to send for a request and whether it was gzipped. Raw bodies supplied through
+// DoRequestRaw are sent verbatim; otherwise body is form encoded (DoFormRequest) or, after validation (see
+// RequestBodyValidator), marshalled by the Integration or the encoder chosen with WithRequestContentType, then size
+// checked, logged and, if configured, compressed.
+func (c *Client) prepareRequestBody(method, endpoint string, body interface{}, ro *requestOptions) (io.Reader, bool, error) {
+	if ro.rawBody != nil {
+		return ro.rawBody, false, nil
+	}
+
+	var requestData []byte
+	var err error
+	if ro.formBody != nil {
+		requestData = []byte(ro.formBody.Encode())
+	} else {
+		if validator, ok := body.(RequestBodyValidator); ok {
+			if err := validator.Validate(); err != nil {
+				return nil, false, fmt.Errorf("%w: %w", ErrInvalidRequestBody, err)
+			}
+		}
+
+		if ro.bodyContentType != "" {
+			requestData, err = c.encodeRequestBody(body, ro.bodyContentType)
+		} else {
+			requestData, err = (*c.Integration).PrepRequestBody(body, method, endpoint)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	if err := c.checkRequestBodySize(requestData); err != nil {
+		return nil, false, err
+	}
+	c.logRequestBody(method, endpoint, requestData)
+
+	compressed := c.shouldCompressRequestBody(method, len(requestData))
+	if compressed {
+		uncompressedSize := len(requestData)
+		requestData, err = gzipBody(requestData)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to compress request body: %w", err)
+		}
+		c.Logger().Debugw("Compressed request body", zap.Int("original_bytes", uncompressedSize), zap.Int("compressed_bytes", len(requestData)))
+	}
+
+	return bytes.NewBuffer(requestData), compressed, nil
+}
@@ -0,0 +1,66 @@
+// httpclient/body_test.go
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+type validatedUser struct {
+	Name string `json:"name"`
+}
+
+func (u validatedUser) Validate() error {
+	if u.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+func TestDoRequest_ValidatesBody(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, nil)
+
+	tests := []struct {
+		name         string
+		body         interface{}
+		wantInvalid  bool
+		wantRequests int32
+	}{
+		{name: "invalid body is not sent", body: validatedUser{}, wantInvalid: true, wantRequests: 0},
+		{name: "invalid pointer body is not sent", body: &validatedUser{}, wantInvalid: true, wantRequests: 0},
+		{name: "valid body is sent", body: validatedUser{Name: "a"}, wantRequests: 1},
+		{name: "body without Validate is sent", body: map[string]string{}, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+
+			var out map[string]interface{}
+			resp, err := client.DoRequest(http.MethodPost, "/users", tt.body, &out)
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			if got := errors.Is(err, ErrInvalidRequestBody); got != tt.wantInvalid {
+				t.Errorf("DoRequest() error = %v, want ErrInvalidRequestBody: %v", err, tt.wantInvalid)
+			}
+			if !tt.wantInvalid && err != nil {
+				t.Errorf("DoRequest() error = %v", err)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("requests sent = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
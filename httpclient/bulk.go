@@ -0,0 +1,95 @@
+// httpclient/bulk.go
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// ErrClientClosed is returned by bulk operations started on a client which has been closed.
+var ErrClientClosed = errors.New("client is closed")
+
+// BulkItemResult is the outcome of one request within a bulk operation.
+type BulkItemResult struct {
+	Endpoint string
+	// StatusCode is the status of the final response, or 0 if none was received.
+	StatusCode int
+	// Err is nil when the request succeeded.
+	Err error
+}
+
+// BulkResult collects the per-endpoint outcomes of a bulk operation, in the order the endpoints were given.
+type BulkResult struct {
+	Results []BulkItemResult
+}
+
+// Succeeded returns the results of the requests which completed successfully.
+func (r BulkResult) Succeeded() []BulkItemResult {
+	return r.filter(func(item BulkItemResult) bool { return item.Err == nil })
+}
+
+// Failed returns the results of the requests which failed, each with its error.
+func (r BulkResult) Failed() []BulkItemResult {
+	return r.filter(func(item BulkItemResult) bool { return item.Err != nil })
+}
+
+// filter returns the results for which keep reports true.
+func (r BulkResult) filter(keep func(BulkItemResult) bool) []BulkItemResult {
+	var matched []BulkItemResult
+	for _, item := range r.Results {
+		if keep(item) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+// DoBulkDelete sends a DELETE to every endpoint concurrently, using at most as many workers as the client's
+// concurrency limit, and reports the outcome of each in the returned BulkResult. Individual failures do not stop the
+// remaining deletions; inspect BulkResult.Failed to see which failed and why. The deletions are sent as one
+// DoRequestBatch, so retries and concurrency permits apply as usual. An error is only returned if the operation
+// could not start.
+func (c *Client) DoBulkDelete(endpoints []string) (BulkResult, error) {
+	select {
+	case <-c.done:
+		return BulkResult{}, ErrClientClosed
+	default:
+	}
+
+	items := make([]BatchItem, len(endpoints))
+	for i, endpoint := range endpoints {
+		items[i] = BatchItem{Method: http.MethodDelete, Endpoint: endpoint}
+	}
+
+	result := BulkResult{Results: make([]BulkItemResult, len(endpoints))}
+	for i, item := range c.DoRequestBatch(context.Background(), items) {
+		result.Results[i] = BulkItemResult{Endpoint: endpoints[i], StatusCode: item.StatusCode, Err: item.Err}
+	}
+
+	if failed := len(result.Failed()); failed > 0 {
+		c.Logger().Warnw("Bulk delete completed with failures", zap.Int("total", len(endpoints)), zap.Int("failed", failed))
+	} else {
+		c.Logger().Infow("Bulk delete completed", zap.Int("total", len(endpoints)))
+	}
+
+	return result, nil
+}
+
+// bulkWorkers returns how many requests a bulk operation of n items runs at once: the current concurrency limit
+// when concurrency management is enabled, otherwise MaxConcurrentRequests, and never more than n or less than one.
+func (c *Client) bulkWorkers(n int) int {
+	workers := c.config.MaxConcurrentRequests
+	if c.Concurrency != nil {
+		workers = c.Concurrency.CurrentLimit()
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
@@ -0,0 +1,92 @@
+// httpclient/bulk_test.go
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoBulkDelete(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	var mu sync.Mutex
+	deleted := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if r.Method != http.MethodDelete {
+			t.Errorf("method = %s, want DELETE", r.Method)
+		}
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+
+		mu.Lock()
+		deleted[r.URL.Path] = true
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.MaxConcurrentRequests = 2
+		config.EnableConcurrencyManagement = true
+	})
+
+	endpoints := []string{"/users/1", "/users/missing", "/users/2", "/users/3", "/users/4"}
+	result, err := client.DoBulkDelete(endpoints)
+	if err != nil {
+		t.Fatalf("DoBulkDelete() error = %v", err)
+	}
+
+	if len(result.Results) != len(endpoints) {
+		t.Fatalf("results = %d, want %d", len(result.Results), len(endpoints))
+	}
+	for i, item := range result.Results {
+		if item.Endpoint != endpoints[i] {
+			t.Errorf("result %d endpoint = %q, want %q (input order)", i, item.Endpoint, endpoints[i])
+		}
+	}
+
+	failed := result.Failed()
+	if len(failed) != 1 || failed[0].Endpoint != "/users/missing" {
+		t.Fatalf("Failed() = %+v, want only /users/missing", failed)
+	}
+	if failed[0].StatusCode != http.StatusNotFound || failed[0].Err == nil {
+		t.Errorf("failure = %+v, want a 404 with its error", failed[0])
+	}
+	if got := len(result.Succeeded()); got != 4 {
+		t.Errorf("Succeeded() = %d results, want 4", got)
+	}
+	if len(deleted) != 4 {
+		t.Errorf("server deleted %d resources, want 4", len(deleted))
+	}
+	if got := peak.Load(); got > 2 {
+		t.Errorf("peak concurrent deletes = %d, want at most the concurrency limit of 2", got)
+	}
+}
+
+func TestDoBulkDelete_ClosedClient(t *testing.T) {
+	client := newTestClient(t, "http://192.0.2.1", nil)
+	client.Close()
+
+	if _, err := client.DoBulkDelete([]string{"/users/1"}); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("DoBulkDelete() error = %v, want ErrClientClosed", err)
+	}
+}
@@ -0,0 +1,400 @@
+// httpclient/cache.go
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultCacheMaxEntryBytes is the largest response body ResponseCacheConfig stores when MaxEntryBytes is not set.
+const DefaultCacheMaxEntryBytes = 1 << 20
+
+// DefaultCacheMaxEntries bounds the in-memory cache store when ResponseCacheConfig.MaxEntries is not set.
+const DefaultCacheMaxEntries = 1000
+
+// ResponseCacheConfig enables an HTTP cache for GET requests which follows the Cache-Control rules of RFC 9111
+// (formerly RFC 7234). Responses are stored for their max-age (or s-maxage, or Expires) and served without a network
+// call while fresh; stale entries carrying an ETag or Last-Modified are revalidated with a conditional request and a
+// 304 answer refreshes and serves the stored copy. no-store responses are never stored, no-cache responses are
+// always revalidated, Vary is honoured, and requests which are already conditional bypass the cache so the caller
+// still sees ErrNotModified. Successful unsafe requests (POST, PUT, PATCH, DELETE) evict the stored URL.
+//
+// The cache is the innermost Interceptor, so interceptors in ClientConfig.Interceptors see cache hits too.
+type ResponseCacheConfig struct {
+	// Store holds cached responses. Defaults to an in-memory store; implement CacheStore to share a cache through
+	// an external store such as Redis.
+	Store CacheStore `json:"-"`
+
+	// Shared marks the cache as shared between users, e.g. a Store used by several clients with different
+	// credentials. A shared cache does not store responses marked Cache-Control: private, prefers s-maxage, and only
+	// stores the answer to a request carrying Authorization when it is marked public, s-maxage or must-revalidate.
+	Shared bool `json:"shared"`
+
+	// MaxEntries bounds the default in-memory store. 0 uses DefaultCacheMaxEntries.
+	MaxEntries int `json:"max_entries"`
+
+	// MaxEntryBytes is the largest response body that is stored. 0 uses DefaultCacheMaxEntryBytes.
+	MaxEntryBytes int64 `json:"max_entry_bytes"`
+}
+
+// CacheStore stores cached responses by key. Implementations must be safe for concurrent use.
+type CacheStore interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, entry *CachedResponse)
+	Delete(key string)
+}
+
+// CachedResponse is a stored response. It is plain data so external stores can serialise it.
+type CachedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	// Vary holds the request header values the response varies on, keyed by canonical header name.
+	Vary map[string]string `json:"vary,omitempty"`
+	// StoredAt is when the response was stored or last revalidated.
+	StoredAt time.Time `json:"stored_at"`
+	// Expires is when the response stops being fresh. A response stored already stale is always revalidated.
+	Expires time.Time `json:"expires"`
+}
+
+// MemoryCacheStore is the default CacheStore, holding up to a fixed number of entries in memory. When full, the
+// entry which expires soonest is evicted.
+type MemoryCacheStore struct {
+	maxEntries int
+	entries    map[string]*CachedResponse
+	sync.Mutex
+}
+
+// NewMemoryCacheStore returns an in-memory store holding at most maxEntries responses. A maxEntries of 0 or less
+// uses DefaultCacheMaxEntries.
+func NewMemoryCacheStore(maxEntries int) *MemoryCacheStore {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheMaxEntries
+	}
+	return &MemoryCacheStore{maxEntries: maxEntries, entries: make(map[string]*CachedResponse)}
+}
+
+// Get returns the entry stored under key.
+func (s *MemoryCacheStore) Get(key string) (*CachedResponse, bool) {
+	s.Lock()
+	defer s.Unlock()
+	entry, ok := s.entries[key]
+	return entry, ok
+}
+
+// Set stores entry under key, evicting the soonest expiring entry if the store is full.
+func (s *MemoryCacheStore) Set(key string, entry *CachedResponse) {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, exists := s.entries[key]; !exists && len(s.entries) >= s.maxEntries {
+		var evict string
+		var soonest time.Time
+		for k, e := range s.entries {
+			if evict == "" || e.Expires.Before(soonest) {
+				evict, soonest = k, e.Expires
+			}
+		}
+		delete(s.entries, evict)
+	}
+	s.entries[key] = entry
+}
+
+// Delete removes the entry stored under key.
+func (s *MemoryCacheStore) Delete(key string) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.entries, key)
+}
+
+// responseCache is the Interceptor implementing ResponseCacheConfig.
+type responseCache struct {
+	store         CacheStore
+	shared        bool
+	maxEntryBytes int64
+	logger        func() *zap.SugaredLogger
+	now           func() time.Time
+}
+
+// newResponseCache returns the cache for config, or nil when caching is disabled. logger is called for every entry
+// logged, so a logger installed later by Client.SetLogger is honoured.
+func newResponseCache(config *ResponseCacheConfig, logger func() *zap.SugaredLogger) *responseCache {
+	if config == nil {
+		return nil
+	}
+
+	cache := &responseCache{
+		store:         config.Store,
+		shared:        config.Shared,
+		maxEntryBytes: config.MaxEntryBytes,
+		logger:        logger,
+		now:           time.Now,
+	}
+	if cache.store == nil {
+		cache.store = NewMemoryCacheStore(config.MaxEntries)
+	}
+	if cache.maxEntryBytes <= 0 {
+		cache.maxEntryBytes = DefaultCacheMaxEntryBytes
+	}
+	return cache
+}
+
+// intercept serves GET requests from the cache where possible and stores cacheable responses.
+func (rc *responseCache) intercept(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	key := req.URL.String()
+
+	if req.Method != http.MethodGet {
+		resp, err := next(req)
+		if err == nil && req.Method != http.MethodHead && resp.StatusCode < http.StatusBadRequest {
+			rc.store.Delete(key)
+		}
+		return resp, err
+	}
+
+	requestDirectives := parseCacheControl(req.Header.Get("Cache-Control"))
+	if _, noStore := requestDirectives["no-store"]; noStore || isConditionalRequest(req) {
+		return next(req)
+	}
+
+	entry, ok := rc.store.Get(key)
+	if ok && !entry.matchesVary(req) {
+		ok = false
+	}
+	_, forceRevalidate := requestDirectives["no-cache"]
+
+	if ok && !forceRevalidate && rc.now().Before(entry.Expires) {
+		rc.logger().Debugw("Serving response from cache", zap.String("url", key))
+		return entry.response(req, rc.now()), nil
+	}
+
+	outgoing := req
+	if ok && entry.hasValidator() {
+		outgoing = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			outgoing.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+			outgoing.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := next(outgoing)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && outgoing != req && resp.StatusCode == http.StatusNotModified {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		refreshed := *entry
+		refreshed.Header = entry.Header.Clone()
+		for name, values := range resp.Header {
+			refreshed.Header[name] = values
+		}
+		refreshed.StoredAt = rc.now()
+		refreshed.Expires = refreshed.StoredAt.Add(rc.freshnessLifetime(refreshed.Header))
+		rc.store.Set(key, &refreshed)
+
+		rc.logger().Debugw("Revalidated cached response", zap.String("url", key))
+		return refreshed.response(req, rc.now()), nil
+	}
+
+	return rc.save(key, req, resp)
+}
+
+// save stores resp under key when it is cacheable, returning a response whose body is still readable.
+func (rc *responseCache) save(key string, req *http.Request, resp *http.Response) (*http.Response, error) {
+	if !rc.cacheable(req, resp) {
+		if resp.StatusCode != http.StatusNotModified {
+			rc.store.Delete(key)
+		}
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, rc.maxEntryBytes+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to read response body for caching: %w", err)
+	}
+	if int64(len(body)) > rc.maxEntryBytes {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	now := rc.now()
+	entry := &CachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		Vary:       varyValues(resp.Header, req),
+		StoredAt:   now,
+		Expires:    now.Add(rc.freshnessLifetime(resp.Header)),
+	}
+	rc.store.Set(key, entry)
+
+	return resp, nil
+}
+
+// cacheable reports whether resp, the answer to req, may be stored. A shared cache only stores the answer to an
+// authenticated request when the response explicitly allows it (RFC 9111 section 3.5).
+func (rc *responseCache) cacheable(req *http.Request, resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+
+	directives := parseCacheControl(resp.Header.Get("Cache-Control"))
+	if _, noStore := directives["no-store"]; noStore {
+		return false
+	}
+	if _, private := directives["private"]; private && rc.shared {
+		return false
+	}
+	if rc.shared && req.Header.Get("Authorization") != "" && !sharedWithAuthorization(directives) {
+		return false
+	}
+	for _, name := range resp.Header.Values("Vary") {
+		if strings.TrimSpace(name) == "*" {
+			return false
+		}
+	}
+
+	return rc.freshnessLifetime(resp.Header) > 0 || resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+// sharedWithAuthorization reports whether directives let a shared cache store the answer to a request carrying
+// Authorization: public, s-maxage or must-revalidate.
+func sharedWithAuthorization(directives map[string]string) bool {
+	for _, name := range []string{"public", "s-maxage", "must-revalidate"} {
+		if _, ok := directives[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// freshnessLifetime returns how long a response with header stays fresh from now: s-maxage (shared caches),
+// max-age or Expires, less the Age it already had. no-cache makes it stale immediately.
+func (rc *responseCache) freshnessLifetime(header http.Header) time.Duration {
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	if _, noCache := directives["no-cache"]; noCache {
+		return 0
+	}
+
+	var lifetime time.Duration
+	if seconds, ok := directiveSeconds(directives, "s-maxage"); ok && rc.shared {
+		lifetime = seconds
+	} else if seconds, ok := directiveSeconds(directives, "max-age"); ok {
+		lifetime = seconds
+	} else if expires, err := http.ParseTime(header.Get("Expires")); err == nil {
+		date, err := http.ParseTime(header.Get("Date"))
+		if err != nil {
+			date = rc.now()
+		}
+		lifetime = expires.Sub(date)
+	}
+
+	if age, err := strconv.Atoi(header.Get("Age")); err == nil && age > 0 {
+		lifetime -= time.Duration(age) * time.Second
+	}
+	if lifetime < 0 {
+		return 0
+	}
+	return lifetime
+}
+
+// response builds a response for req from the stored entry.
+func (e *CachedResponse) response(req *http.Request, now time.Time) *http.Response {
+	header := e.Header.Clone()
+	header.Set("Age", strconv.Itoa(int(now.Sub(e.StoredAt).Seconds())))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// hasValidator reports whether the entry can be revalidated with a conditional request.
+func (e *CachedResponse) hasValidator() bool {
+	return e.Header.Get("ETag") != "" || e.Header.Get("Last-Modified") != ""
+}
+
+// matchesVary reports whether req carries the same values as the stored request for every header in Vary.
+func (e *CachedResponse) matchesVary(req *http.Request) bool {
+	for name, value := range e.Vary {
+		if req.Header.Get(name) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// varyValues records req's values for the headers named by the response's Vary header.
+func varyValues(header http.Header, req *http.Request) map[string]string {
+	var values map[string]string
+	for _, field := range header.Values("Vary") {
+		for _, name := range strings.Split(field, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if values == nil {
+				values = make(map[string]string)
+			}
+			values[name] = req.Header.Get(name)
+		}
+	}
+	return values
+}
+
+// isConditionalRequest reports whether the caller made req conditional itself.
+func isConditionalRequest(req *http.Request) bool {
+	return req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != ""
+}
+
+// parseCacheControl splits a Cache-Control header into lower-cased directives and their unquoted values.
+func parseCacheControl(value string) map[string]string {
+	directives := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, arg, _ := strings.Cut(part, "=")
+		directives[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(arg), `"`)
+	}
+	return directives
+}
+
+// directiveSeconds returns a delta-seconds directive such as max-age as a duration.
+func directiveSeconds(directives map[string]string, name string) (time.Duration, bool) {
+	value, ok := directives[name]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,261 @@
+// httpclient/cache_test.go
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// cacheTestServer answers every request with the given headers and a JSON body naming the request count, so tests
+// can tell cached responses from fresh ones. It honours If-None-Match against its ETag header.
+func cacheTestServer(t *testing.T, headers map[string]string) (*httptest.Server, *atomic.Int32) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		for name, value := range headers {
+			w.Header().Set(name, value)
+		}
+		if etag := headers["ETag"]; etag != "" && r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hit":` + strconv.Itoa(int(n)) + `}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+type cacheTestBody struct {
+	Hit int `json:"hit"`
+}
+
+func getCached(t *testing.T, client *Client, opts ...RequestOption) (cacheTestBody, *http.Response) {
+	t.Helper()
+	var out cacheTestBody
+	resp, err := client.DoRequest(http.MethodGet, "/resource", nil, &out, opts...)
+	if err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	resp.Body.Close()
+	return out, resp
+}
+
+func TestResponseCache(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		shared   bool
+		wantHits int32
+	}{
+		{name: "fresh response served from cache", headers: map[string]string{"Cache-Control": "max-age=60"}, wantHits: 1},
+		{name: "no-store is never cached", headers: map[string]string{"Cache-Control": "no-store, max-age=60"}, wantHits: 2},
+		{name: "no freshness or validator is not cached", headers: map[string]string{}, wantHits: 2},
+		{name: "private is cached by a private cache", headers: map[string]string{"Cache-Control": "private, max-age=60"}, wantHits: 1},
+		{name: "private is not cached by a shared cache", headers: map[string]string{"Cache-Control": "private, max-age=60"}, shared: true, wantHits: 2},
+		{name: "authenticated response is not cached by a shared cache", headers: map[string]string{"Cache-Control": "max-age=60"}, shared: true, wantHits: 2},
+		{name: "public authenticated response is cached by a shared cache", headers: map[string]string{"Cache-Control": "public, max-age=60"}, shared: true, wantHits: 1},
+		{name: "s-maxage authenticated response is cached by a shared cache", headers: map[string]string{"Cache-Control": "s-maxage=60"}, shared: true, wantHits: 1},
+		{name: "must-revalidate authenticated response is cached by a shared cache", headers: map[string]string{"Cache-Control": "must-revalidate, max-age=60"}, shared: true, wantHits: 1},
+		{name: "age already used up", headers: map[string]string{"Cache-Control": "max-age=60", "Age": "60"}, wantHits: 2},
+		{name: "vary star is not cached", headers: map[string]string{"Cache-Control": "max-age=60", "Vary": "*"}, wantHits: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, hits := cacheTestServer(t, tt.headers)
+			client := newTestClient(t, server.URL, func(config *ClientConfig) {
+				config.ResponseCache = &ResponseCacheConfig{Shared: tt.shared}
+			})
+
+			first, _ := getCached(t, client)
+			second, _ := getCached(t, client)
+
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("server hits = %d, want %d", got, tt.wantHits)
+			}
+			if first.Hit != 1 || (tt.wantHits == 1 && second.Hit != 1) {
+				t.Errorf("decoded hits = %d, %d, want the cached body to be served", first.Hit, second.Hit)
+			}
+		})
+	}
+}
+
+func TestResponseCache_RevalidatesStaleEntries(t *testing.T) {
+	var conditional atomic.Int32
+	server, hits := cacheTestServer(t, map[string]string{"Cache-Control": "no-cache", "ETag": `"v1"`})
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.ResponseCache = &ResponseCacheConfig{}
+		config.OnRequest = func(req *http.Request) {
+			if req.Header.Get("If-None-Match") != "" {
+				conditional.Add(1)
+			}
+		}
+	})
+
+	first, _ := getCached(t, client)
+	second, resp := getCached(t, client)
+
+	if hits.Load() != 2 {
+		t.Errorf("server hits = %d, want 2 (one fetch, one revalidation)", hits.Load())
+	}
+	if conditional.Load() != 0 {
+		t.Error("the caller's request was made conditional, want only the cache's outgoing copy to be")
+	}
+	if first.Hit != 1 || second.Hit != 1 {
+		t.Errorf("decoded hits = %d, %d, want the revalidated cached body", first.Hit, second.Hit)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("revalidated status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestResponseCache_Vary(t *testing.T) {
+	server, hits := cacheTestServer(t, map[string]string{"Cache-Control": "max-age=60", "Vary": "Accept-Language"})
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.ResponseCache = &ResponseCacheConfig{}
+	})
+
+	getCached(t, client, WithHeaders(map[string]string{"Accept-Language": "en"}))
+	getCached(t, client, WithHeaders(map[string]string{"Accept-Language": "en"}))
+	getCached(t, client, WithHeaders(map[string]string{"Accept-Language": "fr"}))
+
+	if got := hits.Load(); got != 2 {
+		t.Errorf("server hits = %d, want 2 (one per language)", got)
+	}
+}
+
+func TestResponseCache_UnsafeMethodInvalidates(t *testing.T) {
+	server, hits := cacheTestServer(t, map[string]string{"Cache-Control": "max-age=60"})
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.ResponseCache = &ResponseCacheConfig{}
+	})
+
+	getCached(t, client)
+
+	var out cacheTestBody
+	resp, err := client.DoRequest(http.MethodPut, "/resource", map[string]string{}, &out)
+	if err != nil {
+		t.Fatalf("DoRequest(PUT) error = %v", err)
+	}
+	resp.Body.Close()
+
+	getCached(t, client)
+
+	if got := hits.Load(); got != 3 {
+		t.Errorf("server hits = %d, want 3 (the PUT evicts the cached GET)", got)
+	}
+}
+
+func TestResponseCache_CallerConditionalRequestBypassesCache(t *testing.T) {
+	server, _ := cacheTestServer(t, map[string]string{"Cache-Control": "max-age=60", "ETag": `"v1"`})
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.ResponseCache = &ResponseCacheConfig{}
+	})
+
+	getCached(t, client)
+
+	var out cacheTestBody
+	resp, err := client.DoRequest(http.MethodGet, "/resource", nil, &out, WithHeaders(map[string]string{"If-None-Match": `"v1"`}))
+	if !errors.Is(err, ErrNotModified) {
+		t.Fatalf("DoRequest() error = %v, want ErrNotModified", err)
+	}
+	resp.Body.Close()
+}
+
+// recordingCacheStore is a CacheStore standing in for an external store.
+type recordingCacheStore struct {
+	mu      sync.Mutex
+	entries map[string]*CachedResponse
+	sets    int
+}
+
+func (s *recordingCacheStore) Get(key string) (*CachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	return entry, ok
+}
+
+func (s *recordingCacheStore) Set(key string, entry *CachedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = entry
+	s.sets++
+}
+
+func (s *recordingCacheStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
+
+func TestResponseCache_CustomStore(t *testing.T) {
+	server, hits := cacheTestServer(t, map[string]string{"Cache-Control": "max-age=60"})
+	store := &recordingCacheStore{entries: make(map[string]*CachedResponse)}
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.ResponseCache = &ResponseCacheConfig{Store: store}
+	})
+
+	getCached(t, client)
+	getCached(t, client)
+
+	if store.sets != 1 || hits.Load() != 1 {
+		t.Errorf("store sets = %d, server hits = %d, want 1 and 1", store.sets, hits.Load())
+	}
+}
+
+func TestResponseCache_freshnessLifetime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		shared bool
+		want   time.Duration
+	}{
+		{name: "max-age", header: http.Header{"Cache-Control": {"max-age=120"}}, want: 2 * time.Minute},
+		{name: "s-maxage ignored by private cache", header: http.Header{"Cache-Control": {"max-age=120, s-maxage=10"}}, want: 2 * time.Minute},
+		{name: "s-maxage preferred by shared cache", header: http.Header{"Cache-Control": {"max-age=120, s-maxage=10"}}, shared: true, want: 10 * time.Second},
+		{name: "age is subtracted", header: http.Header{"Cache-Control": {"max-age=120"}, "Age": {"20"}}, want: 100 * time.Second},
+		{name: "expires relative to date", header: http.Header{"Expires": {now.Add(time.Hour).Format(http.TimeFormat)}, "Date": {now.Format(http.TimeFormat)}}, want: time.Hour},
+		{name: "no-cache", header: http.Header{"Cache-Control": {"no-cache, max-age=120"}}, want: 0},
+		{name: "malformed max-age", header: http.Header{"Cache-Control": {"max-age=soon"}}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newResponseCache(&ResponseCacheConfig{Shared: tt.shared}, zap.NewNop().Sugar)
+			cache.now = func() time.Time { return now }
+			if got := cache.freshnessLifetime(tt.header); got != tt.want {
+				t.Errorf("freshnessLifetime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryCacheStore_EvictsSoonestExpiring(t *testing.T) {
+	store := NewMemoryCacheStore(2)
+	now := time.Now()
+
+	store.Set("a", &CachedResponse{Expires: now.Add(time.Hour)})
+	store.Set("b", &CachedResponse{Expires: now.Add(time.Minute)})
+	store.Set("c", &CachedResponse{Expires: now.Add(2 * time.Hour)})
+
+	if _, ok := store.Get("b"); ok {
+		t.Error("entry b, expiring soonest, was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := store.Get(key); !ok {
+			t.Errorf("entry %s was evicted, want it kept", key)
+		}
+	}
+}
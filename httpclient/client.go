@@ -13,9 +13,13 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/deploymenttheory/go-api-http-client/concurrency"
+	"github.com/deploymenttheory/go-api-http-client/ratehandler"
+	"github.com/deploymenttheory/go-api-http-client/response"
 	"go.uber.org/zap"
 )
 
@@ -43,17 +47,51 @@ type Client struct {
 	config      *ClientConfig
 	Integration *APIIntegration
 	http        HTTPExecutor
-	Sugar       *zap.SugaredLogger
+	Sugar       *zap.SugaredLogger // Logger the client was built with; see Logger and SetLogger.
 	Concurrency *concurrency.ConcurrencyHandler
+	backoff     *ratehandler.BackoffScheduler
+	dnsCache    *dnsCache
+	failover    *failoverState
+	hostPolicy  *hostPolicy
+	logSampler  *logSampler
+	spacer      *requestSpacer
+	scopedAuth  []scopedTokenSource
+	oauth2Auth  *oauth2IntegrationSource
+
+	// logger holds the logger installed by SetLogger, read atomically so it can be swapped during requests.
+	logger atomic.Pointer[zap.SugaredLogger]
+
+	// interceptors is ClientConfig.Interceptors followed by the response cache, if enabled.
+	interceptors []Interceptor
+
+	tokenLock sync.Mutex
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
 }
 
 // Options/Variables for Client
 type ClientConfig struct {
 	// Interface which implements the APIIntegration patterns. Integration handles all server/endpoint specific configuration, auth and vars.
-	Integration APIIntegration
+	Integration APIIntegration `json:"-"`
 
 	// Sugar is the logger from Zap.
-	Sugar *zap.SugaredLogger
+	Sugar *zap.SugaredLogger `json:"-"`
+
+	// LogSamplingInterval coalesces the warnings logged on every retry, backoff and rate limit wait: the first
+	// occurrence of each message per interval is logged, and the count of suppressed repeats is attached to the next
+	// one. 0 disables sampling so every attempt is logged.
+	LogSamplingInterval time.Duration `json:"log_sampling_interval"`
+
+	// LogOutputPaths and LogErrorOutputPaths set where the default logger writes its entries and its internal errors,
+	// e.g. []string{"stderr", "/var/log/api-client.log"}. They default to stderr and only apply when Sugar is nil.
+	// Build fails if a path cannot be opened for writing.
+	LogOutputPaths      []string `json:"log_output_paths"`
+	LogErrorOutputPaths []string `json:"log_error_output_paths"`
+
+	// DisableLogging silences all of the client's internal logging by replacing Sugar with a no-op logger, for
+	// applications such as CLIs which manage their own output.
+	DisableLogging bool `json:"disable_logging"`
 
 	// Wether or not empty values will be set or an error thrown for missing items.
 	PopulateDefaultValues bool
@@ -61,6 +99,60 @@ type ClientConfig struct {
 	// HideSenitiveData controls if sensitive data will be visible in logs. Debug option which should be True in production use.
 	HideSensitiveData bool `json:"hide_sensitive_data"`
 
+	// UserAgent identifies the consuming product, e.g. "acme-sync/2.3.1 (build 815)". The User-Agent header becomes
+	// "<UserAgent> go-api-http-client/<version>". When unset the integration's User-Agent, if any, is kept.
+	UserAgent string `json:"user_agent"`
+
+	// DefaultAcceptLanguage is sent as the Accept-Language header (e.g. "fr-FR, fr;q=0.9, en;q=0.5") on requests the
+	// integration has not already localised, so APIs that localise content and error messages answer in that locale.
+	DefaultAcceptLanguage string `json:"default_accept_language"`
+
+	// RequestIDHeader names the header carrying each request's id, which also appears as request_id on the request's
+	// log lines. Defaults to DefaultRequestIDHeader ("X-Request-ID").
+	RequestIDHeader string `json:"request_id_header"`
+
+	// BaseURLOverride, e.g. "http://localhost:8443", replaces the Integration's FQDN and URL construction: relative
+	// endpoints are appended to it verbatim (after BasePath). Use it for mock servers, httptest servers in integration
+	// tests and self-hosted deployments on non-standard hosts or ports.
+	BaseURLOverride string `json:"base_url_override"`
+
+	// XMLRootNamespace, if set, is declared as the default namespace (xmlns) of the root element of XML request bodies
+	// encoded by the client (see WithRequestContentType), e.g. "http://www.w3.org/2005/Atom".
+	XMLRootNamespace string `json:"xml_root_namespace"`
+
+	// BasePath is prepended to every relative endpoint, e.g. "/api/v3" turns "/users" into "/api/v3/users".
+	// Endpoints given as absolute URLs are sent verbatim.
+	BasePath string `json:"base_path"`
+
+	// MaxRequestBodyBytes rejects marshalled request bodies larger than this many bytes before they are sent.
+	// 0 means unlimited. Recommended in production alongside MaxResponseBodyBytes. Multipart uploads are not limited.
+	MaxRequestBodyBytes int64 `json:"max_request_body_bytes"`
+
+	// MaxResponseBodyBytes fails reads of response bodies larger than this many bytes with a ResponseTooLargeError,
+	// protecting the process from a server streaming an enormous payload. 0 means unlimited. Recommended in production.
+	MaxResponseBodyBytes int64 `json:"max_response_body_bytes"`
+
+	// MaxErrorBodyBytes caps how much of an error response body is kept on response.APIError (RawResponse and RawBody).
+	// 0 uses response.DefaultMaxRawErrorBodyBytes.
+	MaxErrorBodyBytes int `json:"max_error_body_bytes"`
+
+	// CompressRequestBody gzips POST, PUT and PATCH bodies larger than CompressRequestBodyThreshold and sends them with
+	// "Content-Encoding: gzip". Opt-in, as not every server accepts compressed requests. Multipart uploads are never compressed.
+	CompressRequestBody bool `json:"compress_request_body"`
+
+	// CompressRequestBodyThreshold is the body size, in bytes, above which CompressRequestBody applies.
+	// 0 uses DefaultCompressRequestBodyThreshold.
+	CompressRequestBodyThreshold int `json:"compress_request_body_threshold"`
+
+	// MultipartChunkSize is the size, in bytes, of the chunks DoMultiPartRequest reads and streams files in.
+	// 0 uses DefaultMultipartChunkSize (8 MB). Values below MinMultipartChunkSize (256 KB) are rejected, and values above
+	// the smallest provider maximum (Azure block blobs, 100 MB) are accepted with a warning.
+	MultipartChunkSize int64 `json:"multipart_chunk_size"`
+
+	// MaxLoggedBodyBytes is the largest request/response payload written to debug logs in full. Larger payloads are
+	// logged as a size summary. 0 uses DefaultMaxLoggedBodyBytes. Payloads are never logged when HideSensitiveData is set.
+	MaxLoggedBodyBytes int `json:"max_logged_body_bytes"`
+
 	// CustomCookies allows implementation of persistent, session wide cookies.
 	CustomCookies []*http.Cookie
 
@@ -80,51 +172,262 @@ type ClientConfig struct {
 	// more sensible to replace the token rather then carry on using it.
 	TokenRefreshBufferPeriod time.Duration
 
+	// OAuth2, when set, obtains the Authorization token with the OAuth2 client credentials grant through
+	// golang.org/x/oauth2 instead of the Integration's own token handling. The Integration still applies its request
+	// params. Tokens are renewed TokenRefreshBufferPeriod before expiry. Unset keeps the Integration's auth.
+	OAuth2 *OAuth2ClientCredentials `json:"oauth2"`
+
+	// ScopedTokenSources authenticates requests whose URL path starts with a key (e.g. "/beta/security/") with that
+	// TokenSource instead of the Integration, for APIs with several token audiences. Keys containing "://" are
+	// matched against the full URL instead. The longest matching key wins; unmatched requests use the Integration.
+	ScopedTokenSources map[string]TokenSource `json:"-"`
+
+	// ProactiveTokenRefresh starts a background goroutine which refreshes the token TokenRefreshBufferPeriod before it
+	// expires, so requests rarely wait on token acquisition. Integrations implementing TokenExpiryReporter are refreshed
+	// just in time, others are checked every half buffer period. Stop it with Client.Close.
+	ProactiveTokenRefresh bool `json:"proactive_token_refresh"`
+
 	// TotalRetryDuration // TODO maybe this should be called context?
 	TotalRetryDuration time.Duration
 
+	// RequestDeadline bounds an entire DoRequest call, including every attempt, retry backoff and failover, whereas
+	// CustomTimeout only limits each attempt. When it passes the call is abandoned immediately, even mid-backoff, with
+	// ErrRequestDeadlineExceeded. 0 means no overall deadline.
+	RequestDeadline time.Duration `json:"request_deadline"`
+
 	// EnableCustomRedirectLogic allows the client to follow redirections when they're returned from a request.
 	// Toggleable for debug reasons only
-	CustomRedirectPolicy *func(req *http.Request, via []*http.Request) error
+	CustomRedirectPolicy *func(req *http.Request, via []*http.Request) error `json:"-"`
 
 	// MaxRedirects is the maximum amount of redirects the client will follow before throwing an error.
 	MaxRedirects int `json:"max_redirects"`
 
+	// SuccessStatusCodes lists the status codes decoded into out as a success, e.g. [{200, 299}] to treat
+	// unfollowed redirects as errors, or [{200, 206}, {208, 299}] to reject 207 Multi-Status. Every other status
+	// goes through error handling and retry classification. Empty means every 2xx and 3xx code.
+	SuccessStatusCodes []StatusCodeRange `json:"success_status_codes"`
+
 	// EnableConcurrencyManagement when false bypasses any concurrency management to allow for a simpler request flow.
+	// Concurrency is evaluated and adjusted inline after each response rather than by a background goroutine, so
+	// neither setting starts one and one-shot programs leak nothing either way.
 	EnableConcurrencyManagement bool `json:"enable_concurrency_management"`
 
-	// MandatoryRequestDelay is a short, usually sub 0.5 second, delay after every request as to not overwhelm an endpoint.
+	// ResponseTimeSmoothing selects how concurrency management averages response times when deciding to scale:
+	// the default sliding window of recent samples reacts fast but is noisy at high request rates, while
+	// concurrency.ResponseTimeSmoothingEMA is smoother but slower to notice a sustained slowdown.
+	ResponseTimeSmoothing concurrency.ResponseTimeSmoothing `json:"response_time_smoothing"`
+
+	// ResponseTimeEMAAlpha is the weight, between 0 and 1, given to each new response time by the EMA smoothing.
+	// Smaller values are more stable. 0 uses concurrency.DefaultResponseTimeEMAAlpha.
+	ResponseTimeEMAAlpha float64 `json:"response_time_ema_alpha"`
+
+	// MandatoryRequestDelay is the minimum spacing, usually sub 0.5 second, between consecutive requests to the same host
+	// as to not overwhelm an endpoint. Back-to-back requests wait out the remainder; a lone request is sent immediately.
 	// Can be set to nothing if you want to be lightning fast!
 	MandatoryRequestDelay time.Duration
 
+	// MandatoryRequestDelayJitter randomly spreads MandatoryRequestDelay by up to this fraction of itself in either
+	// direction, e.g. 0.25 for ±25%, so concurrent clients don't all fire at the same cadence. 0 disables jitter.
+	MandatoryRequestDelayJitter float64 `json:"mandatory_request_delay_jitter"`
+
 	// RetryEligiableRequests when false bypasses any retry logic for a simpler request flow.
 	RetryEligiableRequests bool `json:"retry_eligiable_requests"`
 
-	HTTPExecutor HTTPExecutor
+	// RetryMinDelay is the shortest time a retry backoff will sleep. The exponential backoff already starts at 100ms;
+	// set this to raise the floor for APIs which need more breathing room. It cannot exceed
+	// ratehandler.MaxBackoffDelay. 0 keeps the default curve.
+	RetryMinDelay time.Duration `json:"retry_min_delay"`
+
+	// MaxConcurrentBackoffs caps how many requests may sleep in a retry backoff at once. Requests which need to back off
+	// while the cap is reached fail fast with ratehandler.ErrRetryCapacityExceeded. 0 means unlimited.
+	MaxConcurrentBackoffs int `json:"max_concurrent_backoffs"`
+
+	// SLAThreshold is the end-to-end request duration above which OnSLABreach is invoked. 0 disables the check
+	// unless a per-endpoint threshold applies.
+	SLAThreshold time.Duration `json:"sla_threshold"`
+
+	// SLAEndpointThresholds overrides SLAThreshold for specific endpoints, keyed by the endpoint as passed to DoRequest.
+	SLAEndpointThresholds map[string]time.Duration `json:"sla_endpoint_thresholds"`
+
+	// OnSLABreach is called, in its own goroutine, whenever a completed request takes longer than its SLA threshold.
+	OnSLABreach func(endpoint string, duration, threshold time.Duration) `json:"-"`
+
+	// OnRequest, when set, is called with a copy of every request just before it is sent. The copy's body may be read freely.
+	OnRequest func(*http.Request) `json:"-"`
+
+	// OnResponse, when set, is called with every response just after it is received. The body is buffered so the hook
+	// may read it without affecting unmarshalling. Buffering only happens when the hook is set.
+	OnResponse func(*http.Response) `json:"-"`
+
+	// RequestSigner, when set, signs every request as the last step before it is sent. See RequestSigner.
+	RequestSigner RequestSigner `json:"-"`
+
+	// Interceptors wrap the sending of every request, the first being outermost. See Interceptor for where the
+	// chain sits relative to concurrency permits, hooks and retries.
+	Interceptors []Interceptor `json:"-"`
+
+	// ResponseCache, when set, caches GET responses according to their Cache-Control headers. See ResponseCacheConfig.
+	ResponseCache *ResponseCacheConfig `json:"response_cache"`
+
+	// AsyncOperations, when set, follows 202 Accepted responses by polling the operation's status monitor until it
+	// completes and returning the final resource. See AsyncOperationConfig.
+	AsyncOperations *AsyncOperationConfig `json:"async_operations"`
+
+	// HealthDegradedLatency is the CheckHealth latency above which an endpoint is reported Degraded.
+	// 0 uses DefaultHealthDegradedLatency.
+	HealthDegradedLatency time.Duration `json:"health_degraded_latency"`
+
+	// HealthDownLatency is the CheckHealth latency above which an endpoint is reported Down; probes are also
+	// abandoned after this long. 0 uses DefaultHealthDownLatency.
+	HealthDownLatency time.Duration `json:"health_down_latency"`
+
+	// MaxPages caps how many pages DoRequestAllPages follows before giving up with ErrMaxPagesExceeded.
+	// 0 uses DefaultMaxPages.
+	MaxPages int `json:"max_pages"`
+
+	// EnvelopeDecoder optionally flattens enveloped JSON responses (e.g. response.HALDecoder, response.JSONAPIDecoder)
+	// before they are unmarshalled. Can be overridden per request with WithEnvelopeDecoder.
+	EnvelopeDecoder response.EnvelopeDecoder `json:"-"`
+
+	// TLS configures client certificates (mutual TLS), a custom CA bundle and verification for the default transport.
+	TLS *TLSConfig `json:"tls"`
+
+	// Proxy configures the forward proxy for the default transport. When nil, HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	// from the environment are honoured.
+	Proxy *ProxyConfig `json:"proxy"`
+
+	// MaxIdleConns limits idle (keep-alive) connections across all hosts. 0 keeps Go's default of 100.
+	MaxIdleConns int `json:"max_idle_conns"`
+
+	// MaxIdleConnsPerHost limits idle connections kept per host. 0 defaults to MaxConcurrentRequests so the pool
+	// matches the client's concurrency, or Go's default of 2 when that isn't set.
+	MaxIdleConnsPerHost int `json:"max_idle_conns_per_host"`
+
+	// MaxConnsPerHost limits the total connections per host, including those in use. 0 means unlimited.
+	MaxConnsPerHost int `json:"max_conns_per_host"`
+
+	// IdleConnTimeout is how long an idle connection remains in the pool. 0 keeps Go's default of 90 seconds.
+	IdleConnTimeout time.Duration `json:"idle_conn_timeout"`
+
+	// AddressFamily forces connections over IPv4 (AddressFamilyIPv4Only) or IPv6 (AddressFamilyIPv6Only), e.g. to work
+	// around a broken route in a dual-stack environment. Defaults to AddressFamilyAuto.
+	AddressFamily AddressFamily `json:"address_family"`
+
+	// ForceHTTP1 restricts the default transport to HTTP/1.1, e.g. when a broken intermediary mishandles HTTP/2.
+	// By default HTTP/2 is negotiated over TLS. Cannot be combined with EnableH2C.
+	ForceHTTP1 bool `json:"force_http1"`
+
+	// EnableH2C sends cleartext ("http://") requests as HTTP/2 without TLS (h2c, prior knowledge), for internal
+	// services which speak it. TLS requests are unaffected.
+	EnableH2C bool `json:"enable_h2c"`
+
+	// FailoverDomains is an ordered list of base URLs (e.g. "https://us.api.example.com", "https://eu.api.example.com")
+	// serving the same API. When a request fails with a connection error, or a 5xx after retries, it is resent to the
+	// next domain in the list. Relative endpoints have their scheme and host replaced with the selected domain.
+	// Entries are normalised at Build: whitespace and trailing slashes are trimmed and a bare host defaults to https.
+	FailoverDomains []string `json:"failover_domains"`
+
+	// AllowedHosts, if set, restricts requests and redirects to these hosts. An entry of the form "*.example.com"
+	// allows any subdomain. Recommended for clients which follow redirects from untrusted servers.
+	AllowedHosts []string `json:"allowed_hosts"`
+
+	// DisallowedHostCIDRs rejects requests and redirects to hosts resolving into these ranges, e.g. "169.254.0.0/16"
+	// (cloud metadata endpoints) or "10.0.0.0/8". Recommended alongside AllowedHosts to guard against SSRF. The
+	// transport the client builds also checks the address of every connection it dials, so a host re-resolving
+	// after the check (DNS rebinding) is refused too; a supplied Transport or HTTPExecutor only gets the check.
+	DisallowedHostCIDRs []string `json:"disallowed_host_cidrs"`
+
+	// FailoverPolicy selects the domain each request starts with: FailoverPolicySticky (default) stays on the last
+	// domain which answered, FailoverPolicyPrimaryFirst always tries the first domain again.
+	FailoverPolicy FailoverPolicy `json:"failover_policy"`
+
+	// ReResolveOnConnectionError, when a request to a host fails with a connection level error, flushes the client's
+	// cached DNS entry for that host, closes idle pooled connections and retries idempotent requests once over a freshly
+	// resolved connection. This speeds up recovery when a backend moves to a new IP address (e.g. blue/green failover).
+	ReResolveOnConnectionError bool `json:"re_resolve_on_connection_error"`
+
+	// Resolver resolves host names when ReResolveOnConnectionError is enabled. Defaults to net.DefaultResolver.
+	Resolver HostResolver `json:"-"`
+
+	// DNSCacheTTL is how long resolved addresses are reused when ReResolveOnConnectionError is enabled.
+	// 0 uses DefaultDNSCacheTTL.
+	DNSCacheTTL time.Duration `json:"dns_cache_ttl"`
+
+	// Transport, when set, is used by the default HTTPExecutor instead of building a new transport, allowing several
+	// clients to share one connection pool (see ClientGroup). Transport level options in this config (TLS, AddressFamily,
+	// connection pool limits) are then ignored in favour of the shared transport's own settings.
+	Transport *http.Transport `json:"-"`
+
+	// HTTPExecutor replaces the default http.Client wrapper. When nil, Build constructs a ProdExecutor around a transport
+	// configured from this config. Transport level options (e.g. TLS) are not applied to a supplied executor.
+	HTTPExecutor HTTPExecutor `json:"-"`
 }
 
 // BuildClient creates a new HTTP client with the provided configuration.
 func (c *ClientConfig) Build() (*Client, error) {
+	if c.DisableLogging {
+		c.Sugar = zap.NewNop().Sugar()
+	}
+
 	if c.Sugar == nil {
-		zapLogger, err := zap.NewProduction()
+		zapLogger, err := c.defaultLogger()
 		if err != nil {
 			return nil, err
 		}
 
 		c.Sugar = zapLogger.Sugar()
 		c.Sugar.Info("No logger provided. Defaulting to Sugared Zap Production Logger")
+	} else if len(c.LogOutputPaths) > 0 || len(c.LogErrorOutputPaths) > 0 {
+		c.Sugar.Warn("LogOutputPaths and LogErrorOutputPaths are ignored when a logger is supplied")
 	}
 
 	c.Sugar.Debug("validating configuration")
 
 	err := c.validateClientConfig()
 	if err != nil {
-		return nil, fmt.Errorf("invalid configuration: %v", err)
+		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
 	c.Sugar.Debug("configuration valid")
 
+	if c.MultipartChunkSize > maxProviderChunkSize {
+		c.Sugar.Warnw("Multipart chunk size exceeds Azure Blob Storage's maximum block size",
+			zap.Int64("multipart_chunk_size", c.MultipartChunkSize),
+			zap.Int64("provider_max", maxProviderChunkSize))
+	}
+
+	policy, err := newHostPolicy(c.AllowedHosts, c.DisallowedHostCIDRs, c.Resolver)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
 	httpClient := c.HTTPExecutor
+	var resolverCache *dnsCache
+	if httpClient == nil {
+		transport := c.Transport
+		if transport == nil {
+			if c.ReResolveOnConnectionError {
+				resolverCache = newDNSCache(c.Resolver, c.DNSCacheTTL)
+			}
+
+			transport, err = c.buildTransport(resolverCache, policy)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build transport: %v", err)
+			}
+		} else {
+			c.Sugar.Debug("using shared transport")
+		}
+
+		httpClient = &ProdExecutor{Client: &http.Client{
+			Transport: transport,
+			Timeout:   c.CustomTimeout,
+		}}
+	} else {
+		c.Sugar.Debug("using supplied HTTPExecutor")
+		if c.TLS != nil {
+			c.Sugar.Warn("TLS configuration is ignored when a custom HTTPExecutor is supplied")
+		}
+	}
 
 	cookieJar, err := cookiejar.New(nil)
 	if err != nil {
@@ -133,7 +436,10 @@ func (c *ClientConfig) Build() (*Client, error) {
 
 	httpClient.SetCookieJar(cookieJar)
 
-	if c.CustomRedirectPolicy != nil {
+	if policy != nil {
+		redirectPolicy := policy.redirectPolicy(c.CustomRedirectPolicy)
+		httpClient.SetRedirectPolicy(&redirectPolicy)
+	} else if c.CustomRedirectPolicy != nil {
 		httpClient.SetRedirectPolicy(c.CustomRedirectPolicy)
 	}
 
@@ -146,6 +452,29 @@ func (c *ClientConfig) Build() (*Client, error) {
 			c.Sugar,
 			concurrencyMetrics,
 		)
+		concurrencyHandler.SetResponseTimeSmoothing(c.ResponseTimeSmoothing, c.ResponseTimeEMAAlpha)
+	}
+
+	var failover *failoverState
+	if len(c.FailoverDomains) > 0 {
+		failover, err = newFailoverState(c.FailoverDomains, c.FailoverPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid configuration: %w", err)
+		}
+	}
+
+	var oauth2Auth *oauth2IntegrationSource
+	if c.OAuth2 != nil {
+		var tokenClient *http.Client
+		if executor, ok := httpClient.(*ProdExecutor); ok {
+			tokenClient = executor.Client
+		}
+
+		source, err := NewClientCredentialsTokenSource(*c.OAuth2, c.TokenRefreshBufferPeriod, tokenClient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid configuration: %w", err)
+		}
+		oauth2Auth = &oauth2IntegrationSource{integration: c.Integration, oauth2: source}
 	}
 
 	client := &Client{
@@ -154,6 +483,25 @@ func (c *ClientConfig) Build() (*Client, error) {
 		config:      c,
 		Sugar:       c.Sugar,
 		Concurrency: concurrencyHandler,
+		backoff:     ratehandler.NewBackoffScheduler(c.MaxConcurrentBackoffs),
+		dnsCache:    resolverCache,
+		failover:    failover,
+		hostPolicy:  policy,
+		logSampler:  newLogSampler(c.LogSamplingInterval),
+		spacer:      newRequestSpacer(),
+		scopedAuth:  newScopedTokenSources(c.ScopedTokenSources),
+		oauth2Auth:  oauth2Auth,
+		done:        make(chan struct{}),
+	}
+
+	client.interceptors = append(client.interceptors, c.Interceptors...)
+	if cache := newResponseCache(c.ResponseCache, client.Logger); cache != nil {
+		client.interceptors = append(client.interceptors, cache.intercept)
+	}
+
+	if c.ProactiveTokenRefresh {
+		client.Sugar.Debug("starting background token refresher")
+		client.startTokenRefresher()
 	}
 
 	if len(client.config.CustomCookies) > 0 {
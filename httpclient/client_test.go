@@ -0,0 +1,111 @@
+// httpclient/client_test.go
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// recordingExecutor wraps MockExecutor and records every request it is asked to send.
+type recordingExecutor struct {
+	MockExecutor
+	requests []*http.Request
+}
+
+func (r *recordingExecutor) Do(req *http.Request) (*http.Response, error) {
+	r.requests = append(r.requests, req)
+	return r.MockExecutor.Do(req)
+}
+
+func TestBuild_InjectedHTTPExecutor(t *testing.T) {
+	executor := &recordingExecutor{MockExecutor: MockExecutor{
+		LockedResponseCode: http.StatusOK,
+		ResponseBody:       `{"name":"mocked"}`,
+		ResponseHeaders:    http.Header{"Content-Type": []string{"application/json"}},
+	}}
+
+	// 192.0.2.0/24 is reserved for documentation (RFC 5737) so any real network attempt would fail.
+	client := newTestClient(t, "http://192.0.2.1", func(config *ClientConfig) {
+		config.HTTPExecutor = executor
+	})
+
+	if client.http != executor {
+		t.Fatalf("client executor = %T, want the injected executor", client.http)
+	}
+
+	var out struct {
+		Name string `json:"name"`
+	}
+	if _, err := client.DoRequest(http.MethodGet, "/resource", nil, &out); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+
+	if len(executor.requests) != 1 {
+		t.Fatalf("executor received %d requests, want 1", len(executor.requests))
+	}
+	if got := executor.requests[0].URL.String(); got != "http://192.0.2.1/resource" {
+		t.Errorf("request URL = %q, want http://192.0.2.1/resource", got)
+	}
+	if out.Name != "mocked" {
+		t.Errorf("out.Name = %q, want mocked", out.Name)
+	}
+}
+
+// closingExecutor counts calls to CloseIdleConnections.
+type closingExecutor struct {
+	MockExecutor
+	closes int
+}
+
+func (e *closingExecutor) CloseIdleConnections() {
+	e.closes++
+}
+
+func TestClose(t *testing.T) {
+	executor := &closingExecutor{}
+	client := newTestClient(t, "http://192.0.2.1", func(config *ClientConfig) {
+		config.HTTPExecutor = executor
+		config.ProactiveTokenRefresh = true
+		config.TokenRefreshBufferPeriod = time.Hour
+	})
+
+	for i := 0; i < 2; i++ {
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+
+	if executor.closes != 1 {
+		t.Errorf("CloseIdleConnections calls = %d, want 1", executor.closes)
+	}
+
+	select {
+	case <-client.done:
+	default:
+		t.Error("done channel not closed")
+	}
+}
+
+func TestDoRequest_NotModified(t *testing.T) {
+	executor := &MockExecutor{LockedResponseCode: http.StatusNotModified}
+	client := newTestClient(t, "http://192.0.2.1", func(config *ClientConfig) {
+		config.HTTPExecutor = executor
+	})
+
+	out := struct {
+		Name string `json:"name"`
+	}{Name: "cached"}
+
+	resp, err := client.DoRequest(http.MethodGet, "/users/1", nil, &out)
+	if !errors.Is(err, ErrNotModified) {
+		t.Fatalf("DoRequest() error = %v, want ErrNotModified", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotModified {
+		t.Errorf("response = %v, want 304", resp)
+	}
+	if out.Name != "cached" {
+		t.Errorf("out.Name = %q, want existing value kept", out.Name)
+	}
+}
@@ -0,0 +1,17 @@
+// httpclient/close.go
+package httpclient
+
+// Close releases the client's background resources: it signals background loops (such as the token refresher)
+// to stop, waits for them to exit and closes idle pooled connections. It is safe to call more than once.
+// Clients sharing a transport through a ClientGroup only close the pool's idle connections.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.wg.Wait()
+
+		c.http.CloseIdleConnections()
+		c.Logger().Debug("client closed")
+	})
+
+	return nil
+}
@@ -0,0 +1,46 @@
+// httpclient/compress.go
+package httpclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+)
+
+// DefaultCompressRequestBodyThreshold is the body size above which request bodies are gzipped when
+// CompressRequestBody is enabled and CompressRequestBodyThreshold is not set.
+const DefaultCompressRequestBodyThreshold = 1024
+
+// shouldCompressRequestBody reports whether a marshalled body of size bytes sent with method is gzipped.
+func (c *Client) shouldCompressRequestBody(method string, size int) bool {
+	if !c.config.CompressRequestBody {
+		return false
+	}
+
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return false
+	}
+
+	threshold := c.config.CompressRequestBodyThreshold
+	if threshold <= 0 {
+		threshold = DefaultCompressRequestBodyThreshold
+	}
+
+	return size > threshold
+}
+
+// gzipBody compresses body with gzip.
+func gzipBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
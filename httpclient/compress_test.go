@@ -0,0 +1,76 @@
+// httpclient/compress_test.go
+package httpclient
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompressRequestBody(t *testing.T) {
+	var encoding, name string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+
+		var reader io.Reader = r.Body
+		if encoding == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("gzip.NewReader: %v", err)
+				return
+			}
+			reader = gz
+		}
+
+		var in map[string]string
+		if err := json.NewDecoder(reader).Decode(&in); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		name = in["name"]
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	large := strings.Repeat("a", 2048)
+	tests := []struct {
+		name     string
+		enabled  bool
+		method   string
+		value    string
+		wantGzip bool
+	}{
+		{"disabled", false, http.MethodPost, large, false},
+		{"large post", true, http.MethodPost, large, true},
+		{"small post", true, http.MethodPost, "small", false},
+		{"large put", true, http.MethodPut, large, true},
+		{"delete", true, http.MethodDelete, large, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, server.URL, func(config *ClientConfig) {
+				config.CompressRequestBody = tt.enabled
+			})
+
+			var out map[string]interface{}
+			resp, err := client.DoRequest(tt.method, "/items", map[string]string{"name": tt.value}, &out)
+			if err != nil {
+				t.Fatalf("DoRequest() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if (encoding == "gzip") != tt.wantGzip {
+				t.Errorf("Content-Encoding = %q, want gzip: %v", encoding, tt.wantGzip)
+			}
+			if name != tt.value {
+				t.Errorf("server received %d byte name, want %d", len(name), len(tt.value))
+			}
+		})
+	}
+}
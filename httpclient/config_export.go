@@ -0,0 +1,99 @@
+// httpclient/config_export.go
+package httpclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// RedactedValue replaces secret values when a ClientConfig is exported.
+const RedactedValue = "[REDACTED]"
+
+// redactedURLPassword is the mask url.URL.Redacted puts in place of a URL's password.
+const redactedURLPassword = "xxxxx"
+
+// MarshalJSON exports the serialisable parts of the configuration with secrets redacted, so it can be shared
+// for support or reproduced elsewhere. Runtime only fields (Integration, Sugar, callbacks, HTTPExecutor and
+// in-memory TLS material) are omitted. Custom cookie values, the OAuth2 client secret and the proxy password and
+// auth token are replaced with RedactedValue, and a password in the proxy URL is masked by url.URL.Redacted.
+func (c ClientConfig) MarshalJSON() ([]byte, error) {
+	type clientConfigAlias ClientConfig
+	export := clientConfigAlias(c)
+
+	if len(c.CustomCookies) > 0 {
+		export.CustomCookies = make([]*http.Cookie, len(c.CustomCookies))
+		for i, cookie := range c.CustomCookies {
+			redacted := *cookie
+			redacted.Value = RedactedValue
+			export.CustomCookies[i] = &redacted
+		}
+	}
+
+	if c.OAuth2 != nil && c.OAuth2.ClientSecret != "" {
+		oauth2 := *c.OAuth2
+		oauth2.ClientSecret = RedactedValue
+		export.OAuth2 = &oauth2
+	}
+
+	if c.Proxy != nil {
+		proxy := *c.Proxy
+		if proxy.Password != "" {
+			proxy.Password = RedactedValue
+		}
+		if proxy.AuthToken != "" {
+			proxy.AuthToken = RedactedValue
+		}
+		if u, err := url.Parse(proxy.URL); err == nil {
+			proxy.URL = u.Redacted()
+		}
+		export.Proxy = &proxy
+	}
+
+	return json.Marshal(export)
+}
+
+// LoadClientConfigJSON reconstructs a ClientConfig from JSON produced by ClientConfig.MarshalJSON.
+// Redacted secrets are not restored: cookies whose value was redacted are dropped, and the OAuth2 client secret,
+// proxy password, proxy auth token and proxy URL password are left empty. Runtime only fields such as Integration,
+// Sugar and any callbacks must be supplied by the caller before calling Build.
+// Unlike LoadConfigFromFile no default values are applied, so the exported configuration round-trips unchanged.
+func LoadClientConfigJSON(data []byte) (*ClientConfig, error) {
+	var config ClientConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("could not unmarshal JSON: %v", err)
+	}
+
+	cookies := config.CustomCookies[:0]
+	for _, cookie := range config.CustomCookies {
+		if cookie.Value != RedactedValue {
+			cookies = append(cookies, cookie)
+		}
+	}
+	config.CustomCookies = cookies
+	if len(config.CustomCookies) == 0 {
+		config.CustomCookies = nil
+	}
+
+	if config.OAuth2 != nil && config.OAuth2.ClientSecret == RedactedValue {
+		config.OAuth2.ClientSecret = ""
+	}
+
+	if config.Proxy != nil {
+		if config.Proxy.Password == RedactedValue {
+			config.Proxy.Password = ""
+		}
+		if config.Proxy.AuthToken == RedactedValue {
+			config.Proxy.AuthToken = ""
+		}
+		if u, err := url.Parse(config.Proxy.URL); err == nil && u.User != nil {
+			if password, ok := u.User.Password(); ok && password == redactedURLPassword {
+				u.User = url.User(u.User.Username())
+				config.Proxy.URL = u.String()
+			}
+		}
+	}
+
+	return &config, nil
+}
@@ -11,6 +11,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/deploymenttheory/go-api-http-client/ratehandler"
 )
 
 const (
@@ -27,6 +29,7 @@ const (
 	DefaultTokenRefreshBufferPeriod    = 2 * time.Minute
 	DefaultTotalRetryDuration          = 5 * time.Minute
 	DefaultEnableConcurrencyManagement = false
+	DefaultDisableLogging              = false
 )
 
 // LoadConfigFromFile loads http client configuration settings from a JSON file.
@@ -60,6 +63,8 @@ func LoadConfigFromFile(filepath string) (*ClientConfig, error) {
 
 // LoadConfigFromEnv loads HTTP client configuration settings from environment variables.
 // If any environment variables are not set, the default values defined in the constants are used instead.
+// Secrets (CLIENT_ID, CLIENT_SECRET) can instead be read from files named by the matching *_FILE variable, e.g.
+// CLIENT_SECRET_FILE=/run/secrets/client_secret, which takes precedence over the inline variable.
 func LoadConfigFromEnv() (*ClientConfig, error) {
 	config := &ClientConfig{
 		HideSensitiveData:           getEnvAsBool("HIDE_SENSITIVE_DATA", DefaultHideSensitiveData),
@@ -70,6 +75,27 @@ func LoadConfigFromEnv() (*ClientConfig, error) {
 		TokenRefreshBufferPeriod:    getEnvAsDuration("TOKEN_REFRESH_BUFFER_PERIOD", DefaultTokenRefreshBufferPeriod),
 		TotalRetryDuration:          getEnvAsDuration("TOTAL_RETRY_DURATION", DefaultTotalRetryDuration),
 		EnableConcurrencyManagement: getEnvAsBool("ENABLE_CONCURRENCY_MANAGEMENT", DefaultEnableConcurrencyManagement),
+		DisableLogging:              getEnvAsBool("DISABLE_LOGGING", DefaultDisableLogging),
+	}
+
+	// Load OAuth2 client credentials, reading CLIENT_ID and CLIENT_SECRET from *_FILE secret files when provided.
+	if tokenURL := getEnvAsString("OAUTH2_TOKEN_URL", ""); tokenURL != "" {
+		clientID, err := getEnvAsSecret("CLIENT_ID", "")
+		if err != nil {
+			return nil, err
+		}
+		clientSecret, err := getEnvAsSecret("CLIENT_SECRET", "")
+		if err != nil {
+			return nil, err
+		}
+
+		config.OAuth2 = &OAuth2ClientCredentials{
+			TokenURL:     tokenURL,
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Scopes:       strings.Fields(getEnvAsString("OAUTH2_SCOPES", "")),
+			AuthStyle:    OAuth2AuthStyle(getEnvAsString("OAUTH2_AUTH_STYLE", "")),
+		}
 	}
 
 	// Load custom cookies from environment variables.
@@ -93,44 +119,150 @@ func LoadConfigFromEnv() (*ClientConfig, error) {
 	return config, nil
 }
 
-// TODO Review validateClientConfig
+// validateClientConfig checks every setting and returns all problems found joined into a single error, so a
+// misconfigured client reports each invalid field at once rather than one per attempt.
 func (c ClientConfig) validateClientConfig() error {
 
 	if c.PopulateDefaultValues {
 		c.SetDefaultValuesClientConfig()
 	}
 
+	var errs []error
+
 	// TODO adjust these strings to have links to documentation & centralise them
 	if c.Integration == nil {
-		return errors.New("no http client api integration supplied, please see repo documentation for this client and go-api-http-client-integration and provide an implementation")
+		errs = append(errs, errors.New("no http client api integration supplied, please see repo documentation for this client and go-api-http-client-integration and provide an implementation"))
 	}
 
 	if c.EnableConcurrencyManagement {
 		if c.MaxConcurrentRequests < 1 {
-			return errors.New("maximum concurrent requests cannot be less than 1")
+			errs = append(errs, errors.New("maximum concurrent requests cannot be less than 1"))
 		}
 	}
 
 	if c.CustomTimeout.Seconds() < 0 {
-		return errors.New("timeout cannot be less than 0 seconds")
+		errs = append(errs, errors.New("timeout cannot be less than 0 seconds"))
+	}
+
+	if c.MaxIdleConns < 0 || c.MaxIdleConnsPerHost < 0 || c.MaxConnsPerHost < 0 {
+		errs = append(errs, errors.New("connection pool limits cannot be less than 0"))
+	}
+
+	if c.IdleConnTimeout < 0 {
+		errs = append(errs, errors.New("idle connection timeout cannot be less than 0 seconds"))
+	}
+
+	if c.SLAThreshold < 0 {
+		errs = append(errs, errors.New("sla threshold cannot be less than 0 seconds"))
+	}
+
+	if c.HealthDegradedLatency < 0 || c.HealthDownLatency < 0 {
+		errs = append(errs, errors.New("health check latency thresholds cannot be less than 0 seconds"))
+	}
+
+	if c.MaxRequestBodyBytes < 0 || c.MaxResponseBodyBytes < 0 || c.MaxErrorBodyBytes < 0 {
+		errs = append(errs, errors.New("body size limits cannot be less than 0"))
+	}
+
+	if c.CompressRequestBodyThreshold < 0 {
+		errs = append(errs, errors.New("compression threshold cannot be less than 0"))
+	}
+
+	if c.MultipartChunkSize != 0 && c.MultipartChunkSize < MinMultipartChunkSize {
+		errs = append(errs, fmt.Errorf("multipart chunk size cannot be less than %d bytes", MinMultipartChunkSize))
+	}
+
+	if c.RequestDeadline < 0 {
+		errs = append(errs, errors.New("request deadline cannot be less than 0 seconds"))
+	}
+
+	if c.LogSamplingInterval < 0 {
+		errs = append(errs, errors.New("log sampling interval cannot be less than 0 seconds"))
+	}
+
+	if c.MandatoryRequestDelayJitter < 0 || c.MandatoryRequestDelayJitter > 1 {
+		errs = append(errs, errors.New("mandatory request delay jitter must be between 0 and 1"))
+	}
+
+	if c.MaxPages < 0 {
+		errs = append(errs, errors.New("max pages cannot be less than 0"))
 	}
 
 	if c.TokenRefreshBufferPeriod.Seconds() < 0 {
-		return errors.New("refresh buffer period cannot be less than 0 seconds")
+		errs = append(errs, errors.New("refresh buffer period cannot be less than 0 seconds"))
+	}
+
+	if err := validateBaseURLOverride(c.BaseURLOverride); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.validateProtocolFlags(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.AddressFamily.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.FailoverPolicy.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.ResponseTimeSmoothing.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.ResponseTimeEMAAlpha < 0 || c.ResponseTimeEMAAlpha > 1 {
+		errs = append(errs, errors.New("response time EMA alpha must be between 0 and 1"))
+	}
+
+	if c.OAuth2 != nil {
+		if err := c.OAuth2.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c.TLS != nil {
+		if err := c.TLS.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, r := range c.SuccessStatusCodes {
+		if err := r.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c.AsyncOperations != nil && c.AsyncOperations.MaxPollDuration < 0 {
+		errs = append(errs, errors.New("async operations max poll duration cannot be negative"))
+	}
+
+	if c.Proxy != nil {
+		if err := c.Proxy.validate(); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	if c.RetryEligiableRequests {
 		if c.TotalRetryDuration.Seconds() < 0 {
-			return errors.New("total retry duration cannot be less than 0 seconds")
+			errs = append(errs, errors.New("total retry duration cannot be less than 0 seconds"))
 		}
 
 		if c.MaxRetryAttempts < 0 {
-			return errors.New("max retry cannot be less than 0")
+			errs = append(errs, errors.New("max retry cannot be less than 0"))
 		}
 
+		if c.MaxConcurrentBackoffs < 0 {
+			errs = append(errs, errors.New("max concurrent backoffs cannot be less than 0"))
+		}
+
+		if c.RetryMinDelay < 0 || c.RetryMinDelay > ratehandler.MaxBackoffDelay {
+			errs = append(errs, fmt.Errorf("retry min delay must be between 0 and %s", ratehandler.MaxBackoffDelay))
+		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // SetDefaultValuesClientConfig sets default values for the client configuration. Ensuring that all fields have a valid or minimum value.
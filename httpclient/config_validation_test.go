@@ -0,0 +1,93 @@
+// httpclient/config_validation_test.go
+package httpclient
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigFromEnv_SecretFiles(t *testing.T) {
+	secretPath := filepath.Join(t.TempDir(), "client_secret")
+	if err := os.WriteFile(secretPath, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		env        map[string]string
+		wantID     string
+		wantSecret string
+		wantErr    bool
+	}{
+		{
+			name:       "inline variables",
+			env:        map[string]string{"CLIENT_ID": "id", "CLIENT_SECRET": "inline"},
+			wantID:     "id",
+			wantSecret: "inline",
+		},
+		{
+			name:       "file takes precedence and trailing newline is trimmed",
+			env:        map[string]string{"CLIENT_ID": "id", "CLIENT_SECRET": "inline", "CLIENT_SECRET_FILE": secretPath},
+			wantID:     "id",
+			wantSecret: "from-file",
+		},
+		{
+			name:    "unreadable file is an error",
+			env:     map[string]string{"CLIENT_ID": "id", "CLIENT_SECRET_FILE": filepath.Join(t.TempDir(), "missing")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OAUTH2_TOKEN_URL", "https://login.example.com/token")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			config, err := LoadConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfigFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if config.OAuth2 == nil {
+				t.Fatal("OAuth2 not loaded")
+			}
+			if config.OAuth2.ClientID != tt.wantID || config.OAuth2.ClientSecret != tt.wantSecret {
+				t.Errorf("credentials = %q/%q, want %q/%q", config.OAuth2.ClientID, config.OAuth2.ClientSecret, tt.wantID, tt.wantSecret)
+			}
+		})
+	}
+}
+
+func TestValidateClientConfig_ReportsEveryInvalidField(t *testing.T) {
+	config := ClientConfig{
+		Integration: &MockIntegration{BaseURL: "https://example.com"},
+		OAuth2:      &OAuth2ClientCredentials{TokenURL: "https://auth.example.com/token"},
+		MaxPages:    -1,
+		Proxy:       &ProxyConfig{URL: "ftp://proxy"},
+	}
+
+	err := config.validateClientConfig()
+	if err == nil {
+		t.Fatal("validateClientConfig() error = nil, want an error for the missing client id")
+	}
+
+	for _, want := range []string{"client id", "max pages", "invalid proxy url"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("validateClientConfig() error = %q, want it to mention %q", err, want)
+		}
+	}
+}
+
+func TestValidateClientConfig_Valid(t *testing.T) {
+	config := ClientConfig{Integration: &MockIntegration{BaseURL: "https://example.com"}}
+	if err := config.validateClientConfig(); err != nil {
+		t.Errorf("validateClientConfig() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,187 @@
+// httpclient/configfile.go
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadClientConfig reads a complete ClientConfig from a JSON (.json) or YAML (.yaml, .yml) file. Keys follow the
+// ClientConfig json tags, or the Go field name for fields without one, and nested settings such as tls, proxy and
+// oauth2 are objects. Durations may be written as strings like "30s" or "1m30s" as well as integer nanoseconds.
+// Unknown keys are rejected so typos are reported rather than silently ignored, and decoding errors name the
+// offending field. Defaults are applied as for LoadConfigFromFile; the result is validated when it is built.
+func LoadClientConfig(path string) (*ClientConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config file: %w", err)
+	}
+
+	var raw interface{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&raw)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q: expected .json, .yaml or .yml", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse config file %s: %w", path, err)
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+
+	normalized, err := normalizeDurations(raw, reflect.TypeOf(ClientConfig{}), "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	// Re-encode the normalized document so both formats decode through the json tags with the same strictness.
+	encoded, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	var config ClientConfig
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	config.SetDefaultValuesClientConfig()
+
+	return &config, nil
+}
+
+// NewClientFromFile loads the configuration at path with LoadClientConfig, attaches integration and builds the
+// client, so an application's whole client setup can live in a single config artifact.
+func NewClientFromFile(path string, integration APIIntegration) (*Client, error) {
+	config, err := LoadClientConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Integration = integration
+
+	return config.Build()
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// normalizeDurations walks a decoded document alongside the Go type it will be decoded into, converting duration
+// strings to nanoseconds so encoding/json accepts them. path locates value in the document for error messages.
+func normalizeDurations(value interface{}, t reflect.Type, path string) (interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		text, ok := value.(string)
+		if !ok {
+			return value, nil
+		}
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: invalid duration %q", path, text)
+		}
+		return int64(d), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value, nil
+		}
+		for key, child := range object {
+			field, ok := jsonField(t, key)
+			if !ok {
+				continue // Left for the strict decoder to report as an unknown field.
+			}
+			normalized, err := normalizeDurations(child, field.Type, joinConfigPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			object[key] = normalized
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value, nil
+		}
+		for key, child := range object {
+			normalized, err := normalizeDurations(child, t.Elem(), joinConfigPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			object[key] = normalized
+		}
+	case reflect.Slice:
+		list, ok := value.([]interface{})
+		if !ok {
+			return value, nil
+		}
+		for i, child := range list {
+			normalized, err := normalizeDurations(child, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			list[i] = normalized
+		}
+	}
+
+	return value, nil
+}
+
+// jsonField finds the struct field encoding/json would decode key into: an exact tag or field name match,
+// falling back to a case-insensitive one.
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	var fold reflect.StructField
+	folded := false
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if tagName, _, _ := strings.Cut(tag, ","); tagName != "" {
+				name = tagName
+			}
+		}
+
+		if name == key {
+			return field, true
+		}
+		if !folded && strings.EqualFold(name, key) {
+			fold, folded = field, true
+		}
+	}
+
+	return fold, folded
+}
+
+// joinConfigPath appends key to a dotted config path.
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,133 @@
+// httpclient/configfile_test.go
+package httpclient
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const jsonClientConfig = `{
+	"max_retry_attempts": 4,
+	"max_concurrent_requests": 8,
+	"enable_concurrency_management": true,
+	"retry_eligiable_requests": true,
+	"retry_min_delay": "250ms",
+	"CustomTimeout": "30s",
+	"TotalRetryDuration": 120000000000,
+	"sla_endpoint_thresholds": {"/slow": "2s"},
+	"tls": {"min_tls_version": "1.3"},
+	"proxy": {"url": "http://proxy.internal:3128", "no_proxy": "localhost"}
+}`
+
+const yamlClientConfig = `
+max_retry_attempts: 4
+max_concurrent_requests: 8
+enable_concurrency_management: true
+retry_eligiable_requests: true
+retry_min_delay: 250ms
+CustomTimeout: 30s
+TotalRetryDuration: 120000000000
+sla_endpoint_thresholds:
+  /slow: 2s
+tls:
+  min_tls_version: "1.3"
+proxy:
+  url: http://proxy.internal:3128
+  no_proxy: localhost
+`
+
+func writeConfigFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestLoadClientConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+	}{
+		{name: "json", file: "client.json", contents: jsonClientConfig},
+		{name: "yaml", file: "client.yaml", contents: yamlClientConfig},
+		{name: "yml", file: "client.yml", contents: yamlClientConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := LoadClientConfig(writeConfigFile(t, tt.file, tt.contents))
+			if err != nil {
+				t.Fatalf("LoadClientConfig() error = %v", err)
+			}
+
+			if config.MaxRetryAttempts != 4 || config.MaxConcurrentRequests != 8 {
+				t.Errorf("retry/concurrency = %d/%d, want 4/8", config.MaxRetryAttempts, config.MaxConcurrentRequests)
+			}
+			if !config.EnableConcurrencyManagement || !config.RetryEligiableRequests {
+				t.Error("boolean toggles were not loaded")
+			}
+			if config.RetryMinDelay != 250*time.Millisecond || config.CustomTimeout != 30*time.Second || config.TotalRetryDuration != 2*time.Minute {
+				t.Errorf("durations = %v/%v/%v, want 250ms/30s/2m", config.RetryMinDelay, config.CustomTimeout, config.TotalRetryDuration)
+			}
+			if config.SLAEndpointThresholds["/slow"] != 2*time.Second {
+				t.Errorf("SLAEndpointThresholds = %v, want /slow: 2s", config.SLAEndpointThresholds)
+			}
+			if config.TLS == nil || config.TLS.MinTLSVersion != "1.3" {
+				t.Errorf("TLS = %+v, want min version 1.3", config.TLS)
+			}
+			if config.Proxy == nil || config.Proxy.URL != "http://proxy.internal:3128" || config.Proxy.NoProxy != "localhost" {
+				t.Errorf("Proxy = %+v, want the configured proxy", config.Proxy)
+			}
+		})
+	}
+}
+
+func TestLoadClientConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+		wantErr  string
+	}{
+		{name: "unsupported extension", file: "client.toml", contents: "", wantErr: `unsupported config file extension ".toml"`},
+		{name: "malformed json", file: "client.json", contents: `{"max_retry_attempts":`, wantErr: "could not parse config file"},
+		{name: "unknown field", file: "client.json", contents: `{"max_retry_atempts": 3}`, wantErr: `unknown field "max_retry_atempts"`},
+		{name: "wrong type", file: "client.yaml", contents: "max_retry_attempts: lots", wantErr: "max_retry_attempts"},
+		{name: "invalid duration", file: "client.yaml", contents: "CustomTimeout: soon", wantErr: `field CustomTimeout: invalid duration "soon"`},
+		{name: "invalid nested duration", file: "client.json", contents: `{"sla_endpoint_thresholds": {"/slow": "fast"}}`, wantErr: "field sla_endpoint_thresholds./slow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadClientConfig(writeConfigFile(t, tt.file, tt.contents))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadClientConfig() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewClientFromFile(t *testing.T) {
+	path := writeConfigFile(t, "client.yaml", "disable_logging: true\nmax_retry_attempts: 2\n")
+
+	client, err := NewClientFromFile(path, &MockIntegration{BaseURL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("NewClientFromFile() error = %v", err)
+	}
+	defer client.Close()
+
+	if client.config.MaxRetryAttempts != 2 {
+		t.Errorf("MaxRetryAttempts = %d, want 2", client.config.MaxRetryAttempts)
+	}
+
+	invalid := writeConfigFile(t, "client.yaml", "disable_logging: true\nproxy:\n  url: ftp://proxy\n")
+	if _, err := NewClientFromFile(invalid, &MockIntegration{BaseURL: "https://example.com"}); err == nil || !strings.Contains(err.Error(), "invalid proxy url") {
+		t.Errorf("NewClientFromFile() error = %v, want the proxy validation error", err)
+	}
+}
@@ -0,0 +1,83 @@
+// httpclient/contenttype.go
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxContentTypeSnippetBytes is how much of an unexpected response body UnexpectedContentTypeError quotes.
+const maxContentTypeSnippetBytes = 256
+
+// UnexpectedContentTypeError is returned when a response's media type differs from the one set with
+// WithExpectedContentType, e.g. an HTML login page or proxy error where JSON was expected. It is returned before
+// unmarshalling is attempted, and the response body is left readable.
+type UnexpectedContentTypeError struct {
+	Expected   string
+	Actual     string
+	StatusCode int
+	// Snippet is the start of the response body, to help identify what the server sent instead.
+	Snippet string
+}
+
+// Error implements the error interface.
+func (e *UnexpectedContentTypeError) Error() string {
+	actual := e.Actual
+	if actual == "" {
+		actual = "none"
+	}
+	return fmt.Sprintf("unexpected response content type %s (status %d), expected %s: %q", actual, e.StatusCode, e.Expected, e.Snippet)
+}
+
+// WithExpectedContentType makes the request fail with an UnexpectedContentTypeError unless the success response's
+// media type is mediaType (e.g. "application/json"). Parameters such as charset are ignored. Responses without a
+// body (204 No Content) are not checked.
+func WithExpectedContentType(mediaType string) RequestOption {
+	return func(ro *requestOptions) {
+		ro.expectedContentType = mediaType
+	}
+}
+
+// checkContentType returns an UnexpectedContentTypeError if resp does not carry the expected media type.
+// The body is peeked for the snippet and then restored.
+func checkContentType(resp *http.Response, expected string) error {
+	if expected == "" || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	actual, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		actual = contentType
+	}
+	want, _, err := mime.ParseMediaType(expected)
+	if err != nil {
+		want = expected
+	}
+	if strings.EqualFold(actual, want) {
+		return nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxContentTypeSnippetBytes))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(snippet), resp.Body), resp.Body}
+
+	// Trim a multi-byte character cut off by the limit so the snippet stays valid UTF-8.
+	for len(snippet) > 0 && !utf8.Valid(snippet) {
+		snippet = snippet[:len(snippet)-1]
+	}
+
+	return &UnexpectedContentTypeError{
+		Expected:   expected,
+		Actual:     actual,
+		StatusCode: resp.StatusCode,
+		Snippet:    string(snippet),
+	}
+}
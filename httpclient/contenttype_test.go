@@ -0,0 +1,99 @@
+// httpclient/contenttype_test.go
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDoRequest_WithExpectedContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		status      int
+		body        string
+		wantErr     bool
+	}{
+		{name: "matching type with parameters", contentType: "application/json; charset=utf-8", status: http.StatusOK, body: `{"id":1}`},
+		{name: "html login page", contentType: "text/html", status: http.StatusOK, body: "<html><body>Please sign in</body></html>", wantErr: true},
+		{name: "missing content type", status: http.StatusOK, body: "plain", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				} else {
+					w.Header()["Content-Type"] = nil
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := newTestClient(t, server.URL, nil)
+
+			var out map[string]interface{}
+			resp, err := client.DoRequest(http.MethodGet, "/users/1", nil, &out, WithExpectedContentType("application/json"))
+
+			var ctErr *UnexpectedContentTypeError
+			if got := errors.As(err, &ctErr); got != tt.wantErr {
+				t.Fatalf("DoRequest() error = %v, want UnexpectedContentTypeError: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("DoRequest() error = %v", err)
+				}
+				resp.Body.Close()
+				return
+			}
+
+			if !strings.HasPrefix(tt.body, ctErr.Snippet) || ctErr.Snippet == "" {
+				t.Errorf("Snippet = %q, want the start of %q", ctErr.Snippet, tt.body)
+			}
+			if ctErr.Expected != "application/json" {
+				t.Errorf("Expected = %q, want application/json", ctErr.Expected)
+			}
+
+			// The body is left intact for the caller.
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if string(body) != tt.body {
+				t.Errorf("body after error = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestCheckContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		status      int
+		wantErr     bool
+	}{
+		{name: "case insensitive", contentType: "Application/JSON", status: http.StatusOK},
+		{name: "no content is not checked", status: http.StatusNoContent},
+		{name: "different type", contentType: "application/xml", status: http.StatusOK, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tt.status,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader("<a/>")),
+			}
+			if tt.contentType != "" {
+				resp.Header.Set("Content-Type", tt.contentType)
+			}
+
+			if err := checkContentType(resp, "application/json"); (err != nil) != tt.wantErr {
+				t.Errorf("checkContentType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
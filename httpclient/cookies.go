@@ -6,8 +6,8 @@ import (
 
 // loadCustomCookies applies the custom cookies supplied in the config and applies them to the http session.
 func (c *Client) loadCustomCookies() error {
-	cookieUrl, err := url.Parse((*c.Integration).GetFQDN())
-	c.Sugar.Debug("cookie URL set globally to: %s", cookieUrl)
+	cookieUrl, err := url.Parse(c.baseURL())
+	c.Logger().Debug("cookie URL set globally to: %s", cookieUrl)
 	if err != nil {
 		return err
 	}
@@ -15,9 +15,9 @@ func (c *Client) loadCustomCookies() error {
 	c.http.SetCookies(cookieUrl, c.config.CustomCookies)
 
 	if c.config.HideSensitiveData {
-		c.Sugar.Debug("[REDACTED] cookies set successfully")
+		c.Logger().Debug("[REDACTED] cookies set successfully")
 	} else {
-		c.Sugar.Debug("custom cookies set: %v", c.http.Cookies(cookieUrl))
+		c.Logger().Debug("custom cookies set: %v", c.http.Cookies(cookieUrl))
 	}
 
 	return nil
@@ -0,0 +1,54 @@
+// httpclient/deadline.go
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrRequestDeadlineExceeded is returned, wrapping the error the request was interrupted with, when a DoRequest call
+// runs past RequestDeadline. Check for it with errors.Is; the wrapped chain also matches context.DeadlineExceeded.
+var ErrRequestDeadlineExceeded = errors.New("request deadline exceeded")
+
+// requestDeadlineContext returns the context bounding a whole DoRequest call: every attempt, retry backoff and
+// failover. It is derived from parent; without a RequestDeadline it only ends when parent does.
+func (c *Client) requestDeadlineContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if c.config.RequestDeadline <= 0 {
+		return parent, func() {}
+	}
+
+	return context.WithTimeout(parent, c.config.RequestDeadline)
+}
+
+// finishRequestDeadline marks errors caused by the request deadline, rather than by parent ending, with
+// ErrRequestDeadlineExceeded and releases the deadline context. When a response is returned its body may still be
+// unread, so the context is released when the caller closes the body instead.
+func (c *Client) finishRequestDeadline(parent, ctx context.Context, cancel context.CancelFunc, resp *http.Response, err error) (*http.Response, error) {
+	if err != nil && parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w after %v: %w", ErrRequestDeadlineExceeded, c.config.RequestDeadline, err)
+	}
+
+	if resp != nil && resp.Body != nil {
+		resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	} else {
+		cancel()
+	}
+
+	return resp, err
+}
+
+// cancelOnCloseBody releases a request's context once its response body is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer.
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,91 @@
+// httpclient/deadline_test.go
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDoRequest_RequestDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	const deadline = 300 * time.Millisecond
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.RetryEligiableRequests = true
+		config.MaxRetryAttempts = 10
+		config.TotalRetryDuration = time.Minute
+		config.RequestDeadline = deadline
+	})
+
+	start := time.Now()
+	var out map[string]interface{}
+	_, err := client.DoRequest(http.MethodGet, "/users", nil, &out)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrRequestDeadlineExceeded) {
+		t.Fatalf("DoRequest() error = %v, want ErrRequestDeadlineExceeded", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("DoRequest() error = %v, want it to wrap context.DeadlineExceeded", err)
+	}
+	// Backoff between the retries alone would run for several seconds; the deadline must cut it short.
+	if elapsed > deadline+500*time.Millisecond {
+		t.Errorf("DoRequest() returned after %v, want close to the %v deadline", elapsed, deadline)
+	}
+}
+
+func TestDoRequest_RequestDeadlineLeavesBodyReadable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.RequestDeadline = time.Second
+	})
+
+	var out struct {
+		ID int `json:"id"`
+	}
+	resp, err := client.DoRequest(http.MethodGet, "/users/1", nil, &out)
+	if err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		t.Errorf("Body.Close() error = %v", err)
+	}
+	if out.ID != 1 {
+		t.Errorf("out.ID = %d, want 1", out.ID)
+	}
+}
+
+func TestDoRequestRaw_RequestDeadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.RequestDeadline = 200 * time.Millisecond
+	})
+
+	var out map[string]interface{}
+	_, err := client.DoRequestRaw(http.MethodPost, "/upload", strings.NewReader("payload"), "text/plain", &out)
+	if !errors.Is(err, ErrRequestDeadlineExceeded) {
+		t.Fatalf("DoRequestRaw() error = %v, want ErrRequestDeadlineExceeded", err)
+	}
+}
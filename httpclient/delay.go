@@ -0,0 +1,75 @@
+// httpclient/delay.go
+package httpclient
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/deploymenttheory/go-api-http-client/ratehandler"
+)
+
+// mandatoryRequestDelay returns the spacing to keep before the next request to the same host: MandatoryRequestDelay,
+// spread by up to ±MandatoryRequestDelayJitter of itself so that many clients sharing a delay don't fire in lockstep.
+func (c *Client) mandatoryRequestDelay() time.Duration {
+	return jitteredDelay(c.config.MandatoryRequestDelay, c.config.MandatoryRequestDelayJitter, rand.Float64())
+}
+
+// retryBackoff returns how long to sleep before retry number retry, never less than RetryMinDelay.
+func (c *Client) retryBackoff(retry int) time.Duration {
+	return ratehandler.CalculateBackoffWithFloor(retry, c.config.RetryMinDelay)
+}
+
+// jitteredDelay scales delay by a factor in [1-jitter, 1+jitter] chosen by r, a random number in [0, 1).
+// A zero delay stays zero.
+func jitteredDelay(delay time.Duration, jitter, r float64) time.Duration {
+	if delay <= 0 || jitter <= 0 {
+		return delay
+	}
+
+	return time.Duration(float64(delay) * (1 + jitter*(2*r-1)))
+}
+
+// requestSpacer enforces a minimum interval between consecutive requests to the same host.
+type requestSpacer struct {
+	mu   sync.Mutex
+	next map[string]time.Time // Earliest time the next request to each host may be sent.
+}
+
+// newRequestSpacer returns a requestSpacer with no history, so the first request to every host is sent immediately.
+func newRequestSpacer() *requestSpacer {
+	return &requestSpacer{next: make(map[string]time.Time)}
+}
+
+// wait blocks until a request to host may be sent, reserving that slot and pushing the host's next slot spacing
+// later. Concurrent callers are queued one spacing apart. A spacing of 0 never waits.
+func (s *requestSpacer) wait(ctx context.Context, host string, spacing time.Duration) error {
+	if spacing <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	slot := s.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	s.next[host] = slot.Add(spacing)
+	s.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
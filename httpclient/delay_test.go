@@ -0,0 +1,124 @@
+// httpclient/delay_test.go
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestJitteredDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		delay  time.Duration
+		jitter float64
+		r      float64
+		want   time.Duration
+	}{
+		{name: "zero delay stays zero", delay: 0, jitter: 0.25, r: 0.9, want: 0},
+		{name: "no jitter", delay: 100 * time.Millisecond, jitter: 0, r: 0.9, want: 100 * time.Millisecond},
+		{name: "lower bound", delay: 100 * time.Millisecond, jitter: 0.25, r: 0, want: 75 * time.Millisecond},
+		{name: "midpoint", delay: 100 * time.Millisecond, jitter: 0.25, r: 0.5, want: 100 * time.Millisecond},
+		{name: "towards upper bound", delay: 100 * time.Millisecond, jitter: 0.25, r: 0.75, want: 112500 * time.Microsecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jitteredDelay(tt.delay, tt.jitter, tt.r); got != tt.want {
+				t.Errorf("jitteredDelay(%v, %v, %v) = %v, want %v", tt.delay, tt.jitter, tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_mandatoryRequestDelay(t *testing.T) {
+	client := newTestClient(t, "https://api.example.com", func(config *ClientConfig) {
+		config.MandatoryRequestDelay = 100 * time.Millisecond
+		config.MandatoryRequestDelayJitter = 0.25
+	})
+
+	for i := 0; i < 100; i++ {
+		if got := client.mandatoryRequestDelay(); got < 75*time.Millisecond || got > 125*time.Millisecond {
+			t.Fatalf("mandatoryRequestDelay() = %v, want within ±25%% of 100ms", got)
+		}
+	}
+}
+
+func TestRequestSpacer_Wait(t *testing.T) {
+	spacer := newRequestSpacer()
+	ctx := context.Background()
+	const spacing = 50 * time.Millisecond
+
+	start := time.Now()
+	if err := spacer.wait(ctx, "a.example.com", spacing); err != nil {
+		t.Fatalf("first wait error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > spacing/2 {
+		t.Errorf("first request waited %v, want no wait", elapsed)
+	}
+
+	// A different host has its own spacing.
+	if err := spacer.wait(ctx, "b.example.com", spacing); err != nil {
+		t.Fatalf("other host wait error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > spacing/2 {
+		t.Errorf("request to another host waited %v, want no wait", elapsed)
+	}
+
+	if err := spacer.wait(ctx, "a.example.com", spacing); err != nil {
+		t.Fatalf("second wait error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < spacing {
+		t.Errorf("back-to-back request sent after %v, want at least %v", elapsed, spacing)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := spacer.wait(cancelled, "a.example.com", spacing); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait with cancelled context error = %v, want context.Canceled", err)
+	}
+}
+
+func TestDoRequest_MandatoryRequestDelaySpacing(t *testing.T) {
+	const delay = 100 * time.Millisecond
+
+	var mu sync.Mutex
+	var sent []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sent = append(sent, time.Now())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.MandatoryRequestDelay = delay
+	})
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		var out map[string]interface{}
+		resp, err := client.DoRequest(http.MethodGet, "/users", nil, &out)
+		if err != nil {
+			t.Fatalf("DoRequest() error = %v", err)
+		}
+		resp.Body.Close()
+
+		if i == 0 {
+			if elapsed := time.Since(start); elapsed >= delay {
+				t.Errorf("single request took %v, want it sent without waiting out the %v delay", elapsed, delay)
+			}
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if gap := sent[1].Sub(sent[0]); gap < delay-5*time.Millisecond {
+		t.Errorf("gap between consecutive requests = %v, want at least %v", gap, delay)
+	}
+}
@@ -0,0 +1,67 @@
+// httpclient/dialer.go
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// AddressFamily restricts which IP address family the client dials.
+type AddressFamily string
+
+const (
+	// AddressFamilyAuto dials whichever family the resolver and the network stack prefer (Go's default behaviour).
+	AddressFamilyAuto AddressFamily = ""
+	// AddressFamilyIPv4Only only dials IPv4 addresses (tcp4).
+	AddressFamilyIPv4Only AddressFamily = "ipv4"
+	// AddressFamilyIPv6Only only dials IPv6 addresses (tcp6).
+	AddressFamilyIPv6Only AddressFamily = "ipv6"
+)
+
+// Dialer defaults, matching those used by http.DefaultTransport.
+const (
+	defaultDialTimeout   = 30 * time.Second
+	defaultDialKeepAlive = 30 * time.Second
+)
+
+// dialContextFunc is the signature of http.Transport.DialContext.
+type dialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// validate checks the address family is one of the supported values.
+func (f AddressFamily) validate() error {
+	switch f {
+	case AddressFamilyAuto, AddressFamilyIPv4Only, AddressFamilyIPv6Only:
+		return nil
+	}
+
+	return fmt.Errorf("unsupported address family: %q, expected %q, %q or empty for auto", f, AddressFamilyIPv4Only, AddressFamilyIPv6Only)
+}
+
+// restrictNetwork maps a generic "tcp" network onto the family specific network.
+func (f AddressFamily) restrictNetwork(network string) string {
+	if network != "tcp" {
+		return network
+	}
+
+	switch f {
+	case AddressFamilyIPv4Only:
+		return "tcp4"
+	case AddressFamilyIPv6Only:
+		return "tcp6"
+	}
+
+	return network
+}
+
+// wrapDialer returns a dial function which forces the configured address family onto every dial.
+func (f AddressFamily) wrapDialer(dial dialContextFunc) dialContextFunc {
+	if f == AddressFamilyAuto {
+		return dial
+	}
+
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dial(ctx, f.restrictNetwork(network), address)
+	}
+}
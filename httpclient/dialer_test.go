@@ -0,0 +1,77 @@
+// httpclient/dialer_test.go
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestAddressFamily_wrapDialer(t *testing.T) {
+	tests := []struct {
+		name        string
+		family      AddressFamily
+		wantNetwork string
+	}{
+		{name: "auto leaves network untouched", family: AddressFamilyAuto, wantNetwork: "tcp"},
+		{name: "ipv4 only dials tcp4", family: AddressFamilyIPv4Only, wantNetwork: "tcp4"},
+		{name: "ipv6 only dials tcp6", family: AddressFamilyIPv6Only, wantNetwork: "tcp6"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotNetwork string
+			fakeDial := func(_ context.Context, network, _ string) (net.Conn, error) {
+				gotNetwork = network
+				return nil, errors.New("not dialling")
+			}
+
+			tt.family.wrapDialer(fakeDial)(context.Background(), "tcp", "example.com:443")
+			if gotNetwork != tt.wantNetwork {
+				t.Errorf("dialled network = %q, want %q", gotNetwork, tt.wantNetwork)
+			}
+		})
+	}
+}
+
+func TestAddressFamily_LoopbackDial(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("IPv4 loopback unavailable: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	dialer := &net.Dialer{}
+
+	conn, err := AddressFamilyIPv4Only.wrapDialer(dialer.DialContext)(context.Background(), "tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("IPv4Only dial to IPv4 loopback failed: %v", err)
+	}
+	if network := conn.LocalAddr().Network(); network != "tcp" {
+		t.Errorf("connection network = %q, want tcp", network)
+	}
+	if ip := conn.RemoteAddr().(*net.TCPAddr).IP; ip.To4() == nil {
+		t.Errorf("IPv4Only connection remote address %v is not IPv4", ip)
+	}
+	conn.Close()
+
+	if _, err := AddressFamilyIPv6Only.wrapDialer(dialer.DialContext)(context.Background(), "tcp", listener.Addr().String()); err == nil {
+		t.Error("IPv6Only dial to an IPv4 address succeeded, want error")
+	}
+}
+
+func TestAddressFamily_validate(t *testing.T) {
+	if err := AddressFamily("ipv5").validate(); err == nil {
+		t.Error("validate() with unknown family returned nil, want error")
+	}
+}
@@ -0,0 +1,111 @@
+// httpclient/dnscache.go
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultDNSCacheTTL is how long resolved addresses are reused when ReResolveOnConnectionError is enabled
+// and ClientConfig.DNSCacheTTL is not set.
+const DefaultDNSCacheTTL = 30 * time.Second
+
+// HostResolver resolves a host name to its addresses. *net.Resolver satisfies this interface.
+type HostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// dnsCacheEntry holds the resolved addresses for a host and when they expire.
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// dnsCache resolves and caches host addresses for the client's dialer, so that the client controls when
+// a host is re-resolved. Entries are flushed after a connection error to pick up a backend's new address.
+type dnsCache struct {
+	resolver HostResolver
+	ttl      time.Duration
+	entries  map[string]dnsCacheEntry
+	sync.Mutex
+}
+
+// newDNSCache creates a dnsCache using resolver, falling back to net.DefaultResolver when nil.
+func newDNSCache(resolver HostResolver, ttl time.Duration) *dnsCache {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	if ttl <= 0 {
+		ttl = DefaultDNSCacheTTL
+	}
+
+	return &dnsCache{
+		resolver: resolver,
+		ttl:      ttl,
+		entries:  make(map[string]dnsCacheEntry),
+	}
+}
+
+// lookup returns the cached addresses for host, resolving them if missing or expired.
+func (d *dnsCache) lookup(ctx context.Context, host string) ([]string, error) {
+	d.Lock()
+	entry, ok := d.entries[host]
+	d.Unlock()
+
+	if ok && time.Now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, err := d.resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses found for host: %s", host)
+	}
+
+	d.Lock()
+	d.entries[host] = dnsCacheEntry{addrs: addrs, expires: time.Now().Add(d.ttl)}
+	d.Unlock()
+
+	return addrs, nil
+}
+
+// flush drops the cached addresses for host so the next dial re-resolves it.
+func (d *dnsCache) flush(host string) {
+	d.Lock()
+	delete(d.entries, host)
+	d.Unlock()
+}
+
+// wrapDialer returns a dial function which resolves host names through the cache and tries each
+// address in turn. Addresses which are already IP literals are dialled directly.
+func (d *dnsCache) wrapDialer(dial dialContextFunc) dialContextFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil || net.ParseIP(host) != nil {
+			return dial(ctx, network, address)
+		}
+
+		addrs, err := d.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var firstErr error
+		for _, addr := range addrs {
+			conn, err := dial(ctx, network, net.JoinHostPort(addr, port))
+			if err == nil {
+				return conn, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+
+		return nil, firstErr
+	}
+}
@@ -0,0 +1,88 @@
+// httpclient/dnscache_test.go
+package httpclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// sequenceResolver returns each configured answer in turn, repeating the last one.
+type sequenceResolver struct {
+	answers [][]string
+	calls   int
+	sync.Mutex
+}
+
+func (r *sequenceResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	answer := r.answers[len(r.answers)-1]
+	if r.calls < len(r.answers) {
+		answer = r.answers[r.calls]
+	}
+	r.calls++
+
+	return answer, nil
+}
+
+func TestReResolveOnConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(serverURL.Host)
+
+	// The first answer points at an address nothing listens on, as after a backend failover.
+	resolver := &sequenceResolver{answers: [][]string{{"127.0.0.2"}, {"127.0.0.1"}}}
+	client := newTestClient(t, "http://backend.test:"+port, func(config *ClientConfig) {
+		config.ReResolveOnConnectionError = true
+		config.Resolver = resolver
+	})
+
+	var out map[string]interface{}
+	resp, err := client.DoRequest(http.MethodGet, "/items", nil, &out)
+	if err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if out["ok"] != true {
+		t.Errorf("response = %v, want ok", out)
+	}
+	if resolver.calls != 2 {
+		t.Errorf("resolver calls = %d, want 2", resolver.calls)
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"dial error", &net.OpError{Op: "dial", Err: &net.AddrError{Err: "refused"}}, true},
+		{"dns error", &net.DNSError{Err: "no such host", Name: "backend.test"}, true},
+		{"read error", &net.OpError{Op: "read", Err: context.Canceled}, false},
+		{"other error", context.DeadlineExceeded, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isConnectionError(tt.err); got != tt.want {
+				t.Errorf("isConnectionError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
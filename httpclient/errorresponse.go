@@ -0,0 +1,17 @@
+// httpclient/errorresponse.go
+package httpclient
+
+import (
+	"net/http"
+
+	"github.com/deploymenttheory/go-api-http-client/response"
+)
+
+// handleErrorResponse parses an error response into a response.APIError, keeping up to MaxErrorBodyBytes of the
+// raw body and leaving it out of the error's message when HideSensitiveData is set.
+func (c *Client) handleErrorResponse(resp *http.Response) *response.APIError {
+	return response.HandleAPIErrorResponseWithOptions(resp, c.Logger(), response.ErrorResponseOptions{
+		MaxRawBodyBytes:   c.config.MaxErrorBodyBytes,
+		HideSensitiveData: c.config.HideSensitiveData,
+	})
+}
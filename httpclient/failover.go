@@ -0,0 +1,146 @@
+// httpclient/failover.go
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"github.com/deploymenttheory/go-api-http-client/response"
+	"go.uber.org/zap"
+)
+
+// FailoverPolicy selects which of the FailoverDomains a request starts with.
+type FailoverPolicy string
+
+const (
+	// FailoverPolicySticky starts every request on the last domain which answered successfully (the default).
+	FailoverPolicySticky FailoverPolicy = "sticky"
+
+	// FailoverPolicyPrimaryFirst starts every request on the first domain, failing over only for that request.
+	FailoverPolicyPrimaryFirst FailoverPolicy = "primary_first"
+)
+
+// validate checks the policy is a known value. The zero value selects FailoverPolicySticky.
+func (p FailoverPolicy) validate() error {
+	switch p {
+	case "", FailoverPolicySticky, FailoverPolicyPrimaryFirst:
+		return nil
+	default:
+		return fmt.Errorf("invalid failover policy: %s, expected %s or %s", p, FailoverPolicySticky, FailoverPolicyPrimaryFirst)
+	}
+}
+
+// failoverState holds the parsed failover domains and the index of the last domain that answered.
+type failoverState struct {
+	domains []*url.URL
+	policy  FailoverPolicy
+	current atomic.Int32
+}
+
+// newFailoverState normalises and parses domains, such as "https://eu.api.example.com" or "eu.api.example.com".
+func newFailoverState(domains []string, policy FailoverPolicy) (*failoverState, error) {
+	state := &failoverState{policy: policy}
+	for _, domain := range domains {
+		u, err := normalizeDomain(domain)
+		if err != nil {
+			return nil, err
+		}
+		state.domains = append(state.domains, u)
+	}
+
+	return state, nil
+}
+
+// normalizeDomain turns a configured domain into a base URL: surrounding whitespace and trailing slashes are
+// removed, a missing scheme defaults to https, and the result must be a bare scheme and host without a path,
+// query or credentials.
+func normalizeDomain(domain string) (*url.URL, error) {
+	trimmed := strings.TrimSpace(domain)
+	if !strings.Contains(trimmed, "://") {
+		trimmed = "https://" + trimmed
+	}
+	scheme, host, _ := strings.Cut(trimmed, "://")
+	host = strings.TrimRight(host, "/")
+	if host == "" {
+		return nil, fmt.Errorf("invalid failover domain: %q, expected a host such as api.example.com", domain)
+	}
+	trimmed = scheme + "://" + host
+
+	u, err := url.Parse(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid failover domain: %q: %v", domain, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid failover domain: %q, scheme must be http or https", domain)
+	}
+	if u.Hostname() == "" || strings.ContainsAny(u.Hostname(), " \t") {
+		return nil, fmt.Errorf("invalid failover domain: %q, expected a host such as api.example.com", domain)
+	}
+	if u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return nil, fmt.Errorf("invalid failover domain: %q, expected only a scheme and host such as https://api.example.com", domain)
+	}
+
+	return &url.URL{Scheme: u.Scheme, Host: strings.ToLower(u.Host)}, nil
+}
+
+// start returns the index of the domain a request should try first.
+func (f *failoverState) start() int {
+	if f.policy == FailoverPolicyPrimaryFirst {
+		return 0
+	}
+	return int(f.current.Load())
+}
+
+// doRequestWithFailover sends the request to each failover domain in turn, starting from the policy's preferred
+// domain, until one answers without a connection error or 5xx. The answering domain is remembered for the sticky policy.
+func (c *Client) doRequestWithFailover(ctx context.Context, method, endpoint string, body, out interface{}, ro *requestOptions) (*http.Response, error) {
+	domains := c.failover.domains
+	start := c.failover.start()
+
+	var resp *http.Response
+	var err error
+	for i := range domains {
+		index := (start + i) % len(domains)
+
+		attempt := *ro
+		attempt.baseURL = domains[index]
+
+		resp, err = c.doRequest(ctx, method, endpoint, body, out, &attempt)
+		if ctx.Err() != nil {
+			return resp, err
+		}
+		if !shouldFailover(err) {
+			if previous := c.failover.current.Swap(int32(index)); int(previous) != index {
+				c.Logger().Infow("Switched active domain", zap.String("domain", domains[index].Host))
+			}
+			return resp, err
+		}
+
+		if resp != nil && i < len(domains)-1 {
+			resp.Body.Close()
+		}
+		c.Logger().Warnw("Request failed on domain, failing over", zap.String("domain", domains[index].Host), zap.String("endpoint", endpoint), zap.Error(err))
+	}
+
+	return resp, err
+}
+
+// shouldFailover reports whether err indicates the domain itself is unavailable, i.e. a connection level
+// failure or a 5xx response which survived the client's retries.
+func shouldFailover(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr *response.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	return isConnectionError(err)
+}
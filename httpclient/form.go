@@ -0,0 +1,29 @@
+// httpclient/form.go
+package httpclient
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// FormContentType is the Content-Type of bodies sent by DoFormRequest.
+const FormContentType = "application/x-www-form-urlencoded"
+
+// DoFormRequest sends form as an application/x-www-form-urlencoded body, as required by OAuth token endpoints and
+// many legacy APIs, bypassing the Integration's PrepRequestBody. In every other respect it behaves like DoRequest:
+// the body is size checked and can be compressed, and idempotent methods are retried when RetryEligiableRequests is set.
+func (c *Client) DoFormRequest(method, endpoint string, form url.Values, out interface{}, opts ...RequestOption) (*http.Response, error) {
+	if form == nil {
+		form = url.Values{}
+	}
+
+	return c.DoRequest(method, endpoint, nil, out, append(opts, withFormBody(form))...)
+}
+
+// withFormBody replaces the request body with form and sets the form Content-Type.
+func withFormBody(form url.Values) RequestOption {
+	return func(ro *requestOptions) {
+		ro.formBody = form
+		ro.rawContentType = FormContentType
+	}
+}
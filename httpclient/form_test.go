@@ -0,0 +1,54 @@
+// httpclient/form_test.go
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestDoFormRequest(t *testing.T) {
+	var gotContentType string
+	var gotForm url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm() error = %v", err)
+		}
+		gotForm = r.PostForm
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"abc","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, nil)
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {"id"},
+		"client_secret": {"s3cr&t=value"},
+	}
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	resp, err := client.DoFormRequest(http.MethodPost, "/oauth/token", form, &out)
+	if err != nil {
+		t.Fatalf("DoFormRequest() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if gotContentType != FormContentType {
+		t.Errorf("Content-Type = %q, want %q", gotContentType, FormContentType)
+	}
+	for key := range form {
+		if gotForm.Get(key) != form.Get(key) {
+			t.Errorf("form[%s] = %q, want %q", key, gotForm.Get(key), form.Get(key))
+		}
+	}
+	if out.AccessToken != "abc" || out.ExpiresIn != 3600 {
+		t.Errorf("out = %+v, want the decoded token response", out)
+	}
+}
@@ -0,0 +1,56 @@
+// httpclient/group.go
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// ClientGroup builds clients which share a single http.Transport, and so a single connection pool and its
+// limits (e.g. MaxConnsPerHost), across the whole process. Each client keeps its own Integration, timeouts,
+// retry and concurrency settings.
+type ClientGroup struct {
+	Transport *http.Transport
+}
+
+// NewClientGroup builds the shared transport from the transport level settings of poolConfig
+// (TLS, AddressFamily and the connection pool limits). Other fields of poolConfig are ignored.
+func NewClientGroup(poolConfig ClientConfig) (*ClientGroup, error) {
+	if poolConfig.Sugar == nil {
+		zapLogger, err := zap.NewProduction()
+		if err != nil {
+			return nil, err
+		}
+		poolConfig.Sugar = zapLogger.Sugar()
+	}
+
+	if err := poolConfig.AddressFamily.validate(); err != nil {
+		return nil, err
+	}
+
+	if poolConfig.TLS != nil {
+		if err := poolConfig.TLS.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	transport, err := poolConfig.buildTransport(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build shared transport: %v", err)
+	}
+
+	return &ClientGroup{Transport: transport}, nil
+}
+
+// Build creates a client from config which uses the group's shared transport.
+func (g *ClientGroup) Build(config *ClientConfig) (*Client, error) {
+	config.Transport = g.Transport
+	return config.Build()
+}
+
+// CloseIdleConnections closes idle connections in the shared pool.
+func (g *ClientGroup) CloseIdleConnections() {
+	g.Transport.CloseIdleConnections()
+}
@@ -0,0 +1,87 @@
+// httpclient/group_test.go
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestClientGroup_SharedPool(t *testing.T) {
+	var newConns, active, maxActive int32
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&active, 1)
+		for {
+			seen := atomic.LoadInt32(&maxActive)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxActive, seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&active, -1)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&newConns, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	group, err := NewClientGroup(ClientConfig{Sugar: zap.NewNop().Sugar(), MaxConnsPerHost: 1})
+	if err != nil {
+		t.Fatalf("NewClientGroup() error = %v", err)
+	}
+	defer group.CloseIdleConnections()
+
+	clients := make([]*Client, 2)
+	for i := range clients {
+		clients[i], err = group.Build(&ClientConfig{
+			Integration: &MockIntegration{BaseURL: server.URL},
+			Sugar:       zap.NewNop().Sugar(),
+		})
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+	}
+
+	var out map[string]interface{}
+	for _, client := range clients {
+		if _, err := client.DoRequest(http.MethodGet, "/", nil, &out); err != nil {
+			t.Fatalf("DoRequest() error = %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&newConns); got != 1 {
+		t.Errorf("sequential requests from two clients opened %d connections, want 1 reused connection", got)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+			var out map[string]interface{}
+			if _, err := client.DoRequest(http.MethodGet, "/", nil, &out); err != nil {
+				t.Errorf("DoRequest() error = %v", err)
+			}
+		}(clients[i%2])
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&maxActive); got != 1 {
+		t.Errorf("max concurrent server requests = %d, want 1 (shared MaxConnsPerHost)", got)
+	}
+	if got := atomic.LoadInt32(&newConns); got != 1 {
+		t.Errorf("total connections = %d, want 1", got)
+	}
+}
@@ -0,0 +1,87 @@
+// httpclient/headers.go
+package httpclient
+
+import (
+	"net/http"
+	"runtime/debug"
+	"strings"
+)
+
+// modulePath identifies this library in the User-Agent header and in the binary's build info.
+const modulePath = "github.com/deploymenttheory/go-api-http-client"
+
+// libraryUserAgent is the "go-api-http-client/<version>" product token appended to every User-Agent.
+var libraryUserAgent = "go-api-http-client/" + libraryVersion()
+
+// libraryVersion returns the version of this module linked into the running binary, or "devel" when unknown.
+func libraryVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+
+	if info.Main.Path == modulePath && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			return dep.Version
+		}
+	}
+
+	return "devel"
+}
+
+// userAgent builds the User-Agent for a request: the configured UserAgent, any per-request suffix and the
+// library's own product token. It returns "" when neither a UserAgent nor a suffix is set, leaving the
+// integration's (or Go's) default in place.
+func (c *Client) userAgent(ro *requestOptions) string {
+	var parts []string
+	if c.config.UserAgent != "" {
+		parts = append(parts, c.config.UserAgent)
+	}
+	if ro != nil && ro.userAgentSuffix != "" {
+		parts = append(parts, ro.userAgentSuffix)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return strings.Join(append(parts, libraryUserAgent), " ")
+}
+
+// setUserAgent applies the client's User-Agent to req, if one is configured. When none is configured and
+// the integration has not set one either, the library's product token is used instead of Go's default.
+func (c *Client) setUserAgent(req *http.Request, ro *requestOptions) {
+	if userAgent := c.userAgent(ro); userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+		return
+	}
+
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", libraryUserAgent)
+	}
+}
+
+// setAcceptLanguage applies DefaultAcceptLanguage to req unless the integration has set Accept-Language itself.
+func (c *Client) setAcceptLanguage(req *http.Request) {
+	if c.config.DefaultAcceptLanguage != "" && req.Header.Get("Accept-Language") == "" {
+		req.Header.Set("Accept-Language", c.config.DefaultAcceptLanguage)
+	}
+}
+
+// setRequestHeaders applies the caller's WithHeaders values on top of the standard headers. Caller headers win,
+// except Authorization, which stays under the integration's control.
+func (c *Client) setRequestHeaders(req *http.Request, ro *requestOptions) {
+	if ro == nil {
+		return
+	}
+
+	for name, value := range ro.headers {
+		if http.CanonicalHeaderKey(name) == "Authorization" {
+			c.Logger().Warnw("Ignoring per-request Authorization header; authentication is handled by the integration")
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+}
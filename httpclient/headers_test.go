@@ -0,0 +1,208 @@
+// httpclient/headers_test.go
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestUserAgent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		userAgent string
+		opts      []RequestOption
+		want      string
+	}{
+		{"default", "", nil, libraryUserAgent},
+		{"configured", "acme-sync/2.3.1", nil, "acme-sync/2.3.1 " + libraryUserAgent},
+		{"suffix", "acme-sync/2.3.1", []RequestOption{WithUserAgentSuffix("job/42")}, "acme-sync/2.3.1 job/42 " + libraryUserAgent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, server.URL, func(config *ClientConfig) {
+				config.UserAgent = tt.userAgent
+			})
+
+			var out map[string]interface{}
+			resp, err := client.DoRequest(http.MethodGet, "/items", nil, &out, tt.opts...)
+			if err != nil {
+				t.Fatalf("DoRequest() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if got != tt.want {
+				t.Errorf("User-Agent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if !strings.HasPrefix(libraryUserAgent, "go-api-http-client/") {
+		t.Errorf("libraryUserAgent = %q", libraryUserAgent)
+	}
+}
+
+func TestWithHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, nil)
+
+	var out map[string]interface{}
+	_, err := client.DoRequest(http.MethodGet, "/resource", nil, &out,
+		WithHeaders(map[string]string{"X-Trace-Id": "abc", "User-Agent": "first"}),
+		WithHeaders(map[string]string{"User-Agent": "caller/1.0", "authorization": "Bearer injected"}),
+	)
+	if err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+
+	if got.Get("X-Trace-Id") != "abc" {
+		t.Errorf("X-Trace-Id = %q, want abc", got.Get("X-Trace-Id"))
+	}
+	if got.Get("User-Agent") != "caller/1.0" {
+		t.Errorf("User-Agent = %q, want the caller's value to win", got.Get("User-Agent"))
+	}
+	if got.Get("Authorization") == "Bearer injected" {
+		t.Error("caller overrode the Authorization header")
+	}
+}
+
+func TestDefaultAcceptLanguage(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Accept-Language")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		config   string
+		opts     []RequestOption
+		expected string
+	}{
+		{name: "unset sends nothing", expected: ""},
+		{name: "configured locale", config: "fr-FR, fr;q=0.9", expected: "fr-FR, fr;q=0.9"},
+		{name: "per-request header wins", config: "fr-FR", opts: []RequestOption{WithHeaders(map[string]string{"Accept-Language": "de-DE"})}, expected: "de-DE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, server.URL, func(c *ClientConfig) { c.DefaultAcceptLanguage = tt.config })
+
+			var out map[string]interface{}
+			if _, err := client.DoRequest(http.MethodGet, "/resource", nil, &out, tt.opts...); err != nil {
+				t.Fatalf("DoRequest() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Accept-Language = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetRequestHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		integration *MockIntegration
+		opts        []RequestOption
+		want        map[string]string
+	}{
+		{
+			name:        "bearer token from the integration",
+			integration: &MockIntegration{BaseURL: "http://192.0.2.1", Token: "secret"},
+			want:        map[string]string{"Authorization": "Bearer secret"},
+		},
+		{
+			name:        "integration headers kept",
+			integration: &MockIntegration{BaseURL: "http://192.0.2.1", Headers: map[string]string{"Accept": "application/xml"}},
+			want:        map[string]string{"Accept": "application/xml"},
+		},
+		{
+			name:        "custom headers override integration headers",
+			integration: &MockIntegration{BaseURL: "http://192.0.2.1", Headers: map[string]string{"Accept": "application/xml"}},
+			opts:        []RequestOption{WithHeaders(map[string]string{"Accept": "application/json", "X-Tenant": "acme"})},
+			want:        map[string]string{"Accept": "application/json", "X-Tenant": "acme"},
+		},
+		{
+			name:        "custom Authorization ignored",
+			integration: &MockIntegration{BaseURL: "http://192.0.2.1", Token: "secret"},
+			opts:        []RequestOption{WithHeaders(map[string]string{"Authorization": "Bearer injected"})},
+			want:        map[string]string{"Authorization": "Bearer secret"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &recordingExecutor{MockExecutor: MockExecutor{
+				LockedResponseCode: http.StatusOK,
+				ResponseBody:       `{}`,
+				ResponseHeaders:    http.Header{"Content-Type": []string{"application/json"}},
+			}}
+			client := newTestClient(t, tt.integration.BaseURL, func(config *ClientConfig) {
+				config.Integration = tt.integration
+				config.HTTPExecutor = executor
+			})
+
+			var out map[string]interface{}
+			if _, err := client.DoRequest(http.MethodGet, "/resource", nil, &out, tt.opts...); err != nil {
+				t.Fatalf("DoRequest() error = %v", err)
+			}
+			if len(executor.requests) != 1 {
+				t.Fatalf("requests sent = %d, want 1", len(executor.requests))
+			}
+
+			sent := executor.requests[0].Header
+			for name, want := range tt.want {
+				if got := sent.Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckDeprecationHeader(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	client := newTestClient(t, "http://192.0.2.1", func(config *ClientConfig) {
+		config.Sugar = zap.New(core).Sugar()
+	})
+	logs.TakeAll()
+
+	req, _ := http.NewRequest(http.MethodGet, "http://192.0.2.1/v1/users", nil)
+
+	client.CheckDeprecationHeader(&http.Response{Header: http.Header{}, Request: req})
+	if logs.Len() != 0 {
+		t.Fatalf("warnings without a Deprecation header = %d, want 0", logs.Len())
+	}
+
+	client.CheckDeprecationHeader(&http.Response{Header: http.Header{"Deprecation": []string{"true"}}, Request: req})
+	entries := logs.FilterMessage("API endpoint is deprecated").All()
+	if len(entries) != 1 {
+		t.Fatalf("deprecation warnings = %d, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["deprecation"] != "true" || fields["url"] != "http://192.0.2.1/v1/users" {
+		t.Errorf("warning fields = %v, want the header value and request URL", fields)
+	}
+}
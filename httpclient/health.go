@@ -0,0 +1,117 @@
+// httpclient/health.go
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultHealthDegradedLatency is the probe latency above which an endpoint is reported Degraded.
+	DefaultHealthDegradedLatency = 1 * time.Second
+
+	// DefaultHealthDownLatency is the probe latency above which an endpoint is reported Down.
+	DefaultHealthDownLatency = 5 * time.Second
+)
+
+// HealthStatus classifies an endpoint's readiness.
+type HealthStatus string
+
+const (
+	HealthHealthy  HealthStatus = "Healthy"
+	HealthDegraded HealthStatus = "Degraded"
+	HealthDown     HealthStatus = "Down"
+)
+
+// HealthResult is the outcome of a CheckHealth probe.
+type HealthResult struct {
+	Reachable  bool
+	StatusCode int
+	Latency    time.Duration
+	Status     HealthStatus
+}
+
+// CheckHealth sends a single authenticated GET to endpoint and classifies its readiness, e.g. to back a service's
+// own /healthz. The probe bypasses retries, concurrency permits and the mandatory request delay, and is sent with
+// Cache-Control: no-cache so a ResponseCache cannot answer it; it measures the endpoint rather than the client.
+// An endpoint is:
+//   - Down when unreachable, answering 5xx, or slower than HealthDownLatency.
+//   - Degraded when answering 4xx, or slower than HealthDegradedLatency.
+//   - Healthy otherwise.
+//
+// A connection failure is reported as a Down result together with the error.
+func (c *Client) CheckHealth(endpoint string) (*HealthResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.healthDownLatency())
+	defer cancel()
+
+	ctx = c.withRequestID(ctx, c.newRequestOptions(nil))
+	log := c.requestLogger(ctx)
+
+	result := &HealthResult{Status: HealthDown}
+
+	url := c.constructURL(endpoint)
+	if err := c.checkHostPolicy(ctx, url); err != nil {
+		return result, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return result, err
+	}
+	if err := c.prepRequestAuth(req); err != nil {
+		return result, err
+	}
+	c.setUserAgent(req, nil)
+	req.Header.Set("Cache-Control", "no-cache")
+	c.setRequestIDHeader(req)
+
+	startTime := time.Now()
+	resp, err := c.do(req)
+	result.Latency = time.Since(startTime)
+	if err != nil {
+		log.Warnw("Health check failed", zap.String("endpoint", endpoint), zap.Duration("latency", result.Latency), zap.Error(err))
+		return result, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	result.Reachable = true
+	result.StatusCode = resp.StatusCode
+	result.Status = c.classifyHealth(resp.StatusCode, result.Latency)
+
+	log.Debugw("Health check complete", zap.String("endpoint", endpoint), zap.Int("status_code", resp.StatusCode), zap.Duration("latency", result.Latency), zap.String("status", string(result.Status)))
+
+	return result, nil
+}
+
+// classifyHealth derives a HealthStatus from a probe's status code and latency.
+func (c *Client) classifyHealth(statusCode int, latency time.Duration) HealthStatus {
+	switch {
+	case statusCode >= http.StatusInternalServerError || latency > c.healthDownLatency():
+		return HealthDown
+	case statusCode >= http.StatusBadRequest || latency > c.healthDegradedLatency():
+		return HealthDegraded
+	default:
+		return HealthHealthy
+	}
+}
+
+// healthDegradedLatency returns the configured Degraded latency threshold or its default.
+func (c *Client) healthDegradedLatency() time.Duration {
+	if c.config.HealthDegradedLatency > 0 {
+		return c.config.HealthDegradedLatency
+	}
+	return DefaultHealthDegradedLatency
+}
+
+// healthDownLatency returns the configured Down latency threshold or its default.
+func (c *Client) healthDownLatency() time.Duration {
+	if c.config.HealthDownLatency > 0 {
+		return c.config.HealthDownLatency
+	}
+	return DefaultHealthDownLatency
+}
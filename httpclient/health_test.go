@@ -0,0 +1,105 @@
+// httpclient/health_test.go
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(30 * time.Millisecond)
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		case "/broken":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.HealthDegradedLatency = 20 * time.Millisecond
+		config.HealthDownLatency = time.Second
+	})
+
+	tests := []struct {
+		endpoint string
+		code     int
+		want     HealthStatus
+	}{
+		{"/ok", http.StatusOK, HealthHealthy},
+		{"/slow", http.StatusOK, HealthDegraded},
+		{"/forbidden", http.StatusForbidden, HealthDegraded},
+		{"/broken", http.StatusServiceUnavailable, HealthDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			result, err := client.CheckHealth(tt.endpoint)
+			if err != nil {
+				t.Fatalf("CheckHealth() error = %v", err)
+			}
+			if !result.Reachable || result.StatusCode != tt.code || result.Status != tt.want {
+				t.Errorf("result = %+v, want reachable %d %s", result, tt.code, tt.want)
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		unreachable := newTestClient(t, "http://127.0.0.1:1", nil)
+		result, err := unreachable.CheckHealth("/")
+		if err == nil {
+			t.Fatal("expected connection error")
+		}
+		if result.Reachable || result.Status != HealthDown {
+			t.Errorf("result = %+v, want unreachable and Down", result)
+		}
+	})
+}
+
+func TestCheckHealth_BypassesResponseCache(t *testing.T) {
+	var broken atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		if broken.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.ResponseCache = &ResponseCacheConfig{}
+	})
+
+	if result, err := client.CheckHealth("/healthz"); err != nil || result.Status != HealthHealthy {
+		t.Fatalf("CheckHealth() = %+v, %v, want Healthy", result, err)
+	}
+	broken.Store(true)
+	result, err := client.CheckHealth("/healthz")
+	if err != nil {
+		t.Fatalf("CheckHealth() error = %v", err)
+	}
+	if result.StatusCode != http.StatusServiceUnavailable || result.Status != HealthDown {
+		t.Errorf("result = %+v, want 503 Down rather than the cached 200", result)
+	}
+}
+
+func TestCheckHealth_HostPolicy(t *testing.T) {
+	client := newTestClient(t, "https://api.example.com", func(config *ClientConfig) {
+		config.DisallowedHostCIDRs = []string{"169.254.0.0/16"}
+	})
+
+	result, err := client.CheckHealth("http://169.254.169.254/latest/meta-data")
+	if !errors.Is(err, ErrHostNotAllowed) {
+		t.Fatalf("CheckHealth() error = %v, want ErrHostNotAllowed", err)
+	}
+	if result.Reachable || result.Status != HealthDown {
+		t.Errorf("result = %+v, want unreachable and Down", result)
+	}
+}
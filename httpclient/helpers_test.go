@@ -0,0 +1,32 @@
+// httpclient/helpers_test.go
+package httpclient
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// newTestClient builds a Client against baseURL, applying configure to the config before Build.
+func newTestClient(t *testing.T, baseURL string, configure func(*ClientConfig)) *Client {
+	t.Helper()
+
+	config := &ClientConfig{
+		Integration: &MockIntegration{
+			BaseURL: baseURL,
+			Token:   "test-token",
+			Headers: map[string]string{"Content-Type": "application/json"},
+		},
+		Sugar: zap.NewNop().Sugar(),
+	}
+	if configure != nil {
+		configure(config)
+	}
+
+	client, err := config.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	return client
+}
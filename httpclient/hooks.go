@@ -0,0 +1,50 @@
+// httpclient/hooks.go
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// runRequestHook hands the OnRequest hook a copy of the request whose body can be read without consuming
+// the body that is about to be sent.
+func (c *Client) runRequestHook(req *http.Request) error {
+	if c.config.OnRequest == nil {
+		return nil
+	}
+
+	hookReq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return fmt.Errorf("failed to copy request body for OnRequest hook: %w", err)
+		}
+		hookReq.Body = body
+	}
+
+	c.config.OnRequest(hookReq)
+	return nil
+}
+
+// runResponseHook buffers the response body and hands the OnResponse hook a copy of the response whose body
+// can be read freely. The original response's body is restored so it can still be unmarshalled afterwards.
+func (c *Client) runResponseHook(resp *http.Response) error {
+	if c.config.OnResponse == nil {
+		return nil
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to buffer response body for OnResponse hook: %w", err)
+	}
+
+	hookResp := *resp
+	hookResp.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	c.config.OnResponse(&hookResp)
+
+	resp.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	return nil
+}
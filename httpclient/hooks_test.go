@@ -0,0 +1,47 @@
+// httpclient/hooks_test.go
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestResponseHooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"echo":` + string(body) + `}`))
+	}))
+	defer server.Close()
+
+	var hookRequestBody, hookResponseBody string
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.OnRequest = func(req *http.Request) {
+			body, _ := io.ReadAll(req.Body)
+			hookRequestBody = string(body)
+		}
+		config.OnResponse = func(resp *http.Response) {
+			body, _ := io.ReadAll(resp.Body)
+			hookResponseBody = string(body)
+		}
+	})
+
+	var out struct {
+		Echo string `json:"echo"`
+	}
+	if _, err := client.DoRequest(http.MethodPost, "/echo", "hello", &out); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+
+	if hookRequestBody != `"hello"` {
+		t.Errorf("OnRequest saw body %q, want %q", hookRequestBody, `"hello"`)
+	}
+	if hookResponseBody != `{"echo":"hello"}` {
+		t.Errorf("OnResponse saw body %q, want %q", hookResponseBody, `{"echo":"hello"}`)
+	}
+	if out.Echo != "hello" {
+		t.Errorf("out.Echo = %q, want hello (server must receive the full body and the response must still be decodable)", out.Echo)
+	}
+}
@@ -0,0 +1,240 @@
+// httpclient/hostpolicy.go
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// ErrHostNotAllowed is returned when a request or redirect targets a host rejected by AllowedHosts or
+// DisallowedHostCIDRs.
+var ErrHostNotAllowed = errors.New("host not allowed by client host policy")
+
+// defaultMaxRedirects matches net/http's own limit, applied when the host policy replaces a nil CheckRedirect.
+const defaultMaxRedirects = 10
+
+// hostPolicy restricts the hosts the client may contact, guarding against SSRF through redirects or injected URLs.
+type hostPolicy struct {
+	allowedHosts []string
+	disallowed   []*net.IPNet
+	resolver     HostResolver
+}
+
+// newHostPolicy parses the configured allowlist and CIDR denylist. It returns nil when neither is set.
+func newHostPolicy(allowedHosts, disallowedCIDRs []string, resolver HostResolver) (*hostPolicy, error) {
+	if len(allowedHosts) == 0 && len(disallowedCIDRs) == 0 {
+		return nil, nil
+	}
+
+	policy := &hostPolicy{resolver: resolver}
+	if policy.resolver == nil {
+		policy.resolver = net.DefaultResolver
+	}
+
+	for _, host := range allowedHosts {
+		policy.allowedHosts = append(policy.allowedHosts, strings.ToLower(strings.TrimSpace(host)))
+	}
+
+	for _, cidr := range disallowedCIDRs {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid disallowed host CIDR %q: %v", cidr, err)
+		}
+		policy.disallowed = append(policy.disallowed, network)
+	}
+
+	return policy, nil
+}
+
+// check returns an ErrHostNotAllowed error when u's host is outside AllowedHosts or resolves into a disallowed range.
+// The transport built by the client enforces the ranges again on the address it dials; this earlier check gives a
+// clearer error before anything is sent.
+func (p *hostPolicy) check(ctx context.Context, u *url.URL) error {
+	host := strings.ToLower(u.Hostname())
+
+	if len(p.allowedHosts) > 0 && !p.hostAllowed(host) {
+		return fmt.Errorf("%w: %s is not in AllowedHosts", ErrHostNotAllowed, host)
+	}
+
+	if len(p.disallowed) == 0 {
+		return nil
+	}
+
+	addrs := []string{host}
+	if net.ParseIP(host) == nil {
+		resolved, err := p.resolver.LookupHost(ctx, host)
+		if err != nil {
+			return fmt.Errorf("%w: failed to resolve %s: %v", ErrHostNotAllowed, host, err)
+		}
+		addrs = resolved
+	}
+
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		for _, network := range p.disallowed {
+			if ip != nil && network.Contains(ip) {
+				return fmt.Errorf("%w: %s resolves to %s in disallowed range %s", ErrHostNotAllowed, host, addr, network)
+			}
+		}
+	}
+
+	return nil
+}
+
+// hostAllowed matches host against AllowedHosts entries, where "*.example.com" matches any subdomain.
+func (p *hostPolicy) hostAllowed(host string) bool {
+	for _, allowed := range p.allowedHosts {
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// checkHostPolicy enforces the host policy, if any, on the URL of a request about to be built.
+func (c *Client) checkHostPolicy(ctx context.Context, target string) error {
+	if c.hostPolicy == nil {
+		return nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+	return c.hostPolicy.check(ctx, u)
+}
+
+// redirectPolicy wraps next, the configured redirect policy (nil for net/http's default), so every redirect target
+// is checked against the host policy first.
+func (p *hostPolicy) redirectPolicy(next *func(req *http.Request, via []*http.Request) error) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if err := p.check(req.Context(), req.URL); err != nil {
+			return err
+		}
+		if next != nil {
+			return (*next)(req, via)
+		}
+		if len(via) >= defaultMaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", defaultMaxRedirects)
+		}
+		return nil
+	}
+}
+
+// checkFollowedURL guards URLs named by a response, such as async operation monitors and next-page links, which
+// the client requests with its credentials. target must have the same scheme and host as origin, the request whose
+// response named it, or be explicitly permitted by AllowedHosts; otherwise ErrHostNotAllowed is returned so the
+// integration's credentials are never sent to a host chosen by the server.
+func (c *Client) checkFollowedURL(origin *url.URL, target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(u.Scheme, origin.Scheme) && strings.EqualFold(u.Host, origin.Host) {
+		return nil
+	}
+	if c.hostPolicy != nil && len(c.hostPolicy.allowedHosts) > 0 && c.hostPolicy.hostAllowed(strings.ToLower(u.Hostname())) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s://%s was named by a response from %s://%s and is not in AllowedHosts", ErrHostNotAllowed, u.Scheme, u.Host, origin.Scheme, origin.Host)
+}
+
+// proxyDialKey marks a dial to a proxy chosen by the transport's Proxy function. Such dials are exempt from the
+// dial-time CIDR check, as the proxy rather than the client connects to the request's host.
+type proxyDialKey struct{}
+
+// dialGuard enforces DisallowedHostCIDRs on the address every connection actually reaches. The transport resolves
+// host names again after the policy check, so without it a host which re-resolves in between (DNS rebinding) could
+// still connect into a disallowed range.
+type dialGuard struct {
+	policy  *hostPolicy
+	proxies sync.Map // Dial addresses ("host:port") of the proxies the transport has been given.
+}
+
+// trackProxies wraps the transport's Proxy function to record the address of every proxy it selects.
+func (g *dialGuard) trackProxies(proxy func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	if proxy == nil {
+		return nil
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := proxy(req)
+		if err == nil && proxyURL != nil {
+			g.proxies.Store(proxyDialAddress(proxyURL), struct{}{})
+		}
+		return proxyURL, err
+	}
+}
+
+// markProxyDials is the outermost dial wrapper: it flags dials to a recorded proxy before any resolution happens.
+func (g *dialGuard) markProxyDials(dial dialContextFunc) dialContextFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if _, ok := g.proxies.Load(address); ok {
+			ctx = context.WithValue(ctx, proxyDialKey{}, true)
+		}
+		return dial(ctx, network, address)
+	}
+}
+
+// dial returns the innermost dial function, which checks the resolved remote address through dialer's Control hook.
+func (g *dialGuard) dial(dialer *net.Dialer) dialContextFunc {
+	guarded := *dialer
+	guarded.Control = g.control
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if proxyDial, _ := ctx.Value(proxyDialKey{}).(bool); proxyDial {
+			return dialer.DialContext(ctx, network, address)
+		}
+		return guarded.DialContext(ctx, network, address)
+	}
+}
+
+// control rejects a connection whose remote IP lies in a disallowed range. It runs after resolution, just before
+// the socket connects.
+func (g *dialGuard) control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: cannot check dialed address %s: %v", ErrHostNotAllowed, address, err)
+	}
+	host, _, _ = strings.Cut(host, "%")
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: cannot check dialed address %s", ErrHostNotAllowed, address)
+	}
+
+	for _, disallowed := range g.policy.disallowed {
+		if disallowed.Contains(ip) {
+			return fmt.Errorf("%w: connection to %s is in disallowed range %s", ErrHostNotAllowed, ip, disallowed)
+		}
+	}
+	return nil
+}
+
+// proxyDialAddress returns the address the transport dials to reach proxyURL, adding the scheme's default port.
+func proxyDialAddress(proxyURL *url.URL) string {
+	port := proxyURL.Port()
+	if port == "" {
+		switch proxyURL.Scheme {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), port)
+}
@@ -0,0 +1,171 @@
+// httpclient/hostpolicy_test.go
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type staticResolver map[string][]string
+
+func (r staticResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addrs, ok := r[host]; ok {
+		return addrs, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func TestHostPolicy_Check(t *testing.T) {
+	resolver := staticResolver{
+		"api.example.com":      {"93.184.216.34"},
+		"internal.example.com": {"10.1.2.3"},
+	}
+
+	tests := []struct {
+		name       string
+		allowed    []string
+		disallowed []string
+		target     string
+		wantErr    bool
+	}{
+		{"exact allowed host", []string{"api.example.com"}, nil, "https://api.example.com/x", false},
+		{"wildcard allowed host", []string{"*.example.com"}, nil, "https://api.example.com/x", false},
+		{"host outside allowlist", []string{"api.example.com"}, nil, "https://evil.test/x", true},
+		{"metadata IP literal", nil, []string{"169.254.0.0/16"}, "http://169.254.169.254/latest", true},
+		{"host resolving into private range", nil, []string{"10.0.0.0/8"}, "https://internal.example.com", true},
+		{"public host passes denylist", nil, []string{"10.0.0.0/8"}, "https://api.example.com", false},
+		{"unresolvable host is rejected", nil, []string{"10.0.0.0/8"}, "https://unknown.example.com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := newHostPolicy(tt.allowed, tt.disallowed, resolver)
+			if err != nil {
+				t.Fatalf("newHostPolicy() error = %v", err)
+			}
+
+			target, _ := url.Parse(tt.target)
+			err = policy.check(context.Background(), target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("check(%s) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrHostNotAllowed) {
+				t.Errorf("check(%s) error = %v, want ErrHostNotAllowed", tt.target, err)
+			}
+		})
+	}
+}
+
+func TestHostPolicy_InvalidCIDR(t *testing.T) {
+	if _, err := newHostPolicy(nil, []string{"not-a-cidr"}, nil); err == nil {
+		t.Error("newHostPolicy() error = nil, want an invalid CIDR error")
+	}
+}
+
+func TestHostPolicy_EnforcedOnRequestsAndRedirects(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(c *ClientConfig) {
+		c.DisallowedHostCIDRs = []string{"169.254.0.0/16"}
+	})
+
+	var out map[string]interface{}
+	resp, err := client.DoRequest(http.MethodGet, "/ok", nil, &out)
+	if err != nil {
+		t.Fatalf("DoRequest() to an allowed host error = %v", err)
+	}
+	resp.Body.Close()
+
+	_, err = client.DoRequest(http.MethodGet, "/redirect", nil, &out)
+	if !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("DoRequest() following a redirect to the metadata endpoint error = %v, want ErrHostNotAllowed", err)
+	}
+
+	denied := newTestClient(t, server.URL, func(c *ClientConfig) {
+		c.AllowedHosts = []string{"api.example.com"}
+	})
+	_, err = denied.DoRequest(http.MethodGet, "/ok", nil, &out)
+	if !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("DoRequest() to a host outside AllowedHosts error = %v, want ErrHostNotAllowed", err)
+	}
+}
+
+// rebindingResolver answers the first lookup of each host with a public address and every later one with loopback,
+// as a DNS-rebinding attacker would.
+type rebindingResolver struct {
+	mu     sync.Mutex
+	looked map[string]bool
+}
+
+func (r *rebindingResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.looked[host] {
+		r.looked[host] = true
+		return []string{"93.184.216.34"}, nil
+	}
+	return []string{"127.0.0.1"}, nil
+}
+
+func TestHostPolicy_EnforcedAtDialTime(t *testing.T) {
+	t.Run("rebinding host is refused", func(t *testing.T) {
+		var hits atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+		}))
+		defer server.Close()
+
+		_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+		client := newTestClient(t, "http://rebind.test:"+port, func(c *ClientConfig) {
+			c.DisallowedHostCIDRs = []string{"127.0.0.0/8"}
+			c.Resolver = &rebindingResolver{looked: map[string]bool{}}
+			c.ReResolveOnConnectionError = true
+		})
+
+		var out map[string]interface{}
+		_, err := client.DoRequest(http.MethodGet, "/latest/meta-data", nil, &out)
+		if !errors.Is(err, ErrHostNotAllowed) {
+			t.Fatalf("DoRequest() error = %v, want ErrHostNotAllowed from the dial", err)
+		}
+		if hits.Load() != 0 {
+			t.Errorf("server received %d requests, want none", hits.Load())
+		}
+	})
+
+	t.Run("proxy in a disallowed range is dialed", func(t *testing.T) {
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{}`))
+		}))
+		defer proxy.Close()
+
+		client := newTestClient(t, "http://api.example.com", func(c *ClientConfig) {
+			c.DisallowedHostCIDRs = []string{"127.0.0.0/8"}
+			c.Resolver = staticResolver{"api.example.com": {"93.184.216.34"}}
+			c.Proxy = &ProxyConfig{URL: proxy.URL}
+		})
+
+		var out map[string]interface{}
+		resp, err := client.DoRequest(http.MethodGet, "/ok", nil, &out)
+		if err != nil {
+			t.Fatalf("DoRequest() through the proxy error = %v", err)
+		}
+		resp.Body.Close()
+	})
+}
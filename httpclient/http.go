@@ -45,6 +45,7 @@ func (c *ProdExecutor) SetRedirectPolicy(policy *func(req *http.Request, via []*
 type MockExecutor struct {
 	LockedResponseCode int
 	ResponseBody       string
+	ResponseHeaders    http.Header
 }
 
 // CloseIdleConnections does nothing.
@@ -62,7 +63,11 @@ func (m *MockExecutor) Do(req *http.Request) (*http.Response, error) {
 	response := &http.Response{
 		StatusCode: m.LockedResponseCode,
 		Body:       io.NopCloser(bytes.NewBufferString(m.ResponseBody)),
-		Header:     make(http.Header),
+		Header:     m.ResponseHeaders.Clone(),
+		Request:    req,
+	}
+	if response.Header == nil {
+		response.Header = make(http.Header)
 	}
 
 	return response, nil
@@ -2,7 +2,9 @@
 package httpclient
 
 import (
+	"encoding/json"
 	"net/http"
+	"time"
 )
 
 // APIIntegration is an interface that defines the methods required for an API integration. These are obtained from go-api-http-client-integrations.
@@ -17,3 +19,82 @@ type APIIntegration interface {
 	MarshalMultipartRequest(fields map[string]string, files map[string]string) ([]byte, string, error)
 	GetSessionCookies() ([]*http.Cookie, error)
 }
+
+// TokenExpiryReporter is optionally implemented by an APIIntegration to report when its current token expires,
+// letting the background refresher wake just before TokenRefreshBufferPeriod rather than polling.
+type TokenExpiryReporter interface {
+	TokenExpiry() time.Time
+}
+
+// TokenSource authenticates requests for a single token scope. Every APIIntegration is a TokenSource, so a second
+// integration configured for another audience can be registered in ClientConfig.ScopedTokenSources.
+type TokenSource interface {
+	CheckRefreshToken() error
+	PrepRequestParamsAndAuth(req *http.Request) error
+}
+
+// Mocking
+
+// MockIntegration is a controllable APIIntegration for tests. It authenticates with a static bearer token, applies
+// fixed headers and marshals bodies as JSON, so tests can assert exactly what the client adds on top.
+type MockIntegration struct {
+	// BaseURL is returned by GetFQDN and prefixed to every endpoint by ConstructURL.
+	BaseURL string
+	// Token, if set, is sent as "Authorization: Bearer <Token>".
+	Token string
+	// Headers are set on every request by PrepRequestParamsAndAuth.
+	Headers map[string]string
+	// RefreshErr is returned by CheckRefreshToken.
+	RefreshErr error
+	// Cookies are returned by GetSessionCookies.
+	Cookies []*http.Cookie
+}
+
+// GetFQDN returns BaseURL.
+func (m *MockIntegration) GetFQDN() string {
+	return m.BaseURL
+}
+
+// ConstructURL returns BaseURL followed by endpoint.
+func (m *MockIntegration) ConstructURL(endpoint string) string {
+	return m.BaseURL + endpoint
+}
+
+// GetAuthMethodDescriptor returns "bearer".
+func (m *MockIntegration) GetAuthMethodDescriptor() string {
+	return "bearer"
+}
+
+// CheckRefreshToken returns RefreshErr.
+func (m *MockIntegration) CheckRefreshToken() error {
+	return m.RefreshErr
+}
+
+// PrepRequestParamsAndAuth sets Headers and, if Token is set, the bearer Authorization header.
+func (m *MockIntegration) PrepRequestParamsAndAuth(req *http.Request) error {
+	for name, value := range m.Headers {
+		req.Header.Set(name, value)
+	}
+	if m.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+m.Token)
+	}
+	return nil
+}
+
+// PrepRequestBody marshals body as JSON. A nil body produces no payload.
+func (m *MockIntegration) PrepRequestBody(body interface{}, method string, endpoint string) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	return json.Marshal(body)
+}
+
+// MarshalMultipartRequest returns an empty payload.
+func (m *MockIntegration) MarshalMultipartRequest(fields map[string]string, files map[string]string) ([]byte, string, error) {
+	return nil, "", nil
+}
+
+// GetSessionCookies returns Cookies.
+func (m *MockIntegration) GetSessionCookies() ([]*http.Cookie, error) {
+	return m.Cookies, nil
+}
@@ -0,0 +1,51 @@
+// httpclient/interceptor.go
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+)
+
+// errInterceptorNoResponse is returned when an interceptor chain yields neither a response nor an error.
+var errInterceptorNoResponse = errors.New("interceptor returned neither a response nor an error")
+
+// Interceptor wraps the sending of a request, in the style of a gRPC interceptor. It may inspect or modify req,
+// call next to continue down the chain, inspect or replace the response, or return without calling next at all
+// (e.g. to answer from a cache).
+//
+// Interceptors registered in ClientConfig.Interceptors run in order, the first being outermost. The chain wraps only
+// the network round trip of each attempt: it runs after the concurrency permit is acquired, the request is built and
+// authenticated, the OnRequest hook has been called and MandatoryRequestDelay has elapsed, and before the response
+// body limit, the OnResponse hook and status handling. Any RequestSigner signs the request after the whole chain.
+// Retries and pagination call the chain afresh for every attempt, so an interceptor sees every request actually sent.
+type Interceptor func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error)
+
+// do sends req through the configured interceptors, then the response cache if enabled, then the RequestSigner if
+// set, to the HTTP executor.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	send := c.http.Do
+	if c.config.RequestSigner != nil {
+		send = c.signAndSend
+	}
+
+	resp, err := chainInterceptors(c.interceptors, send)(req)
+	if resp == nil && err == nil {
+		return nil, errInterceptorNoResponse
+	}
+	return resp, err
+}
+
+// chainInterceptors composes interceptors around final, the first interceptor being outermost.
+func chainInterceptors(interceptors []Interceptor, final func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+	next := final
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := interceptors[i], next
+		if interceptor == nil {
+			continue
+		}
+		next = func(req *http.Request) (*http.Response, error) {
+			return interceptor(req, inner)
+		}
+	}
+	return next
+}
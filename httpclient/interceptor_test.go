@@ -0,0 +1,161 @@
+// httpclient/interceptor_test.go
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoRequest_InterceptorOrder(t *testing.T) {
+	var serverSaw string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverSaw = r.Header.Get("X-Layers")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var calls []string
+	layer := func(name string) Interceptor {
+		return func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+			calls = append(calls, name+" before")
+			req.Header.Add("X-Layers", name)
+			resp, err := next(req)
+			calls = append(calls, name+" after")
+			return resp, err
+		}
+	}
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.Interceptors = []Interceptor{layer("outer"), nil, layer("inner")}
+	})
+
+	var out map[string]interface{}
+	resp, err := client.DoRequest(http.MethodGet, "/resource", nil, &out)
+	if err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	resp.Body.Close()
+
+	want := []string{"outer before", "inner before", "inner after", "outer after"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("interceptor calls = %v, want %v", calls, want)
+	}
+	if serverSaw != "outer" {
+		t.Errorf("server saw first X-Layers = %q, want the outer interceptor's", serverSaw)
+	}
+}
+
+func TestDoRequest_InterceptorRunsPerAttempt(t *testing.T) {
+	var served atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if served.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var intercepted atomic.Int32
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.RetryEligiableRequests = true
+		config.MaxRetryAttempts = 3
+		config.TotalRetryDuration = 10 * time.Second
+		config.Interceptors = []Interceptor{
+			func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+				intercepted.Add(1)
+				return next(req)
+			},
+		}
+	})
+
+	var out map[string]interface{}
+	resp, err := client.DoRequest(http.MethodGet, "/resource", nil, &out)
+	if err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if got, want := intercepted.Load(), served.Load(); got != want || got != 3 {
+		t.Errorf("interceptor ran %d times for %d attempts, want 3 for 3", got, want)
+	}
+}
+
+func TestDoRequest_InterceptorShortCircuit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached the server despite a short-circuiting interceptor")
+	}))
+	defer server.Close()
+
+	cached := func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"name":"cached"}`)),
+			Request:    req,
+		}, nil
+	}
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.Interceptors = []Interceptor{cached}
+	})
+
+	var out struct {
+		Name string `json:"name"`
+	}
+	resp, err := client.DoRequest(http.MethodGet, "/resource", nil, &out)
+	if err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if out.Name != "cached" {
+		t.Errorf("out.Name = %q, want the interceptor's response", out.Name)
+	}
+}
+
+func TestDoRequest_InterceptorErrors(t *testing.T) {
+	errDenied := errors.New("denied by policy")
+
+	tests := []struct {
+		name        string
+		interceptor Interceptor
+		want        error
+	}{
+		{
+			name: "error is returned",
+			interceptor: func(*http.Request, func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+				return nil, errDenied
+			},
+			want: errDenied,
+		},
+		{
+			name: "no response and no error",
+			interceptor: func(*http.Request, func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+				return nil, nil
+			},
+			want: errInterceptorNoResponse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, "http://192.0.2.1", func(config *ClientConfig) {
+				config.Interceptors = []Interceptor{tt.interceptor}
+			})
+
+			_, err := client.DoRequest(http.MethodPost, "/resource", nil, nil)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("DoRequest() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// httpclient/limits.go
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrRequestBodyTooLarge is returned, wrapped with the sizes involved, when a marshalled request body exceeds
+// MaxRequestBodyBytes. The request is not sent.
+var ErrRequestBodyTooLarge = errors.New("request body too large")
+
+// ResponseTooLargeError is returned when a response body exceeds MaxResponseBodyBytes.
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+// Error implements the error interface.
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds the limit of %d bytes", e.Limit)
+}
+
+// limitedBody reads at most limit bytes from the wrapped body, failing with ResponseTooLargeError if the
+// body holds more, rather than silently truncating it like io.LimitReader.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+}
+
+// Read implements io.Reader.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, &ResponseTooLargeError{Limit: b.limit}
+	}
+
+	// Allow one byte past the limit so an over-sized body can be told apart from one of exactly limit bytes.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), &ResponseTooLargeError{Limit: b.limit}
+	}
+
+	return n, err
+}
+
+// checkRequestBodySize rejects request bodies larger than MaxRequestBodyBytes.
+func (c *Client) checkRequestBodySize(body []byte) error {
+	if c.config.MaxRequestBodyBytes > 0 && int64(len(body)) > c.config.MaxRequestBodyBytes {
+		return fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes", ErrRequestBodyTooLarge, len(body), c.config.MaxRequestBodyBytes)
+	}
+	return nil
+}
+
+// limitResponseBody enforces MaxResponseBodyBytes on a response, failing immediately when the declared
+// Content-Length is already over the limit and otherwise as the body is read.
+func (c *Client) limitResponseBody(resp *http.Response) error {
+	limit := c.config.MaxResponseBodyBytes
+	if limit <= 0 {
+		return nil
+	}
+
+	if resp.ContentLength > limit {
+		resp.Body.Close()
+		return &ResponseTooLargeError{Limit: limit}
+	}
+
+	resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: limit, limit: limit}
+	return nil
+}
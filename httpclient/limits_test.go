@@ -0,0 +1,73 @@
+// httpclient/limits_test.go
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxResponseBodyBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		payload := `{"data":"` + strings.Repeat("x", 100) + `"}`
+		if r.URL.Path == "/chunked" {
+			// Flushing before writing forces chunked encoding, so no Content-Length is declared.
+			w.(http.Flusher).Flush()
+		}
+		w.Write([]byte(payload))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		limit   int64
+		path    string
+		wantErr bool
+	}{
+		{"unlimited", 0, "/sized", false},
+		{"within limit", 1024, "/chunked", false},
+		{"content length over limit", 50, "/sized", true},
+		{"streamed over limit", 50, "/chunked", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, server.URL, func(config *ClientConfig) {
+				config.MaxResponseBodyBytes = tt.limit
+			})
+
+			var out map[string]interface{}
+			resp, err := client.DoRequest(http.MethodGet, tt.path, nil, &out)
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			var tooLarge *ResponseTooLargeError
+			if got := errors.As(err, &tooLarge); got != tt.wantErr {
+				t.Fatalf("DoRequest() error = %v, want ResponseTooLargeError: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(out["data"].(string)) != 100 {
+				t.Errorf("data truncated: %v", out)
+			}
+		})
+	}
+}
+
+func TestMaxRequestBodyBytes(t *testing.T) {
+	executor := &recordingExecutor{MockExecutor: MockExecutor{LockedResponseCode: http.StatusOK}}
+	client := newTestClient(t, "http://192.0.2.1", func(config *ClientConfig) {
+		config.HTTPExecutor = executor
+		config.MaxRequestBodyBytes = 16
+	})
+
+	_, err := client.DoRequest(http.MethodPost, "/items", map[string]string{"name": strings.Repeat("x", 32)}, nil)
+	if !errors.Is(err, ErrRequestBodyTooLarge) {
+		t.Fatalf("DoRequest() error = %v, want ErrRequestBodyTooLarge", err)
+	}
+	if len(executor.requests) != 0 {
+		t.Errorf("%d requests sent, want none", len(executor.requests))
+	}
+}
@@ -0,0 +1,155 @@
+// httpclient/logging.go
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/deploymenttheory/go-api-http-client/ratehandler"
+	"go.uber.org/zap"
+)
+
+// DefaultMaxLoggedBodyBytes is the largest request or response payload logged in full when
+// ClientConfig.MaxLoggedBodyBytes is not set. Larger payloads are logged as a size summary.
+const DefaultMaxLoggedBodyBytes = 4096
+
+// readCloser pairs a replacement reader with the original body's Close.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// defaultLogger builds the zap production logger used when no Sugar is supplied, writing to LogOutputPaths and
+// LogErrorOutputPaths when set. zap opens every path up front, so an unwritable path fails here rather than later.
+func (c *ClientConfig) defaultLogger() (*zap.Logger, error) {
+	zapConfig := zap.NewProductionConfig()
+	if len(c.LogOutputPaths) > 0 {
+		zapConfig.OutputPaths = c.LogOutputPaths
+	}
+	if len(c.LogErrorOutputPaths) > 0 {
+		zapConfig.ErrorOutputPaths = c.LogErrorOutputPaths
+	}
+
+	logger, err := zapConfig.Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build logger: %w", err)
+	}
+	return logger, nil
+}
+
+// SetLogger replaces the client's logger, including the one used by its ConcurrencyHandler, e.g. to attach fields
+// or redirect logs once the application has configured logging. A nil logger is ignored. It is safe to call while
+// requests are in flight; each log line goes to whichever logger is current when it is written.
+func (c *Client) SetLogger(sugar *zap.SugaredLogger) {
+	if sugar == nil {
+		return
+	}
+
+	c.logger.Store(sugar)
+	if c.Concurrency != nil {
+		c.Concurrency.SetLogger(sugar)
+	}
+}
+
+// Logger returns the logger the client currently writes to: the one installed by SetLogger, or Sugar.
+func (c *Client) Logger() *zap.SugaredLogger {
+	if sugar := c.logger.Load(); sugar != nil {
+		return sugar
+	}
+	return c.Sugar
+}
+
+// maxLoggedBodyBytes returns the configured payload logging threshold.
+func (c *Client) maxLoggedBodyBytes() int {
+	if c.config.MaxLoggedBodyBytes > 0 {
+		return c.config.MaxLoggedBodyBytes
+	}
+	return DefaultMaxLoggedBodyBytes
+}
+
+// debugEnabled reports whether debug level logs are written, so payload logging costs nothing otherwise.
+func (c *Client) debugEnabled() bool {
+	return c.Logger().Desugar().Core().Enabled(zap.DebugLevel)
+}
+
+// logRequestBody logs an outgoing payload at debug level, or a size summary if it exceeds the threshold.
+func (c *Client) logRequestBody(method, endpoint string, body []byte) {
+	if len(body) == 0 || !c.debugEnabled() {
+		return
+	}
+
+	fields := []interface{}{zap.String("method", method), zap.String("endpoint", endpoint), zap.Int("body_bytes", len(body))}
+	if c.config.HideSensitiveData || len(body) > c.maxLoggedBodyBytes() {
+		c.Logger().Debugw("Request body summary", fields...)
+		return
+	}
+
+	c.Logger().Debugw("Request body", append(fields, zap.ByteString("body", body))...)
+}
+
+// logResponseBody logs a response payload at debug level, or a size summary if it exceeds the threshold.
+// At most threshold+1 bytes are read ahead and the body is restored so it remains fully readable afterwards.
+func (c *Client) logResponseBody(method, endpoint string, resp *http.Response) {
+	if resp.Body == nil || resp.Body == http.NoBody || !c.debugEnabled() {
+		return
+	}
+
+	fields := []interface{}{zap.String("method", method), zap.String("endpoint", endpoint), zap.Int("status_code", resp.StatusCode)}
+	if id := RequestIDFromResponse(resp); id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	maxBytes := int64(c.maxLoggedBodyBytes())
+
+	if c.config.HideSensitiveData || resp.ContentLength > maxBytes {
+		c.Logger().Debugw("Response body summary", append(fields, zap.Int64("body_bytes", resp.ContentLength))...)
+		return
+	}
+
+	peeked, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(peeked), resp.Body), Closer: resp.Body}
+	if err != nil {
+		c.Logger().Debugw("Unable to read response body for logging", append(fields, zap.Error(err))...)
+		return
+	}
+
+	if int64(len(peeked)) > maxBytes {
+		c.Logger().Debugw("Response body summary", append(fields, zap.Int64("body_bytes", resp.ContentLength), zap.String("note", "body exceeds logging threshold"))...)
+		return
+	}
+
+	c.Logger().Debugw("Response body", append(fields, zap.ByteString("body", peeked))...)
+}
+
+// logRateLimitInfo logs the server's rate limit position when the response reports one, so operators can follow
+// how close the client is to its quota. An exhausted quota is logged at info level, anything else at debug.
+func (c *Client) logRateLimitInfo(method, endpoint string, resp *http.Response) {
+	state := ratehandler.ParseRateLimitInfo(resp, c.Logger())
+	if !state.Reported() {
+		return
+	}
+
+	fields := []interface{}{
+		zap.String("method", method),
+		zap.String("endpoint", endpoint),
+		zap.Int("limit", state.Limit),
+		zap.Int("remaining", state.Remaining),
+	}
+	if id := RequestIDFromResponse(resp); id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	if state.HasReset {
+		fields = append(fields, zap.Float64("reset_in_seconds", time.Until(state.Reset).Seconds()))
+	}
+	if state.HasRetryAfter {
+		fields = append(fields, zap.Duration("retry_after", state.RetryAfter))
+	}
+
+	if state.Remaining == 0 {
+		c.Logger().Infow("Rate limit exhausted", fields...)
+		return
+	}
+	c.Logger().Debugw("Rate limit state", fields...)
+}
@@ -0,0 +1,303 @@
+// httpclient/logging_test.go
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogResponseBody_Threshold(t *testing.T) {
+	largeValue := strings.Repeat("x", 10000)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/large" {
+			w.Write([]byte(`{"value":"` + largeValue + `"}`))
+			return
+		}
+		w.Write([]byte(`{"value":"small"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		endpoint    string
+		wantMessage string
+		wantValue   string
+		wantBody    bool
+	}{
+		{name: "large body is summarised", endpoint: "/large", wantMessage: "Response body summary", wantValue: largeValue, wantBody: false},
+		{name: "small body is logged", endpoint: "/small", wantMessage: "Response body", wantValue: "small", wantBody: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			client := newTestClient(t, server.URL, func(config *ClientConfig) {
+				config.Sugar = zap.New(core).Sugar()
+				config.MaxLoggedBodyBytes = 100
+			})
+
+			var out struct {
+				Value string `json:"value"`
+			}
+			if _, err := client.DoRequest(http.MethodGet, tt.endpoint, nil, &out); err != nil {
+				t.Fatalf("DoRequest() error = %v", err)
+			}
+
+			if out.Value != tt.wantValue {
+				t.Errorf("body was not fully readable after logging, got %d bytes of value, want %d", len(out.Value), len(tt.wantValue))
+			}
+
+			entries := logs.FilterMessage(tt.wantMessage).All()
+			if len(entries) != 1 {
+				t.Fatalf("found %d %q log entries, want 1", len(entries), tt.wantMessage)
+			}
+			if _, hasBody := entries[0].ContextMap()["body"]; hasBody != tt.wantBody {
+				t.Errorf("log entry has body field = %v, want %v", hasBody, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestLogRateLimitInfo(t *testing.T) {
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		wantMessage string
+	}{
+		{name: "no headers logs nothing"},
+		{
+			name:        "quota remaining is logged at debug",
+			headers:     map[string]string{"X-RateLimit-Limit": "100", "X-RateLimit-Remaining": "42", "X-RateLimit-Reset": strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)},
+			wantMessage: "Rate limit state",
+		},
+		{
+			name:        "exhausted quota is logged at info",
+			headers:     map[string]string{"X-RateLimit-Limit": "100", "X-RateLimit-Remaining": "0"},
+			wantMessage: "Rate limit exhausted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			client := newTestClient(t, "https://example.com", func(config *ClientConfig) {
+				config.Sugar = zap.New(core).Sugar()
+			})
+
+			logs.TakeAll() // discard Build's own log lines
+
+			resp := &http.Response{Header: http.Header{}}
+			for name, value := range tt.headers {
+				resp.Header.Set(name, value)
+			}
+			client.logRateLimitInfo(http.MethodGet, "/resource", resp)
+
+			if tt.wantMessage == "" {
+				if logs.Len() != 0 {
+					t.Errorf("logged %d entries without rate limit headers, want 0", logs.Len())
+				}
+				return
+			}
+
+			entries := logs.FilterMessage(tt.wantMessage).All()
+			if len(entries) != 1 {
+				t.Fatalf("found %d %q log entries, want 1", len(entries), tt.wantMessage)
+			}
+			fields := entries[0].ContextMap()
+			if fields["limit"] != int64(100) {
+				t.Errorf("limit field = %v, want 100", fields["limit"])
+			}
+			if _, ok := fields["remaining"]; !ok {
+				t.Error("remaining field missing")
+			}
+			if _, ok := fields["reset_in_seconds"]; ok != (tt.headers["X-RateLimit-Reset"] != "") {
+				t.Errorf("reset_in_seconds present = %v, want %v", ok, !ok)
+			}
+		})
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.EnableConcurrencyManagement = true
+		config.MaxConcurrentRequests = 1
+		config.ResponseCache = &ResponseCacheConfig{}
+	})
+
+	client.SetLogger(nil)
+	if client.Logger() == nil {
+		t.Fatal("SetLogger(nil) cleared the logger")
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	client.SetLogger(zap.New(core).Sugar())
+
+	var out map[string]interface{}
+	for i := 0; i < 2; i++ {
+		if _, err := client.DoRequest(http.MethodGet, "/resource", nil, &out); err != nil {
+			t.Fatalf("DoRequest() error = %v", err)
+		}
+	}
+
+	if logs.Len() == 0 {
+		t.Fatal("no entries written to the replacement logger")
+	}
+	if logs.FilterMessageSnippet("Evaluate and Adjust Concurrency").Len() == 0 {
+		t.Error("ConcurrencyHandler did not log to the replacement logger")
+	}
+	if logs.FilterMessage("Serving response from cache").Len() == 0 {
+		t.Error("response cache did not log to the replacement logger")
+	}
+}
+
+func TestSetLogger_DuringRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.Sugar = zap.New(zapcore.NewNopCore()).Sugar()
+		config.EnableConcurrencyManagement = true
+		config.MaxConcurrentRequests = 4
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				var out map[string]interface{}
+				if _, err := client.DoRequest(http.MethodGet, "/resource", nil, &out); err != nil {
+					t.Errorf("DoRequest() error = %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	var latest *observer.ObservedLogs
+	for swapping := true; swapping; {
+		core, logs := observer.New(zapcore.DebugLevel)
+		client.SetLogger(zap.New(core).Sugar())
+		latest = logs
+
+		select {
+		case <-finished:
+			swapping = false
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	var out map[string]interface{}
+	if _, err := client.DoRequest(http.MethodGet, "/resource", nil, &out); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	if latest.Len() == 0 {
+		t.Error("no entries written to the last logger installed")
+	}
+}
+
+func TestDisableLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"value":"ok"}`))
+	}))
+	defer server.Close()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.Sugar = zap.New(core).Sugar()
+		config.DisableLogging = true
+	})
+
+	var out struct {
+		Value string `json:"value"`
+	}
+	if _, err := client.DoRequest(http.MethodGet, "/resource", nil, &out); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	if out.Value != "ok" {
+		t.Errorf("value = %q, want ok", out.Value)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("logged %d entries with DisableLogging set, want 0", logs.Len())
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		client.Logger().Debugw("Suppressed", "endpoint", "/resource")
+	})
+	if allocs != 0 {
+		t.Errorf("suppressed log call allocated %v times, want 0", allocs)
+	}
+}
+
+func TestLogOutputPaths(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "client.log")
+
+	tests := []struct {
+		name    string
+		paths   []string
+		wantErr bool
+	}{
+		{name: "writable file", paths: []string{logPath}},
+		{name: "unwritable path", paths: []string{filepath.Join(dir, "missing", "client.log")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ClientConfig{
+				Integration:         &MockIntegration{BaseURL: "https://example.com"},
+				LogOutputPaths:      tt.paths,
+				LogErrorOutputPaths: tt.paths,
+			}
+
+			client, err := config.Build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			client.Logger().Infow("written to file")
+			client.Logger().Sync()
+
+			contents, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatalf("reading log file: %v", err)
+			}
+			if !strings.Contains(string(contents), "written to file") {
+				t.Errorf("log file does not contain the entry: %s", contents)
+			}
+		})
+	}
+}
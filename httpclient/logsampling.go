@@ -0,0 +1,67 @@
+// httpclient/logsampling.go
+package httpclient
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// logSampler coalesces repeated warnings with the same message. The first occurrence in each interval is logged;
+// later ones are counted and reported on the first occurrence of the next interval, so a retry storm produces
+// one line per interval instead of one per attempt.
+type logSampler struct {
+	interval time.Duration
+	entries  map[string]*sampledEntry
+	sync.Mutex
+}
+
+// sampledEntry tracks one message's current interval and how many occurrences it has suppressed.
+type sampledEntry struct {
+	windowStart time.Time
+	suppressed  int
+}
+
+// newLogSampler returns a sampler for interval, or nil when interval is not positive (sampling disabled).
+func newLogSampler(interval time.Duration) *logSampler {
+	if interval <= 0 {
+		return nil
+	}
+	return &logSampler{interval: interval, entries: make(map[string]*sampledEntry)}
+}
+
+// sample reports whether msg should be logged now and how many occurrences were suppressed since it was last logged.
+func (s *logSampler) sample(msg string) (bool, int) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+	entry, ok := s.entries[msg]
+	if ok && now.Sub(entry.windowStart) < s.interval {
+		entry.suppressed++
+		return false, 0
+	}
+
+	suppressed := 0
+	if ok {
+		suppressed = entry.suppressed
+	}
+	s.entries[msg] = &sampledEntry{windowStart: now}
+	return true, suppressed
+}
+
+// warnSampled logs a warning from a hot retry or backoff path, subject to LogSamplingInterval.
+func (c *Client) warnSampled(msg string, keysAndValues ...interface{}) {
+	if c.logSampler != nil {
+		log, suppressed := c.logSampler.sample(msg)
+		if !log {
+			return
+		}
+		if suppressed > 0 {
+			keysAndValues = append(keysAndValues, zap.Int("suppressed", suppressed))
+		}
+	}
+
+	c.Logger().Warnw(msg, keysAndValues...)
+}
@@ -0,0 +1,59 @@
+// httpclient/logsampling_test.go
+package httpclient
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWarnSampled(t *testing.T) {
+	tests := []struct {
+		name           string
+		interval       time.Duration
+		wantFirst      int
+		wantSuppressed interface{}
+	}{
+		{name: "disabled logs every warning", interval: 0, wantFirst: 5},
+		{name: "enabled coalesces repeats", interval: 50 * time.Millisecond, wantFirst: 1, wantSuppressed: int64(4)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			client := newTestClient(t, "https://example.com", func(config *ClientConfig) {
+				config.Sugar = zap.New(core).Sugar()
+				config.LogSamplingInterval = tt.interval
+			})
+
+			for i := 0; i < 5; i++ {
+				client.warnSampled("Retrying request due to transient error", zap.Int("retryCount", i))
+			}
+			client.warnSampled("Rate limit encountered, waiting before retrying")
+
+			if got := logs.FilterMessage("Retrying request due to transient error").Len(); got != tt.wantFirst {
+				t.Fatalf("logged %d retry warnings, want %d", got, tt.wantFirst)
+			}
+			if got := logs.FilterMessage("Rate limit encountered, waiting before retrying").Len(); got != 1 {
+				t.Errorf("logged %d rate limit warnings, want 1; messages are sampled independently", got)
+			}
+			if tt.interval == 0 {
+				return
+			}
+
+			time.Sleep(tt.interval)
+			client.warnSampled("Retrying request due to transient error", zap.Int("retryCount", 5))
+
+			entries := logs.FilterMessage("Retrying request due to transient error").All()
+			if len(entries) != 2 {
+				t.Fatalf("logged %d retry warnings after the interval, want 2", len(entries))
+			}
+			if got := entries[1].ContextMap()["suppressed"]; got != tt.wantSuppressed {
+				t.Errorf("suppressed = %v, want %v", got, tt.wantSuppressed)
+			}
+		})
+	}
+}
@@ -1,7 +1,12 @@
-// httpmethod/httpmethod.go
+// httpclient/methods.go
 package httpclient
 
-import "net/http"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
 
 /* Ref: https://www.rfc-editor.org/rfc/rfc7231#section-8.1.3
 
@@ -19,19 +24,74 @@ import "net/http"
 +---------+------+------------+
 */
 
-// IsIdempotentHTTPMethod checks if the given HTTP method is idempotent.
-func isIdempotentHTTPMethod(method string) bool {
-	methodsMap := map[string]bool{
-		http.MethodGet:     true,
-		http.MethodPut:     true,
-		http.MethodDelete:  true,
-		http.MethodHead:    true,
-		http.MethodOptions: true,
-		http.MethodTrace:   true,
-		http.MethodPost:    false,
-		http.MethodPatch:   false,
-		http.MethodConnect: false,
+// ErrUnsupportedMethod is returned, wrapped with the offending value, when a request names an unknown HTTP method.
+var ErrUnsupportedMethod = errors.New("http method not supported")
+
+// HTTPMethod is a request method known to the client. The string based request functions accept any spelling of
+// these, e.g. "get" or " GET", and normalise it before sending.
+type HTTPMethod string
+
+const (
+	MethodGet     HTTPMethod = http.MethodGet
+	MethodHead    HTTPMethod = http.MethodHead
+	MethodPost    HTTPMethod = http.MethodPost
+	MethodPut     HTTPMethod = http.MethodPut
+	MethodPatch   HTTPMethod = http.MethodPatch
+	MethodDelete  HTTPMethod = http.MethodDelete
+	MethodConnect HTTPMethod = http.MethodConnect
+	MethodOptions HTTPMethod = http.MethodOptions
+	MethodTrace   HTTPMethod = http.MethodTrace
+)
+
+// idempotentMethods records, for every supported method, whether it is idempotent.
+var idempotentMethods = map[HTTPMethod]bool{
+	MethodGet:     true,
+	MethodPut:     true,
+	MethodDelete:  true,
+	MethodHead:    true,
+	MethodOptions: true,
+	MethodTrace:   true,
+	MethodPost:    false,
+	MethodPatch:   false,
+	MethodConnect: false,
+}
+
+// ParseHTTPMethod normalises method to upper case without surrounding whitespace and checks it is supported.
+func ParseHTTPMethod(method string) (HTTPMethod, error) {
+	m := HTTPMethod(strings.ToUpper(strings.TrimSpace(method)))
+	if !m.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedMethod, method)
 	}
+	return m, nil
+}
+
+// Valid reports whether m is one of the supported methods.
+func (m HTTPMethod) Valid() bool {
+	_, ok := idempotentMethods[m]
+	return ok
+}
 
-	return methodsMap[method]
+// IsIdempotent reports whether m may be safely repeated.
+func (m HTTPMethod) IsIdempotent() bool {
+	return idempotentMethods[m]
+}
+
+// String returns the method as sent on the wire.
+func (m HTTPMethod) String() string {
+	return string(m)
+}
+
+// normalizeHTTPMethod returns method in its canonical form, or an error wrapping ErrUnsupportedMethod.
+func normalizeHTTPMethod(method string) (string, error) {
+	m, err := ParseHTTPMethod(method)
+	if err != nil {
+		return "", err
+	}
+	return m.String(), nil
+}
+
+// isIdempotentHTTPMethod checks if the given HTTP method is idempotent, whatever its case.
+func isIdempotentHTTPMethod(method string) bool {
+	m, err := ParseHTTPMethod(method)
+	return err == nil && m.IsIdempotent()
 }
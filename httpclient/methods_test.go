@@ -1,8 +1,10 @@
-// httpmethod/httpmethod.go
+// httpclient/methods_test.go
 package httpclient
 
 import (
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -29,6 +31,27 @@ func Test_isIdempotentHTTPMethod(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "lower case idempotent method",
+			args: args{
+				method: "get",
+			},
+			want: true,
+		},
+		{
+			name: "idempotent method with surrounding whitespace",
+			args: args{
+				method: " PUT ",
+			},
+			want: true,
+		},
+		{
+			name: "unknown method",
+			args: args{
+				method: "GETT",
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -38,3 +61,60 @@ func Test_isIdempotentHTTPMethod(t *testing.T) {
 		})
 	}
 }
+
+func TestParseHTTPMethod(t *testing.T) {
+	tests := []struct {
+		method  string
+		want    HTTPMethod
+		wantErr bool
+	}{
+		{method: "GET", want: MethodGet},
+		{method: "patch", want: MethodPatch},
+		{method: "Delete\n", want: MethodDelete},
+		{method: "GET /", wantErr: true},
+		{method: "FETCH", wantErr: true},
+		{method: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			got, err := ParseHTTPMethod(tt.method)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsupportedMethod) {
+					t.Fatalf("ParseHTTPMethod(%q) error = %v, want ErrUnsupportedMethod", tt.method, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseHTTPMethod(%q) = %q, %v, want %q", tt.method, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoRequestNormalizesMethod(t *testing.T) {
+	var gotMethod string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, nil)
+
+	var out map[string]interface{}
+	if _, err := client.DoRequest("get ", "/widgets", nil, &out); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	if gotMethod != http.MethodGet {
+		t.Errorf("server saw method %q, want GET", gotMethod)
+	}
+
+	gotMethod = ""
+	if _, err := client.DoRequest("FETCH", "/widgets", nil, &out); !errors.Is(err, ErrUnsupportedMethod) {
+		t.Fatalf("DoRequest() error = %v, want ErrUnsupportedMethod", err)
+	}
+	if gotMethod != "" {
+		t.Errorf("unsupported method reached the server as %q", gotMethod)
+	}
+}
@@ -13,17 +13,72 @@ import (
 	"sync"
 	"time"
 
+	"github.com/deploymenttheory/go-api-http-client/ratehandler"
 	"github.com/deploymenttheory/go-api-http-client/response"
 	"go.uber.org/zap"
 )
 
 // UploadState represents the state of an upload operation, including the last uploaded byte.
 // This struct is used to track the progress of file uploads for resumable uploads and to resume uploads from the last uploaded byte.
+// LastUploadedByte only has meaning when resuming a single file upload. A multipart body is always rebuilt whole, so every
+// multipart attempt, including a retry, streams each file with a fresh UploadState starting at offset 0.
 type UploadState struct {
 	LastUploadedByte int64
 	sync.Mutex
 }
 
+// ProgressFunc receives the number of file bytes sent so far and the total across all files of a multipart upload.
+// It is called from the goroutine streaming the request body, one call at a time.
+type ProgressFunc func(bytesSent, totalBytes int64)
+
+// uploadProgress accumulates bytes written across every file of a multipart body and reports them to a ProgressFunc.
+type uploadProgress struct {
+	fn    ProgressFunc
+	sent  int64
+	total int64
+}
+
+// newUploadProgress returns a tracker for files, or nil when fn is nil. Files that cannot be stat'd are left out
+// of the total; opening them fails the upload later.
+func newUploadProgress(fn ProgressFunc, files map[string][]string) *uploadProgress {
+	if fn == nil {
+		return nil
+	}
+
+	p := &uploadProgress{fn: fn}
+	for _, filePaths := range files {
+		for _, filePath := range filePaths {
+			if info, err := os.Stat(filePath); err == nil {
+				p.total += info.Size()
+			}
+		}
+	}
+	return p
+}
+
+// add records n more bytes sent and reports the running total.
+func (p *uploadProgress) add(n int64) {
+	p.sent += n
+	p.fn(p.sent, p.total)
+}
+
+// DefaultMultipartRequestWeight is the number of concurrency tokens a multipart upload holds, reflecting the larger
+// load it places on the server compared to an ordinary request. Override per call with WithRequestWeight.
+const DefaultMultipartRequestWeight = 4
+
+const (
+	// DefaultMultipartChunkSize is the size of the chunks files are read and streamed in when
+	// ClientConfig.MultipartChunkSize is unset.
+	DefaultMultipartChunkSize int64 = 8 * 1024 * 1024 // 8 MB
+
+	// MinMultipartChunkSize is the smallest accepted ClientConfig.MultipartChunkSize, matching GCP Cloud Storage's minimum.
+	MinMultipartChunkSize int64 = 256 * 1024 // 256 KB
+
+	// maxProviderChunkSize is Azure Blob Storage's block size maximum, the lowest of the common providers' maxima.
+	// Larger chunk sizes are accepted with a warning.
+	maxProviderChunkSize int64 = 100 * 1024 * 1024 // 100 MB
+)
+
 // DoMultiPartRequest creates and executes a multipart/form-data HTTP request for file uploads and form fields.
 // This function handles constructing the multipart request body, setting the necessary headers, and executing the request.
 // It supports custom content types and headers for each part of the multipart request, and handles authentication and
@@ -43,6 +98,8 @@ type UploadState struct {
 //     and the value is an http.Header containing the headers for that part.
 //   - out: A pointer to an output variable where the response will be deserialized. This should be a pointer to a struct that
 //     matches the expected response schema.
+//   - opts: Optional RequestOptions, e.g. WithRequestWeight to change the DefaultMultipartRequestWeight the upload holds,
+//     WithProgress to report progress, or WithMultipartRetry to retry transient failures.
 //
 // Returns:
 //   - *http.Response: The HTTP response received from the server. In case of successful execution, this response contains
@@ -64,92 +121,142 @@ type UploadState struct {
 //	}
 //
 // // Use `result` or `resp` as needed
-func (c *Client) DoMultiPartRequest(method, endpoint string, files map[string][]string, formDataFields map[string]string, fileContentTypes map[string]string, formDataPartHeaders map[string]http.Header, encodingType string, out interface{}) (*http.Response, error) {
+func (c *Client) DoMultiPartRequest(method, endpoint string, files map[string][]string, formDataFields map[string]string, fileContentTypes map[string]string, formDataPartHeaders map[string]http.Header, encodingType string, out interface{}, opts ...RequestOption) (*http.Response, error) {
 	if encodingType != "byte" && encodingType != "base64" {
-		c.Sugar.Errorw("Invalid encoding type specified", zap.String("encodingType", encodingType))
+		c.Logger().Errorw("Invalid encoding type specified", zap.String("encodingType", encodingType))
 		return nil, fmt.Errorf("invalid encoding type: %s. Must be 'byte' for rawBytes or 'base64' for base64 encoded content", encodingType)
 	}
 
-	if method != http.MethodPost && method != http.MethodPut {
-		c.Sugar.Error("HTTP method not supported for multipart request", zap.String("method", method))
+	normalized, err := normalizeHTTPMethod(method)
+	if err != nil || (normalized != http.MethodPost && normalized != http.MethodPut) {
+		c.Logger().Errorw("HTTP method not supported for multipart request", zap.String("method", method))
 		return nil, fmt.Errorf("unsupported HTTP method: %s", method)
 	}
+	method = normalized
 
-	url := (*c.Integration).GetFQDN() + endpoint
+	url := c.baseURL() + joinBasePath(c.config.BasePath, endpoint)
 
 	var ctx context.Context
 	var cancel context.CancelFunc
 
 	if c.config.CustomTimeout > 0 {
 		ctx, cancel = context.WithTimeout(context.Background(), c.config.CustomTimeout)
-		c.Sugar.Infow("Using timeout context for multipart request", zap.Duration("custom_timeout_seconds", c.config.CustomTimeout))
+		c.Logger().Infow("Using timeout context for multipart request", zap.Duration("custom_timeout_seconds", c.config.CustomTimeout))
 	} else {
 		ctx = context.Background()
 		cancel = func() {}
-		c.Sugar.Info("Using background context for multipart request. Caller will handle timeouts")
+		c.Logger().Info("Using background context for multipart request. Caller will handle timeouts")
 	}
 	defer cancel()
 
-	var body io.Reader
-	var contentType string
+	ro := c.newRequestOptions(append([]RequestOption{WithRequestWeight(DefaultMultipartRequestWeight)}, opts...))
+	ctx = c.withRequestID(ctx, ro)
 
-	createBody := func() error {
-		var err error
-		body, contentType, err = createStreamingMultipartRequestBody(files, formDataFields, fileContentTypes, formDataPartHeaders, encodingType, c.Sugar)
+	if c.config.EnableConcurrencyManagement {
+		_, requestID, err := c.Concurrency.AcquireWeightedConcurrencyPermit(ctx, ro.weight)
 		if err != nil {
-			c.Sugar.Errorw("Failed to create streaming multipart request body", zap.Error(err))
-		} else {
-			c.Sugar.Infow("Successfully created streaming multipart request body",
-				zap.String("content_type", contentType),
-				zap.String("encoding", encodingType))
+			return nil, fmt.Errorf("failed to acquire concurrency permit: %w", err)
+		}
+		defer c.Concurrency.ReleaseConcurrencyPermit(requestID)
+	}
+
+	var retryCount int
+	for {
+		resp, err := c.sendMultipartRequest(ctx, method, url, endpoint, files, formDataFields, fileContentTypes, formDataPartHeaders, encodingType, ro)
+		if err != nil {
+			if !ro.multipartRetry || !isRetryableNetworkError(err, method) || retryCount >= c.config.MaxRetryAttempts {
+				return nil, err
+			}
+		} else if c.isSuccessStatus(resp.StatusCode) {
+			return resp, response.HandleAPISuccessResponse(resp, out, c.Logger())
+		} else if !ro.multipartRetry || retryCount >= c.config.MaxRetryAttempts ||
+			(!response.IsTransientError(resp.StatusCode) && resp.StatusCode != http.StatusTooManyRequests) {
+			return resp, c.handleErrorResponse(resp)
+		}
+
+		retryCount++
+		waitDuration := c.retryBackoff(retryCount)
+		if resp != nil {
+			if resp.StatusCode == http.StatusTooManyRequests {
+				if rateLimitWait := ratehandler.ParseRateLimitHeaders(resp, c.Logger()); rateLimitWait > 0 {
+					waitDuration = rateLimitWait
+				}
+			}
+			resp.Body.Close()
+		}
+
+		c.warnSampled("Retrying multipart request",
+			zap.String("method", method),
+			zap.String("endpoint", endpoint),
+			zap.Int("retry_count", retryCount),
+			zap.Duration("wait_duration", waitDuration),
+			zap.Error(err))
+		if err := c.backoff.Wait(ctx, waitDuration); err != nil {
+			return nil, err
 		}
-		return err
+	}
+}
+
+// sendMultipartRequest makes a single multipart upload attempt. The streaming body is rebuilt from the files on every
+// call, so each attempt uploads every file from the start.
+func (c *Client) sendMultipartRequest(ctx context.Context, method, url, endpoint string, files map[string][]string, formDataFields map[string]string, fileContentTypes map[string]string, formDataPartHeaders map[string]http.Header, encodingType string, ro *requestOptions) (*http.Response, error) {
+	log := c.requestLogger(ctx)
+	if err := c.checkHostPolicy(ctx, url); err != nil {
+		return nil, err
 	}
 
-	if err := createBody(); err != nil {
-		c.Sugar.Errorw("Failed to create multipart request body", zap.Error(err))
+	body, contentType, err := createStreamingMultipartRequestBody(files, formDataFields, fileContentTypes, formDataPartHeaders, encodingType, c.multipartChunkSize(), newUploadProgress(ro.progress, files), log)
+	if err != nil {
+		log.Errorw("Failed to create streaming multipart request body", zap.Error(err))
 		return nil, err
 	}
+	log.Infow("Successfully created streaming multipart request body",
+		zap.String("content_type", contentType),
+		zap.String("encoding", encodingType))
 
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		c.Sugar.Errorw("Failed to create HTTP request", zap.Error(err))
+		log.Errorw("Failed to create HTTP request", zap.Error(err))
 		return nil, err
 	}
 
-	c.Sugar.Infow("Created HTTP Multipart request",
+	log.Infow("Created HTTP Multipart request",
 		zap.String("method", method),
 		zap.String("url", url),
 		zap.String("content_type", contentType),
 		zap.String("encoding", encodingType))
 
-	(*c.Integration).PrepRequestParamsAndAuth(req)
+	if err := c.prepRequestAuth(req); err != nil {
+		req.Body.Close()
+		log.Errorw("Failed to prepare multipart request authentication", zap.Error(err))
+		return nil, err
+	}
+	c.setUserAgent(req, ro)
 	req.Header.Set("Content-Type", contentType)
+	c.setAcceptLanguage(req)
+	c.setRequestHeaders(req, ro)
+	c.setRequestIDHeader(req)
 
 	startTime := time.Now()
 
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
 	duration := time.Since(startTime)
 
 	if err != nil {
-		c.Sugar.Errorw("Failed to send request",
+		log.Errorw("Failed to send request",
 			zap.String("method", method),
 			zap.String("endpoint", endpoint),
 			zap.Error(err))
 		return nil, err
 	}
 
-	c.Sugar.Debugw("Request sent successfully",
+	log.Debugw("Request sent successfully",
 		zap.String("method", method),
 		zap.String("endpoint", endpoint),
 		zap.Int("status_code", resp.StatusCode),
 		zap.Duration("duration", duration))
 
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return resp, response.HandleAPISuccessResponse(resp, out, c.Sugar)
-	}
-
-	return resp, response.HandleAPIErrorResponse(resp, c.Sugar)
+	return resp, nil
 }
 
 // createStreamingMultipartRequestBody creates a streaming multipart request body with the provided files and form fields.
@@ -165,6 +272,8 @@ func (c *Client) DoMultiPartRequest(method, endpoint string, files map[string][]
 //     content type (e.g., "image/jpeg").
 //   - formDataPartHeaders: A map specifying custom headers for each part of the multipart form data. The key is the field name
 //     and the value is an http.Header containing the headers for that part.
+//   - chunkSize: The size, in bytes, of the chunks each file is read and streamed in.
+//   - progress: Reports bytes sent across all files in place of the progress log lines. May be nil.
 //   - sugar: An instance of a logger implementing the logger.Logger interface, used to sugar informational messages, warnings,
 //     and errors encountered during the construction of the multipart request body.
 //
@@ -173,12 +282,17 @@ func (c *Client) DoMultiPartRequest(method, endpoint string, files map[string][]
 //   - string: The content type of the multipart request body. This includes the boundary string used by the multipart writer.
 //   - error: An error object indicating failure during the construction of the multipart request body. This could be due to issues
 //     such as file reading errors or multipart writer errors.
-func createStreamingMultipartRequestBody(files map[string][]string, formDataFields map[string]string, fileContentTypes map[string]string, formDataPartHeaders map[string]http.Header, encodingType string, sugar *zap.SugaredLogger) (io.Reader, string, error) {
+func createStreamingMultipartRequestBody(files map[string][]string, formDataFields map[string]string, fileContentTypes map[string]string, formDataPartHeaders map[string]http.Header, encodingType string, chunkSize int64, progress *uploadProgress, sugar *zap.SugaredLogger) (io.Reader, string, error) {
 	pr, pw := io.Pipe()
 	writer := multipart.NewWriter(pw)
 
 	go func() {
 		defer func() {
+			if r := recover(); r != nil {
+				sugar.Errorw("Multipart upload aborted by panic", zap.Any("panic", r))
+				pw.CloseWithError(fmt.Errorf("multipart upload panicked: %v", r))
+				return
+			}
 			if err := writer.Close(); err != nil {
 				sugar.Errorw("Failed to close multipart writer", zap.Error(err))
 			}
@@ -193,7 +307,7 @@ func createStreamingMultipartRequestBody(files map[string][]string, formDataFiel
 					zap.String("field_name", fieldName),
 					zap.String("file_path", filePath),
 					zap.String("encoding", encodingType))
-				if err := addFilePartWithEncoding(writer, fieldName, filePath, fileContentTypes, formDataPartHeaders, encodingType, sugar); err != nil {
+				if err := addFilePartWithEncoding(writer, fieldName, filePath, fileContentTypes, formDataPartHeaders, encodingType, chunkSize, progress, sugar); err != nil {
 					sugar.Errorw("Failed to add file part", zap.Error(err))
 					pw.CloseWithError(err)
 					return
@@ -222,11 +336,13 @@ func createStreamingMultipartRequestBody(files map[string][]string, formDataFiel
 //   - fileContentTypes: Map of content types for each file field
 //   - formDataPartHeaders: Map of custom headers for each form field
 //   - encodingType: The encoding to use ('byte' for raw bytes or 'base64' for base64 encoding)
+//   - chunkSize: The size, in bytes, of the chunks the file is read and streamed in
+//   - progress: Reports bytes sent in place of the progress log lines when not nil
 //   - sugar: Logger for progress and debug information
 //
 // Returns:
 //   - error: Any error encountered during the file part creation or upload process
-func addFilePartWithEncoding(writer *multipart.Writer, fieldName, filePath string, fileContentTypes map[string]string, formDataPartHeaders map[string]http.Header, encodingType string, sugar *zap.SugaredLogger) error {
+func addFilePartWithEncoding(writer *multipart.Writer, fieldName, filePath string, fileContentTypes map[string]string, formDataPartHeaders map[string]http.Header, encodingType string, chunkSize int64, progress *uploadProgress, sugar *zap.SugaredLogger) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		sugar.Errorw("Failed to open file", zap.String("filePath", filePath), zap.Error(err))
@@ -257,7 +373,13 @@ func addFilePartWithEncoding(writer *multipart.Writer, fieldName, filePath strin
 		return err
 	}
 
-	progressLogger := logUploadProgress(file, fileSize.Size(), sugar)
+	var progressLogger func(int64)
+	if progress != nil {
+		progressLogger = progress.add
+	} else {
+		progressLogger = logUploadProgress(file, fileSize.Size(), sugar)
+	}
+	// The multipart body is rebuilt from scratch on every attempt, so never carry an offset over from a failed one.
 	uploadState := &UploadState{}
 
 	var writeTarget io.Writer = part
@@ -270,7 +392,7 @@ func addFilePartWithEncoding(writer *multipart.Writer, fieldName, filePath strin
 		sugar.Debugw("Using raw encoding for file upload", zap.String("fieldName", fieldName))
 	}
 
-	return chunkFileUpload(file, writeTarget, progressLogger, uploadState, sugar)
+	return chunkFileUpload(file, writeTarget, chunkSize, progressLogger, uploadState, sugar)
 }
 
 // createFilePartHeader creates the MIME header for a file part with the specified encoding type.
@@ -325,7 +447,8 @@ func addFormField(writer *multipart.Writer, key, val string, sugar *zap.SugaredL
 
 // chunkFileUpload reads the file upload into chunks and writes it to the writer.
 // This function reads the file in chunks and writes it to the provided writer, allowing for progress logging during the upload.
-// The chunk size is set to 8192 KB (8 MB) by default. This is a common chunk size used for file uploads to cloud storage services.
+// The chunk size is configured with ClientConfig.MultipartChunkSize and defaults to 8192 KB (8 MB), a common chunk size used
+// for file uploads to cloud storage services.
 // Azure Blob Storage has a minimum chunk size of 4 MB and a maximum of 100 MB for block blobs.
 // GCP Cloud Storage has a minimum chunk size of 256 KB and a maximum of 5 GB.
 // AWS S3 has a minimum chunk size of 5 MB and a maximum of 5 GB.
@@ -333,6 +456,7 @@ func addFormField(writer *multipart.Writer, key, val string, sugar *zap.SugaredL
 // Parameters:
 //   - file: The file to be uploaded.
 //   - writer: The writer to which the file content will be written.
+//   - chunkSize: The size, in bytes, of each chunk read from the file. Values below 1 use DefaultMultipartChunkSize.
 //   - sugar: An instance of a logger implementing the logger.Logger interface, used to sugar informational messages, warnings,
 //     and errors encountered during the file upload.
 //   - updateProgress: A function to update the upload progress, typically used for logging purposes.
@@ -341,8 +465,10 @@ func addFormField(writer *multipart.Writer, key, val string, sugar *zap.SugaredL
 // Returns:
 //   - error: An error object indicating failure during the file upload. This could be due to issues such as file reading errors
 //     or writer errors.
-func chunkFileUpload(file *os.File, writer io.Writer, updateProgress func(int64), uploadState *UploadState, sugar *zap.SugaredLogger) error {
-	const chunkSize = 8 * 1024 * 1024 // 8 MB
+func chunkFileUpload(file *os.File, writer io.Writer, chunkSize int64, updateProgress func(int64), uploadState *UploadState, sugar *zap.SugaredLogger) error {
+	if chunkSize < 1 {
+		chunkSize = DefaultMultipartChunkSize
+	}
 	buffer := make([]byte, chunkSize)
 	totalWritten := int64(0)
 	chunkWritten := int64(0)
@@ -442,3 +568,11 @@ func logUploadProgress(file *os.File, fileSize int64, sugar *zap.SugaredLogger)
 		}
 	}
 }
+
+// multipartChunkSize returns the configured multipart chunk size, or DefaultMultipartChunkSize when unset.
+func (c *Client) multipartChunkSize() int64 {
+	if c.config.MultipartChunkSize > 0 {
+		return c.config.MultipartChunkSize
+	}
+	return DefaultMultipartChunkSize
+}
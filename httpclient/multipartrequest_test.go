@@ -0,0 +1,362 @@
+// httpclient/multipartrequest_test.go
+package httpclient
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestMultipartChunkSizeValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		chunkSize int64
+		wantErr   bool
+	}{
+		{"unset uses default", 0, false},
+		{"minimum", MinMultipartChunkSize, false},
+		{"below minimum", MinMultipartChunkSize - 1, true},
+		{"above provider maximum warns only", maxProviderChunkSize + 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ClientConfig{
+				Integration:        &MockIntegration{BaseURL: "https://example.com"},
+				Sugar:              zap.NewNop().Sugar(),
+				MultipartChunkSize: tt.chunkSize,
+			}
+
+			_, err := config.Build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "multipart chunk size") {
+				t.Errorf("Build() error = %v, want a multipart chunk size error", err)
+			}
+		})
+	}
+}
+
+func TestChunkFileUpload_ChunkSize(t *testing.T) {
+	content := bytes.Repeat([]byte("a"), 1000)
+	path := filepath.Join(t.TempDir(), "upload.bin")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var writes []int64
+	var out bytes.Buffer
+	err = chunkFileUpload(file, &out, 300, func(n int64) { writes = append(writes, n) }, &UploadState{}, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatalf("chunkFileUpload() error = %v", err)
+	}
+
+	if !bytes.Equal(out.Bytes(), content) {
+		t.Errorf("written %d bytes that differ from the file", out.Len())
+	}
+	want := []int64{300, 300, 300, 100}
+	if len(writes) != len(want) {
+		t.Fatalf("writes = %v, want %v", writes, want)
+	}
+	for i := range want {
+		if writes[i] != want[i] {
+			t.Errorf("writes = %v, want %v", writes, want)
+			break
+		}
+	}
+}
+
+func TestDoMultiPartRequest_WithProgress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.bin")
+	second := filepath.Join(dir, "second.bin")
+	if err := os.WriteFile(first, bytes.Repeat([]byte("a"), 700), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, bytes.Repeat([]byte("b"), 300), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newTestClient(t, server.URL, nil)
+
+	var sent, totals []int64
+	progress := func(bytesSent, totalBytes int64) {
+		sent = append(sent, bytesSent)
+		totals = append(totals, totalBytes)
+	}
+
+	files := map[string][]string{"file": {first, second}}
+	var out map[string]interface{}
+	resp, err := client.DoMultiPartRequest(http.MethodPost, "/upload", files, nil, nil, nil, "byte", &out, WithProgress(progress))
+	if err != nil {
+		t.Fatalf("DoMultiPartRequest() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if len(sent) == 0 {
+		t.Fatal("progress callback never invoked")
+	}
+	for i := range sent {
+		if totals[i] != 1000 {
+			t.Errorf("totalBytes = %d, want 1000", totals[i])
+		}
+		if i > 0 && sent[i] <= sent[i-1] {
+			t.Errorf("bytesSent not increasing: %v", sent)
+		}
+	}
+	if last := sent[len(sent)-1]; last != 1000 {
+		t.Errorf("final bytesSent = %d, want 1000", last)
+	}
+}
+
+func TestDoMultiPartRequest_Encodings(t *testing.T) {
+	content := []byte("multipart payload \x00\x01\x02 with binary bytes")
+
+	tests := []struct {
+		encodingType string
+		decode       func([]byte) ([]byte, error)
+	}{
+		{"byte", func(b []byte) ([]byte, error) { return b, nil }},
+		{"base64", func(b []byte) ([]byte, error) { return base64.StdEncoding.DecodeString(string(b)) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encodingType, func(t *testing.T) {
+			var gotPart []byte
+			var gotEncoding, gotField string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					t.Errorf("ParseMultipartForm() error = %v", err)
+					return
+				}
+				gotField = r.FormValue("name")
+				fh := r.MultipartForm.File["file"][0]
+				gotEncoding = fh.Header.Get("Content-Transfer-Encoding")
+				f, _ := fh.Open()
+				gotPart, _ = io.ReadAll(f)
+				f.Close()
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			path := filepath.Join(t.TempDir(), "payload.bin")
+			if err := os.WriteFile(path, content, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			client := newTestClient(t, server.URL, nil)
+
+			var out map[string]interface{}
+			files := map[string][]string{"file": {path}}
+			resp, err := client.DoMultiPartRequest(http.MethodPost, "/upload", files, map[string]string{"name": "payload"}, nil, nil, tt.encodingType, &out)
+			if err != nil {
+				t.Fatalf("DoMultiPartRequest() error = %v", err)
+			}
+			resp.Body.Close()
+
+			decoded, err := tt.decode(gotPart)
+			if err != nil {
+				t.Fatalf("decoding part: %v", err)
+			}
+			if !bytes.Equal(decoded, content) {
+				t.Errorf("part = %q, want %q", decoded, content)
+			}
+			if tt.encodingType == "base64" && gotEncoding != "base64" {
+				t.Errorf("Content-Transfer-Encoding = %q, want base64", gotEncoding)
+			}
+			if gotField != "payload" {
+				t.Errorf("form field = %q, want payload", gotField)
+			}
+		})
+	}
+}
+
+func TestDoMultiPartRequest_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []RequestOption
+		wantAttempts int
+		wantErr      bool
+	}{
+		{"retries transient failures when enabled", []RequestOption{WithMultipartRetry()}, 2, false},
+		{"does not retry by default", nil, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(io.Discard, r.Body)
+				attempts++
+				if attempts == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			path := filepath.Join(t.TempDir(), "payload.bin")
+			if err := os.WriteFile(path, []byte("payload"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			client := newTestClient(t, server.URL, func(c *ClientConfig) { c.MaxRetryAttempts = 2 })
+
+			var out map[string]interface{}
+			resp, err := client.DoMultiPartRequest(http.MethodPost, "/upload", map[string][]string{"file": {path}}, nil, nil, nil, "byte", &out, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DoMultiPartRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if resp != nil {
+				resp.Body.Close()
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestDoMultiPartRequest_RetryUploadsWholeFile(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), 40*1024) // 640 KB, three 256 KB chunks
+
+	var received [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm() error = %v", err)
+			return
+		}
+		f, _ := r.MultipartForm.File["file"][0].Open()
+		part, _ := io.ReadAll(f)
+		f.Close()
+		received = append(received, part)
+
+		if len(received) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "payload.bin")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newTestClient(t, server.URL, func(c *ClientConfig) {
+		c.MaxRetryAttempts = 1
+		c.MultipartChunkSize = MinMultipartChunkSize
+	})
+
+	var out map[string]interface{}
+	resp, err := client.DoMultiPartRequest(http.MethodPut, "/upload", map[string][]string{"file": {path}}, nil, nil, nil, "byte", &out, WithMultipartRetry())
+	if err != nil {
+		t.Fatalf("DoMultiPartRequest() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if len(received) != 2 {
+		t.Fatalf("attempts = %d, want 2", len(received))
+	}
+	for i, part := range received {
+		if !bytes.Equal(part, content) {
+			t.Errorf("attempt %d uploaded %d bytes, want the whole %d byte file", i+1, len(part), len(content))
+		}
+	}
+}
+
+// failingTokenSource cannot authenticate any request.
+type failingTokenSource struct{}
+
+func (failingTokenSource) CheckRefreshToken() error { return nil }
+
+func (failingTokenSource) PrepRequestParamsAndAuth(req *http.Request) error {
+	return errors.New("token unavailable")
+}
+
+func TestDoMultiPartRequest_AuthError(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "file.bin")
+	if err := os.WriteFile(file, []byte("payload"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.ScopedTokenSources = map[string]TokenSource{server.URL: failingTokenSource{}}
+	})
+
+	files := map[string][]string{"file": {file}}
+	if _, err := client.DoMultiPartRequest(http.MethodPost, "/upload", files, nil, nil, nil, "byte", nil); err == nil || err.Error() != "token unavailable" {
+		t.Fatalf("DoMultiPartRequest() error = %v, want the token source's error", err)
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("server received %d requests, want none without credentials", got)
+	}
+}
+
+func TestDoMultiPartRequest_MethodCase(t *testing.T) {
+	var gotMethod string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "payload.bin")
+	if err := os.WriteFile(path, []byte("payload"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newTestClient(t, server.URL, nil)
+	files := map[string][]string{"file": {path}}
+
+	var out map[string]interface{}
+	resp, err := client.DoMultiPartRequest("put", "/upload", files, nil, nil, nil, "byte", &out)
+	if err != nil {
+		t.Fatalf("DoMultiPartRequest(\"put\") error = %v", err)
+	}
+	resp.Body.Close()
+	if gotMethod != http.MethodPut {
+		t.Errorf("method at server = %s, want %s", gotMethod, http.MethodPut)
+	}
+
+	if _, err := client.DoMultiPartRequest("delete", "/upload", files, nil, nil, nil, "byte", &out); err == nil {
+		t.Error("DoMultiPartRequest(\"delete\") error = nil, want the method rejected")
+	}
+}
@@ -0,0 +1,236 @@
+// httpclient/multistatus.go
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// MultiStatusResult is the per-item outcome of a 207 Multi-Status response, as sent by WebDAV servers and batch APIs.
+// Multi-status parsing is opt-in: pass a *MultiStatusResult as out to receive it. Any other out is decoded as usual,
+// so callers such as DoBatch which read the document themselves see the 207 body untouched.
+type MultiStatusResult struct {
+	Items []MultiStatusItem
+}
+
+// MultiStatusItem is the outcome reported for one sub-resource of a 207 response.
+type MultiStatusItem struct {
+	// Href identifies the sub-resource: the WebDAV href, or the id of a JSON batch item.
+	Href string
+	// StatusCode is the item's own HTTP status, or 0 if it reported none. An item without a status is neither
+	// succeeded nor failed.
+	StatusCode int
+	// Error is the item's error description, if any.
+	Error string
+	// Body is the item's JSON body, when it carried one.
+	Body json.RawMessage
+}
+
+// Succeeded reports whether the item has a 2xx status.
+func (i MultiStatusItem) Succeeded() bool {
+	return i.StatusCode >= http.StatusOK && i.StatusCode < http.StatusMultipleChoices
+}
+
+// Failed returns the items which reported a status outside 2xx.
+func (r *MultiStatusResult) Failed() []MultiStatusItem {
+	var failed []MultiStatusItem
+	for _, item := range r.Items {
+		if item.StatusCode != 0 && !item.Succeeded() {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// MultiStatusError is returned by DoRequest when out is a *MultiStatusResult and the 207 Multi-Status response
+// reports at least one failed item. out holds every item; use errors.As to inspect Result and act on the failures.
+type MultiStatusError struct {
+	Result *MultiStatusResult
+}
+
+// Error summarises how many items failed.
+func (e *MultiStatusError) Error() string {
+	failed := e.Result.Failed()
+	if len(failed) == 1 {
+		return fmt.Sprintf("multi-status response: 1 of %d items failed: %s %d %s", len(e.Result.Items), failed[0].Href, failed[0].StatusCode, failed[0].Error)
+	}
+	return fmt.Sprintf("multi-status response: %d of %d items failed", len(failed), len(e.Result.Items))
+}
+
+// handleMultiStatus parses a 207 response into out when it is a *MultiStatusResult. It reports false, leaving the
+// body readable for ordinary decoding, when out is anything else, the response is not a 207 or its body is not a
+// recognised multi-status document.
+func (c *Client) handleMultiStatus(resp *http.Response, out interface{}) (bool, error) {
+	target, ok := out.(*MultiStatusResult)
+	if !ok || resp.StatusCode != http.StatusMultiStatus {
+		return false, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return false, fmt.Errorf("failed to read multi-status response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	var result *MultiStatusResult
+	switch {
+	case strings.HasSuffix(mediaType, "xml"):
+		result = parseXMLMultiStatus(body)
+	case strings.HasSuffix(mediaType, "json"):
+		result = parseJSONMultiStatus(body)
+	}
+	if result == nil {
+		return false, nil
+	}
+
+	*target = *result
+
+	if failed := result.Failed(); len(failed) > 0 {
+		c.Logger().Warnw("Multi-status response reported failed items", zap.String("url", resp.Request.URL.String()), zap.Int("failed", len(failed)), zap.Int("items", len(result.Items)))
+		return true, &MultiStatusError{Result: result}
+	}
+	return true, nil
+}
+
+// xmlMultiStatus is the WebDAV multistatus document (RFC 4918 section 14.16). Elements are matched by local name so
+// any namespace prefix is accepted.
+type xmlMultiStatus struct {
+	XMLName   xml.Name `xml:"multistatus"`
+	Responses []struct {
+		Hrefs       []string `xml:"href"`
+		Status      string   `xml:"status"`
+		Description string   `xml:"responsedescription"`
+		Error       struct {
+			Inner string `xml:",innerxml"`
+		} `xml:"error"`
+		Propstats []struct {
+			Status      string `xml:"status"`
+			Description string `xml:"responsedescription"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// parseXMLMultiStatus parses a WebDAV multistatus body, returning nil if body is not one. An item without a
+// response-level status takes the first failing propstat status, or else its first propstat status.
+func parseXMLMultiStatus(body []byte) *MultiStatusResult {
+	var doc xmlMultiStatus
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil
+	}
+
+	result := &MultiStatusResult{}
+	for _, r := range doc.Responses {
+		item := MultiStatusItem{
+			StatusCode: parseStatusLine(r.Status),
+			Error:      strings.TrimSpace(r.Description),
+		}
+		if len(r.Hrefs) > 0 {
+			item.Href = strings.TrimSpace(r.Hrefs[0])
+		}
+		if item.Error == "" {
+			item.Error = strings.TrimSpace(r.Error.Inner)
+		}
+
+		if item.StatusCode == 0 {
+			for _, propstat := range r.Propstats {
+				code := parseStatusLine(propstat.Status)
+				if item.StatusCode == 0 || (code >= http.StatusMultipleChoices && item.Succeeded()) {
+					item.StatusCode = code
+					if item.Error == "" {
+						item.Error = strings.TrimSpace(propstat.Description)
+					}
+				}
+			}
+		}
+
+		result.Items = append(result.Items, item)
+	}
+	return result
+}
+
+// jsonMultiStatus is a JSON batch response listing per-item outcomes, e.g. the Microsoft Graph $batch format.
+type jsonMultiStatus struct {
+	Responses []struct {
+		ID     string          `json:"id"`
+		Href   string          `json:"href"`
+		Status json.RawMessage `json:"status"`
+		Error  json.RawMessage `json:"error"`
+		Body   json.RawMessage `json:"body"`
+	} `json:"responses"`
+}
+
+// parseJSONMultiStatus parses a JSON batch body, returning nil unless it has a "responses" array. Item statuses may
+// be numbers or status lines, and errors strings or objects with a "message".
+func parseJSONMultiStatus(body []byte) *MultiStatusResult {
+	var doc jsonMultiStatus
+	if err := json.Unmarshal(body, &doc); err != nil || doc.Responses == nil {
+		return nil
+	}
+
+	result := &MultiStatusResult{}
+	for _, r := range doc.Responses {
+		item := MultiStatusItem{Href: r.Href, Error: jsonErrorText(r.Error), Body: r.Body}
+		if item.Href == "" {
+			item.Href = r.ID
+		}
+
+		var code int
+		var line string
+		if json.Unmarshal(r.Status, &code) == nil {
+			item.StatusCode = code
+		} else if json.Unmarshal(r.Status, &line) == nil {
+			item.StatusCode = parseStatusLine(line)
+		}
+
+		result.Items = append(result.Items, item)
+	}
+	return result
+}
+
+// jsonErrorText extracts a readable message from a JSON error value.
+func jsonErrorText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+	var object struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(raw, &object) == nil && object.Message != "" {
+		return object.Message
+	}
+	return string(raw)
+}
+
+// parseStatusLine returns the code of a status line such as "HTTP/1.1 404 Not Found", or of a bare "404".
+// It returns 0 when none can be found.
+func parseStatusLine(line string) int {
+	fields := strings.Fields(line)
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "HTTP/") {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return 0
+	}
+
+	code, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0
+	}
+	return code
+}
@@ -0,0 +1,152 @@
+// httpclient/multistatus_test.go
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const webDAVMultiStatus = `<?xml version="1.0" encoding="utf-8"?>
+<d:multistatus xmlns:d="DAV:">
+  <d:response>
+    <d:href>/files/a.txt</d:href>
+    <d:status>HTTP/1.1 204 No Content</d:status>
+  </d:response>
+  <d:response>
+    <d:href>/files/b.txt</d:href>
+    <d:status>HTTP/1.1 423 Locked</d:status>
+    <d:responsedescription>file is locked</d:responsedescription>
+  </d:response>
+  <d:response>
+    <d:href>/files/c.txt</d:href>
+    <d:propstat><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+    <d:propstat><d:status>HTTP/1.1 403 Forbidden</d:status><d:responsedescription>read only</d:responsedescription></d:propstat>
+  </d:response>
+</d:multistatus>`
+
+const jsonMultiStatusBody = `{"responses":[
+  {"id":"1","status":201,"body":{"id":42}},
+  {"id":"2","status":"HTTP/1.1 409 Conflict","error":{"code":"conflict","message":"already exists"}}
+]}`
+
+func TestDoRequestMultiStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		want        []MultiStatusItem
+		wantFailed  int
+	}{
+		{
+			name:        "webdav xml",
+			status:      http.StatusMultiStatus,
+			contentType: "application/xml; charset=utf-8",
+			body:        webDAVMultiStatus,
+			want: []MultiStatusItem{
+				{Href: "/files/a.txt", StatusCode: http.StatusNoContent},
+				{Href: "/files/b.txt", StatusCode: http.StatusLocked, Error: "file is locked"},
+				{Href: "/files/c.txt", StatusCode: http.StatusForbidden, Error: "read only"},
+			},
+			wantFailed: 2,
+		},
+		{
+			name:        "json batch",
+			status:      http.StatusMultiStatus,
+			contentType: "application/json",
+			body:        jsonMultiStatusBody,
+			want: []MultiStatusItem{
+				{Href: "1", StatusCode: http.StatusCreated, Body: []byte(`{"id":42}`)},
+				{Href: "2", StatusCode: http.StatusConflict, Error: "already exists"},
+			},
+			wantFailed: 1,
+		},
+		{
+			name:        "all items succeeded",
+			status:      http.StatusMultiStatus,
+			contentType: "application/json",
+			body:        `{"responses":[{"id":"1","status":200}]}`,
+			want:        []MultiStatusItem{{Href: "1", StatusCode: http.StatusOK}},
+		},
+		{
+			name:        "items without a status are not failures",
+			status:      http.StatusMultiStatus,
+			contentType: "application/json",
+			body:        `{"responses":[{"id":"1","status":200},{"id":"2"}]}`,
+			want:        []MultiStatusItem{{Href: "1", StatusCode: http.StatusOK}, {Href: "2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := newTestClient(t, server.URL, nil)
+
+			var out MultiStatusResult
+			_, err := client.DoRequest(http.MethodPost, "/batch", nil, &out)
+
+			if !reflect.DeepEqual(out.Items, tt.want) {
+				t.Errorf("items = %+v, want %+v", out.Items, tt.want)
+			}
+
+			var multiErr *MultiStatusError
+			if tt.wantFailed == 0 {
+				if err != nil {
+					t.Fatalf("DoRequest() error = %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &multiErr) {
+				t.Fatalf("DoRequest() error = %v, want a MultiStatusError", err)
+			}
+			if got := len(multiErr.Result.Failed()); got != tt.wantFailed {
+				t.Errorf("failed items = %d, want %d", got, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestDoRequestMultiStatusUnrecognisedBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(`{"name":"widget"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, nil)
+
+	var out struct {
+		Name string `json:"name"`
+	}
+	if _, err := client.DoRequest(http.MethodPost, "/widgets", nil, &out); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	if out.Name != "widget" {
+		t.Errorf("out.Name = %q, want the body decoded as usual", out.Name)
+	}
+}
+
+func TestParseStatusLine(t *testing.T) {
+	tests := map[string]int{
+		"HTTP/1.1 404 Not Found": 404,
+		"HTTP/2 200":             200,
+		"207":                    207,
+		"":                       0,
+		"HTTP/1.1 OK":            0,
+	}
+	for line, want := range tests {
+		if got := parseStatusLine(line); got != want {
+			t.Errorf("parseStatusLine(%q) = %d, want %d", line, got, want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// httpclient/neterrors.go
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+
+	"github.com/deploymenttheory/go-api-http-client/concurrency"
+	"go.uber.org/zap"
+)
+
+// ErrResponseBodyRead is returned, wrapping the underlying network error, when the connection fails while a
+// successful response's body is being read, e.g. with io.ErrUnexpectedEOF when it drops mid-body. It is kept
+// distinct from decoding errors, and idempotent requests are retried on it like any other network error.
+var ErrResponseBodyRead = errors.New("failed to read response body")
+
+// bufferResponseBody reads resp's body into memory before it is decoded, so a connection dropped mid-body
+// surfaces as ErrResponseBodyRead rather than as an unmarshalling failure. ResponseTooLargeError is returned as is.
+func bufferResponseBody(resp *http.Response) error {
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		var tooLarge *ResponseTooLargeError
+		if errors.As(err, &tooLarge) {
+			return err
+		}
+		return fmt.Errorf("%w: %w", ErrResponseBodyRead, err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return nil
+}
+
+// isConnectionError reports whether err happened while establishing or using the underlying connection
+// (dial failures, refused or reset connections) rather than being an HTTP level failure.
+func isConnectionError(err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EHOSTUNREACH) || errors.Is(err, syscall.ENETUNREACH) {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// retryWithFreshConnection flushes the cached DNS entry for the request's host and any idle pooled connections,
+// then sends the request once more so it is dialled against a freshly resolved address.
+func (c *Client) retryWithFreshConnection(req *http.Request, cause error) (*http.Response, error) {
+	host := req.URL.Hostname()
+	c.Logger().Warnw("Connection error, re-resolving host and retrying on a fresh connection", zap.String("host", host), zap.Error(cause))
+
+	if c.dnsCache != nil {
+		c.dnsCache.flush(host)
+	}
+	c.http.CloseIdleConnections()
+
+	retryReq := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, cause
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, cause
+		}
+		retryReq.Body = body
+	}
+
+	return c.do(retryReq)
+}
+
+// isRetryableNetworkError reports whether a transport error is worth retrying. Errors raised before the request
+// could have reached the server (dial failures, refused connections, temporary DNS failures) are retryable for
+// any method. Errors after the request may have been sent (resets, unexpected EOF, timeouts) are only retryable
+// for idempotent methods, so a non-idempotent request is never replayed against a server which may have acted on it.
+func isRetryableNetworkError(err error, method string) bool {
+	if errors.Is(err, concurrency.ErrPermitAcquireTimeout) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+
+	if !isIdempotentHTTPMethod(method) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,135 @@
+// httpclient/neterrors_test.go
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-http-client/concurrency"
+)
+
+func TestIsRetryableNetworkError(t *testing.T) {
+	reset := &net.OpError{Op: "read", Err: syscall.ECONNRESET}
+	tests := []struct {
+		name   string
+		err    error
+		method string
+		want   bool
+	}{
+		{"dial failure post", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, http.MethodPost, true},
+		{"temporary dns get", &net.DNSError{Err: "server misbehaving", IsTemporary: true}, http.MethodGet, true},
+		{"unknown host get", &net.DNSError{Err: "no such host", IsNotFound: true}, http.MethodGet, false},
+		{"reset get", reset, http.MethodGet, true},
+		{"reset post", reset, http.MethodPost, false},
+		{"eof put", fmt.Errorf("send: %w", io.EOF), http.MethodPut, true},
+		{"permit timeout", fmt.Errorf("%w: %w", concurrency.ErrPermitAcquireTimeout, context.DeadlineExceeded), http.MethodGet, false},
+		{"other", errors.New("marshal failed"), http.MethodGet, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableNetworkError(tt.err, tt.method); got != tt.want {
+				t.Errorf("isRetryableNetworkError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestWithRetries_NetworkError(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			// Drop the connection without a response, as a crashing backend would.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.RetryEligiableRequests = true
+		config.MaxRetryAttempts = 2
+		config.TotalRetryDuration = 10 * time.Second
+	})
+
+	var out map[string]interface{}
+	resp, err := client.DoRequest(http.MethodGet, "/items", nil, &out)
+	if err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := atomic.LoadInt32(&attempts); got != 2 || out["ok"] != true {
+		t.Errorf("attempts = %d, out = %v; want success on attempt 2", got, out)
+	}
+}
+
+func TestDoRequest_TruncatedResponseBody(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{name: "idempotent request is retried", method: http.MethodGet, wantAttempts: 2},
+		{name: "non-idempotent request reports the read failure", method: http.MethodPost, wantAttempts: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					// Promise a longer body than is sent, then drop the connection mid-body.
+					conn, buf, err := w.(http.Hijacker).Hijack()
+					if err != nil {
+						return
+					}
+					buf.WriteString("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: 100\r\n\r\n{\"ok\":")
+					buf.Flush()
+					conn.Close()
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"ok":true}`))
+			}))
+			defer server.Close()
+
+			client := newTestClient(t, server.URL, func(config *ClientConfig) {
+				config.RetryEligiableRequests = true
+				config.MaxRetryAttempts = 2
+				config.TotalRetryDuration = 10 * time.Second
+			})
+
+			var out map[string]interface{}
+			_, err := client.DoRequest(tt.method, "/items", nil, &out)
+
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			if !tt.wantErr {
+				if err != nil || out["ok"] != true {
+					t.Fatalf("DoRequest() = %v, %v; want the body of the retried request", out, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrResponseBodyRead) || !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Fatalf("DoRequest() error = %v, want ErrResponseBodyRead wrapping io.ErrUnexpectedEOF", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// httpclient/oauth2.go
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// OAuth2AuthStyle selects how the client id and secret are sent to the token endpoint.
+type OAuth2AuthStyle string
+
+const (
+	// OAuth2AuthStyleAutoDetect tries HTTP Basic auth first, then form parameters (the default).
+	OAuth2AuthStyleAutoDetect OAuth2AuthStyle = "auto"
+
+	// OAuth2AuthStyleInHeader sends the credentials with HTTP Basic auth.
+	OAuth2AuthStyleInHeader OAuth2AuthStyle = "header"
+
+	// OAuth2AuthStyleInParams sends the credentials as client_id and client_secret form parameters.
+	OAuth2AuthStyleInParams OAuth2AuthStyle = "params"
+)
+
+// validate checks the style is a known value. The zero value selects OAuth2AuthStyleAutoDetect.
+func (s OAuth2AuthStyle) validate() error {
+	switch s {
+	case "", OAuth2AuthStyleAutoDetect, OAuth2AuthStyleInHeader, OAuth2AuthStyleInParams:
+		return nil
+	default:
+		return fmt.Errorf("invalid oauth2 auth style: %s, expected %s, %s or %s", s, OAuth2AuthStyleAutoDetect, OAuth2AuthStyleInHeader, OAuth2AuthStyleInParams)
+	}
+}
+
+// oauth2Style maps the style onto golang.org/x/oauth2's.
+func (s OAuth2AuthStyle) oauth2Style() oauth2.AuthStyle {
+	switch s {
+	case OAuth2AuthStyleInHeader:
+		return oauth2.AuthStyleInHeader
+	case OAuth2AuthStyleInParams:
+		return oauth2.AuthStyleInParams
+	default:
+		return oauth2.AuthStyleAutoDetect
+	}
+}
+
+// OAuth2ClientCredentials configures token acquisition with the OAuth2 client credentials grant, handled by
+// golang.org/x/oauth2/clientcredentials.
+type OAuth2ClientCredentials struct {
+	TokenURL       string          `json:"token_url"`
+	ClientID       string          `json:"client_id"`
+	ClientSecret   string          `json:"client_secret"`
+	Scopes         []string        `json:"scopes"`
+	AuthStyle      OAuth2AuthStyle `json:"auth_style"`
+	EndpointParams url.Values      `json:"endpoint_params"`
+}
+
+// validate checks the settings required to request a token are present.
+func (o *OAuth2ClientCredentials) validate() error {
+	if o.TokenURL == "" {
+		return errors.New("oauth2 client credentials require a token url")
+	}
+	if _, err := url.ParseRequestURI(o.TokenURL); err != nil {
+		return fmt.Errorf("invalid oauth2 token url: %v", err)
+	}
+	if o.ClientID == "" {
+		return errors.New("oauth2 client credentials require a client id")
+	}
+	return o.AuthStyle.validate()
+}
+
+// ClientCredentialsTokenSource is a TokenSource backed by golang.org/x/oauth2/clientcredentials. Tokens are cached
+// and renewed refreshBuffer before they expire. It implements TokenExpiryReporter, so the background refresher
+// renews the token just in time.
+type ClientCredentialsTokenSource struct {
+	source oauth2.TokenSource
+	token  *oauth2.Token
+	sync.Mutex
+}
+
+// NewClientCredentialsTokenSource returns a TokenSource for config, renewing tokens refreshBuffer before expiry.
+// Token requests are sent with httpClient, or http.DefaultClient when nil.
+func NewClientCredentialsTokenSource(config OAuth2ClientCredentials, refreshBuffer time.Duration, httpClient *http.Client) (*ClientCredentialsTokenSource, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	ccConfig := &clientcredentials.Config{
+		ClientID:       config.ClientID,
+		ClientSecret:   config.ClientSecret,
+		TokenURL:       config.TokenURL,
+		Scopes:         config.Scopes,
+		EndpointParams: config.EndpointParams,
+		AuthStyle:      config.AuthStyle.oauth2Style(),
+	}
+
+	ctx := context.Background()
+	if httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	}
+
+	return &ClientCredentialsTokenSource{
+		source: oauth2.ReuseTokenSourceWithExpiry(nil, ccConfig.TokenSource(ctx), refreshBuffer),
+	}, nil
+}
+
+// Token returns a valid token, requesting a new one when the cached token is within the refresh buffer of expiry.
+func (s *ClientCredentialsTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain oauth2 token: %w", err)
+	}
+
+	s.Lock()
+	s.token = token
+	s.Unlock()
+
+	return token, nil
+}
+
+// CheckRefreshToken makes sure a valid token is cached, renewing it if needed.
+func (s *ClientCredentialsTokenSource) CheckRefreshToken() error {
+	_, err := s.Token()
+	return err
+}
+
+// PrepRequestParamsAndAuth sets the Authorization header from the current token.
+func (s *ClientCredentialsTokenSource) PrepRequestParamsAndAuth(req *http.Request) error {
+	token, err := s.Token()
+	if err != nil {
+		return err
+	}
+
+	token.SetAuthHeader(req)
+	return nil
+}
+
+// TokenExpiry returns when the most recently obtained token expires, or the zero time before the first token.
+func (s *ClientCredentialsTokenSource) TokenExpiry() time.Time {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.token == nil {
+		return time.Time{}
+	}
+	return s.token.Expiry
+}
+
+// oauth2IntegrationSource authenticates with the integration's params and an OAuth2 client credentials token,
+// which replaces any Authorization the integration set.
+type oauth2IntegrationSource struct {
+	integration APIIntegration
+	oauth2      *ClientCredentialsTokenSource
+}
+
+// CheckRefreshToken renews the OAuth2 token; the integration's own token handling is bypassed.
+func (s *oauth2IntegrationSource) CheckRefreshToken() error {
+	return s.oauth2.CheckRefreshToken()
+}
+
+// PrepRequestParamsAndAuth applies the integration's params, then the OAuth2 Authorization header.
+func (s *oauth2IntegrationSource) PrepRequestParamsAndAuth(req *http.Request) error {
+	if err := s.integration.PrepRequestParamsAndAuth(req); err != nil {
+		return err
+	}
+	return s.oauth2.PrepRequestParamsAndAuth(req)
+}
+
+// TokenExpiry reports the OAuth2 token's expiry.
+func (s *oauth2IntegrationSource) TokenExpiry() time.Time {
+	return s.oauth2.TokenExpiry()
+}
@@ -0,0 +1,96 @@
+// httpclient/oauth2_test.go
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	var tokenRequests atomic.Int32
+	var gotGrant, gotScope string
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := tokenRequests.Add(1)
+		r.ParseForm()
+		gotGrant, gotScope = r.PostForm.Get("grant_type"), r.PostForm.Get("scope")
+		if id, secret, ok := r.BasicAuth(); !ok || id != "client" || secret != "secret" {
+			t.Errorf("token request credentials = %q/%q, want client/secret in the header", id, secret)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, n)
+	}))
+	defer tokenServer.Close()
+
+	var gotAuth []string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = append(gotAuth, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer apiServer.Close()
+
+	client := newTestClient(t, apiServer.URL, func(c *ClientConfig) {
+		c.TokenRefreshBufferPeriod = time.Minute
+		c.OAuth2 = &OAuth2ClientCredentials{
+			TokenURL:     tokenServer.URL,
+			ClientID:     "client",
+			ClientSecret: "secret",
+			Scopes:       []string{"api.read", "api.write"},
+			AuthStyle:    OAuth2AuthStyleInHeader,
+		}
+	})
+
+	var out map[string]interface{}
+	for i := 0; i < 2; i++ {
+		if _, err := client.DoRequest(http.MethodGet, "/resource", nil, &out); err != nil {
+			t.Fatalf("DoRequest() error = %v", err)
+		}
+	}
+
+	if tokenRequests.Load() != 1 {
+		t.Errorf("token requests = %d, want 1 as the token is reused until expiry", tokenRequests.Load())
+	}
+	if gotGrant != "client_credentials" || gotScope != "api.read api.write" {
+		t.Errorf("grant_type = %q, scope = %q", gotGrant, gotScope)
+	}
+	for _, auth := range gotAuth {
+		if auth != "Bearer token-1" {
+			t.Errorf("Authorization = %q, want the OAuth2 token to replace the integration's", auth)
+		}
+	}
+
+	expiry := client.oauth2Auth.TokenExpiry()
+	if until := time.Until(expiry); until < 59*time.Minute || until > time.Hour {
+		t.Errorf("TokenExpiry() in %v, want about an hour", until)
+	}
+}
+
+func TestOAuth2ClientCredentials_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  OAuth2ClientCredentials
+		wantErr string
+	}{
+		{"missing token url", OAuth2ClientCredentials{ClientID: "client"}, "token url"},
+		{"missing client id", OAuth2ClientCredentials{TokenURL: "https://login.example.com/token"}, "client id"},
+		{"unknown auth style", OAuth2ClientCredentials{TokenURL: "https://login.example.com/token", ClientID: "client", AuthStyle: "cookie"}, "auth style"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ClientConfig{Integration: &MockIntegration{}, Sugar: zap.NewNop().Sugar(), OAuth2: &tt.config}
+			_, err := config.Build()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Build() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// httpclient/options.go
+package httpclient
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"github.com/deploymenttheory/go-api-http-client/response"
+)
+
+// RequestOption customises a single request without changing the client wide configuration.
+// Options are applied in order on top of the defaults taken from ClientConfig.
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the per-request settings resolved from the client config and any RequestOptions.
+type requestOptions struct {
+	envelopeDecoder     response.EnvelopeDecoder
+	onRecord            func(json.RawMessage) error
+	baseURL             *url.URL
+	userAgentSuffix     string
+	rawBody             io.Reader
+	rawContentType      string
+	formBody            url.Values
+	weight              int
+	progress            ProgressFunc
+	multipartRetry      bool
+	headers             map[string]string
+	expectedContentType string
+	bodyContentType     string
+	requestID           string
+}
+
+// newRequestOptions seeds the per-request settings from the client config and applies the supplied options.
+func (c *Client) newRequestOptions(opts []RequestOption) *requestOptions {
+	ro := &requestOptions{
+		envelopeDecoder: c.config.EnvelopeDecoder,
+		weight:          1,
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(ro)
+		}
+	}
+
+	return ro
+}
+
+// WithEnvelopeDecoder flattens the response body with the given decoder (e.g. response.HALDecoder or
+// response.JSONAPIDecoder) before it is unmarshalled into out. Pass nil to disable a client wide decoder.
+func WithEnvelopeDecoder(decoder response.EnvelopeDecoder) RequestOption {
+	return func(ro *requestOptions) {
+		ro.envelopeDecoder = decoder
+	}
+}
+
+// WithUserAgentSuffix appends suffix (e.g. "sync-job/1.4") to the User-Agent of this request, after the configured
+// UserAgent and before the library's own product token.
+func WithUserAgentSuffix(suffix string) RequestOption {
+	return func(ro *requestOptions) {
+		ro.userAgentSuffix = suffix
+	}
+}
+
+// WithRequestWeight makes the request hold weight concurrency tokens instead of one while it is in flight, so heavy
+// requests count for more of MaxConcurrentRequests. Only applies when EnableConcurrencyManagement is set.
+// Multipart uploads default to DefaultMultipartRequestWeight.
+func WithRequestWeight(weight int) RequestOption {
+	return func(ro *requestOptions) {
+		ro.weight = weight
+	}
+}
+
+// WithProgress reports multipart upload progress to fn after every chunk written, replacing the 5% progress log lines.
+func WithProgress(fn ProgressFunc) RequestOption {
+	return func(ro *requestOptions) {
+		ro.progress = fn
+	}
+}
+
+// WithMultipartRetry makes DoMultiPartRequest retry transient failures (5xx, 429 and retryable network errors) up to
+// MaxRetryAttempts times with backoff, rebuilding the whole multipart body for every attempt. Only use it when the
+// endpoint tolerates a repeated upload.
+func WithMultipartRetry() RequestOption {
+	return func(ro *requestOptions) {
+		ro.multipartRetry = true
+	}
+}
+
+// WithHeaders adds headers, such as a trace id or tenant selector, to this request. They are applied after the
+// standard headers, so they override the integration's, User-Agent and Content-Type, with the exception of
+// Authorization, which cannot be overridden. Repeated options merge, later values winning.
+func WithHeaders(headers map[string]string) RequestOption {
+	return func(ro *requestOptions) {
+		if ro.headers == nil {
+			ro.headers = make(map[string]string, len(headers))
+		}
+		for name, value := range headers {
+			ro.headers[name] = value
+		}
+	}
+}
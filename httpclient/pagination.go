@@ -0,0 +1,138 @@
+// httpclient/pagination.go
+package httpclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// DefaultMaxPages is the page cap used by DoRequestAllPages when ClientConfig.MaxPages is not set.
+const DefaultMaxPages = 100
+
+// ErrMaxPagesExceeded is returned by DoRequestAllPages when more pages remain after MaxPages have been read.
+// The items read so far are kept in out.
+var ErrMaxPagesExceeded = errors.New("maximum page count exceeded")
+
+// odataPage is the Microsoft Graph (OData) collection page shape.
+type odataPage struct {
+	Value    json.RawMessage `json:"value"`
+	NextLink string          `json:"@odata.nextLink"`
+}
+
+// DoRequestAllPages sends the request and follows next-page pointers until none remain, appending every page's
+// items to out, which must be a pointer to a slice. The next page is taken from "@odata.nextLink" in an OData
+// body ({"value": [...]}) or from an RFC 5988 Link header with rel="next" when the body is a plain JSON array.
+// Next links are resolved against the page that named them and, as they are requested with the client's
+// credentials, must stay on its scheme and host unless AllowedHosts permits theirs; otherwise ErrHostNotAllowed is
+// returned. At most MaxPages pages are read; if more remain ErrMaxPagesExceeded is returned with the items read so
+// far.
+func (c *Client) DoRequestAllPages(method, endpoint string, out interface{}) error {
+	target := reflect.ValueOf(out)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("output parameter must be a pointer to a slice, got %T", out)
+	}
+	items := target.Elem()
+
+	maxPages := c.config.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+
+	next := endpoint
+	for page := 1; next != ""; page++ {
+		if page > maxPages {
+			return fmt.Errorf("%w: read %d pages of %s", ErrMaxPagesExceeded, maxPages, endpoint)
+		}
+
+		var body json.RawMessage
+		resp, err := c.DoRequest(method, next, nil, &body)
+		if err != nil {
+			return fmt.Errorf("failed to fetch page %d: %w", page, err)
+		}
+		resp.Body.Close()
+
+		pageItems, nextLink, err := parsePage(body, resp.Header)
+		if err != nil {
+			return fmt.Errorf("failed to parse page %d: %w", page, err)
+		}
+
+		pageSlice := reflect.New(items.Type())
+		if err := json.Unmarshal(pageItems, pageSlice.Interface()); err != nil {
+			return fmt.Errorf("failed to unmarshal page %d items: %w", page, err)
+		}
+		items.Set(reflect.AppendSlice(items, pageSlice.Elem()))
+
+		c.Logger().Debugw("Fetched page", zap.String("endpoint", endpoint), zap.Int("page", page), zap.Int("items", pageSlice.Elem().Len()))
+
+		next = ""
+		if nextLink != "" {
+			if next = resolveLocation(resp, nextLink); next == "" {
+				return fmt.Errorf("invalid next page link %q on page %d", nextLink, page)
+			}
+			if resp.Request != nil && resp.Request.URL != nil {
+				if err := c.checkFollowedURL(resp.Request.URL, next); err != nil {
+					return fmt.Errorf("failed to follow page %d: %w", page+1, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// parsePage extracts a page's items and the next page pointer from either an OData body or a plain array
+// paired with a Link header.
+func parsePage(body json.RawMessage, header http.Header) (json.RawMessage, string, error) {
+	trimmed := strings.TrimSpace(string(body))
+	if strings.HasPrefix(trimmed, "[") {
+		return body, nextLinkFromHeader(header), nil
+	}
+
+	var page odataPage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, "", err
+	}
+	if page.Value == nil {
+		return nil, "", errors.New("response is neither a JSON array nor a collection with a \"value\" member")
+	}
+
+	next := page.NextLink
+	if next == "" {
+		next = nextLinkFromHeader(header)
+	}
+
+	return page.Value, next, nil
+}
+
+// nextLinkFromHeader returns the target of the rel="next" entry of an RFC 5988 Link header, if any.
+func nextLinkFromHeader(header http.Header) string {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range parts[1:] {
+				key, val, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(key, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(val, `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return strings.Trim(target, "<>")
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,145 @@
+// httpclient/pagination_test.go
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type pagedUser struct {
+	ID int `json:"id"`
+}
+
+func TestDoRequestAllPages(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path + "?" + r.URL.RawQuery {
+		case "/odata?":
+			fmt.Fprintf(w, `{"value":[{"id":1},{"id":2}],"@odata.nextLink":"%s/odata?page=2"}`, server.URL)
+		case "/odata?page=2":
+			fmt.Fprint(w, `{"value":[{"id":3}]}`)
+		case "/linked?":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/linked?page=2>; rel="next", <%s/linked?page=2>; rel="last"`, server.URL, server.URL))
+			fmt.Fprint(w, `[{"id":1}]`)
+		case "/linked?page=2":
+			fmt.Fprint(w, `[{"id":2},{"id":3}]`)
+		case "/endless?":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/endless>; rel="next"`, server.URL))
+			fmt.Fprint(w, `[{"id":1}]`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.MaxPages = 3
+	})
+
+	want := []pagedUser{{1}, {2}, {3}}
+	for _, endpoint := range []string{"/odata", "/linked"} {
+		t.Run(endpoint, func(t *testing.T) {
+			var users []pagedUser
+			if err := client.DoRequestAllPages(http.MethodGet, endpoint, &users); err != nil {
+				t.Fatalf("DoRequestAllPages() error = %v", err)
+			}
+			if !reflect.DeepEqual(users, want) {
+				t.Errorf("users = %v, want %v", users, want)
+			}
+		})
+	}
+
+	t.Run("page cap", func(t *testing.T) {
+		var users []pagedUser
+		err := client.DoRequestAllPages(http.MethodGet, "/endless", &users)
+		if !errors.Is(err, ErrMaxPagesExceeded) {
+			t.Fatalf("error = %v, want ErrMaxPagesExceeded", err)
+		}
+		if len(users) != 3 {
+			t.Errorf("users = %d, want the 3 pages read", len(users))
+		}
+	})
+}
+
+func TestDoRequestAllPagesNextLinks(t *testing.T) {
+	t.Run("relative link with BasePath", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path + "?" + r.URL.RawQuery {
+			case "/api/v1/items?":
+				w.Header().Set("Link", `</api/v1/items?page=2>; rel="next"`)
+				fmt.Fprint(w, `[{"id":1}]`)
+			case "/api/v1/items?page=2":
+				fmt.Fprint(w, `[{"id":2}]`)
+			default:
+				t.Errorf("unexpected request %s", r.URL)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		client := newTestClient(t, server.URL, func(config *ClientConfig) {
+			config.BasePath = "/api/v1"
+		})
+
+		var users []pagedUser
+		if err := client.DoRequestAllPages(http.MethodGet, "/items", &users); err != nil {
+			t.Fatalf("DoRequestAllPages() error = %v", err)
+		}
+		if want := []pagedUser{{1}, {2}}; !reflect.DeepEqual(users, want) {
+			t.Errorf("users = %v, want %v", users, want)
+		}
+	})
+
+	t.Run("foreign host is refused", func(t *testing.T) {
+		foreign := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("foreign host received %s with Authorization %q", r.URL, r.Header.Get("Authorization"))
+		}))
+		defer foreign.Close()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"value":[{"id":1}],"@odata.nextLink":"%s/items?page=2"}`, foreign.URL)
+		}))
+		defer server.Close()
+
+		client := newTestClient(t, server.URL, nil)
+
+		var users []pagedUser
+		err := client.DoRequestAllPages(http.MethodGet, "/items", &users)
+		if !errors.Is(err, ErrHostNotAllowed) {
+			t.Fatalf("error = %v, want ErrHostNotAllowed", err)
+		}
+		if len(users) != 1 {
+			t.Errorf("users = %d, want the first page's item", len(users))
+		}
+	})
+}
+
+func TestNextLinkFromHeader(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{`<https://api.test/items?page=2>; rel="next"`, "https://api.test/items?page=2"},
+		{`<https://api.test/items?page=1>; rel="prev", <https://api.test/items?page=3>; rel=next`, "https://api.test/items?page=3"},
+		{`<https://api.test/items?page=9>; rel="last"`, ""},
+		{``, ""},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.link != "" {
+			header.Set("Link", tt.link)
+		}
+		if got := nextLinkFromHeader(header); got != tt.want {
+			t.Errorf("nextLinkFromHeader(%q) = %q, want %q", tt.link, got, tt.want)
+		}
+	}
+}
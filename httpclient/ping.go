@@ -0,0 +1,57 @@
+// httpclient/ping.go
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// DoPole repeatedly probes an endpoint until it answers 200 OK, e.g. to wait for a resource to become available
+// after it has been created. Each probe is a single attempt without the client's own retry logic, and probes are
+// spaced with exponential backoff. Polling gives up after MaxRetryAttempts further probes or when ctx is done.
+// Parameters:
+//   - ctx: Controls cancellation of the whole poll, including any backoff between probes.
+//   - method, endpoint, body, out: As for DoRequest. out is only populated from the successful probe.
+//
+// Returns:
+//   - *http.Response: The 200 OK response. The caller is responsible for closing its body.
+//   - error: The context error if ctx ends first, otherwise an error wrapping the last probe's failure.
+func (c *Client) DoPole(ctx context.Context, method, endpoint string, body, out interface{}) (*http.Response, error) {
+	method, err := normalizeHTTPMethod(method)
+	if err != nil {
+		return nil, err
+	}
+
+	ro := c.newRequestOptions(nil)
+
+	var lastErr error
+	for attempt := 0; attempt <= c.config.MaxRetryAttempts; attempt++ {
+		if attempt > 0 {
+			if err := c.backoff.Wait(ctx, c.retryBackoff(attempt)); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := c.requestNoRetries(ctx, method, endpoint, body, out, ro)
+		if err == nil && resp.StatusCode == http.StatusOK {
+			c.Logger().Infow("Polling succeeded", zap.String("method", method), zap.String("endpoint", endpoint), zap.Int("attempts", attempt+1))
+			return resp, nil
+		}
+
+		if err == nil {
+			resp.Body.Close()
+			err = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		lastErr = err
+		c.Logger().Debugw("Polling attempt did not succeed", zap.String("endpoint", endpoint), zap.Int("attempt", attempt+1), zap.Error(err))
+	}
+
+	return nil, fmt.Errorf("endpoint %s did not return 200 after %d attempts: %w", endpoint, c.config.MaxRetryAttempts+1, lastErr)
+}
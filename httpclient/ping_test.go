@@ -0,0 +1,56 @@
+// httpclient/ping_test.go
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoPole(t *testing.T) {
+	var probes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&probes, 1) < 3 {
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"status":"pending"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"ready"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.MaxRetryAttempts = 3
+	})
+
+	t.Run("succeeds once ready", func(t *testing.T) {
+		var out struct {
+			Status string `json:"status"`
+		}
+		resp, err := client.DoPole(context.Background(), http.MethodGet, "/jobs/1", nil, &out)
+		if err != nil {
+			t.Fatalf("DoPole() error = %v", err)
+		}
+		defer resp.Body.Close()
+
+		if out.Status != "ready" || atomic.LoadInt32(&probes) != 3 {
+			t.Errorf("status = %q after %d probes, want ready after 3", out.Status, probes)
+		}
+	})
+
+	t.Run("respects context", func(t *testing.T) {
+		atomic.StoreInt32(&probes, 0)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.DoPole(ctx, http.MethodGet, "/jobs/1", nil, nil)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("DoPole() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+}
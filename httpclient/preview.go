@@ -0,0 +1,15 @@
+// httpclient/preview.go
+package httpclient
+
+import (
+	"context"
+	"net/http"
+)
+
+// PreviewRequest builds the request DoRequest would send for method, endpoint and body, without sending it. The
+// body is marshalled by the Integration, the URL constructed and the auth and other headers set exactly as for a
+// real request, so the result can be inspected or asserted on when troubleshooting URL construction, auth or
+// serialisation. Preparing auth may fetch or refresh a token. No concurrency permit is taken and no hooks run.
+func (c *Client) PreviewRequest(method, endpoint string, body interface{}, opts ...RequestOption) (*http.Request, error) {
+	return c.buildRequest(context.Background(), method, endpoint, body, c.newRequestOptions(opts))
+}
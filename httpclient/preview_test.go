@@ -0,0 +1,50 @@
+// httpclient/preview_test.go
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestClient_PreviewRequest(t *testing.T) {
+	var hookCalled bool
+	client := newTestClient(t, "https://api.example.com", func(config *ClientConfig) {
+		config.BasePath = "/api/v1"
+		config.OnRequest = func(*http.Request) { hookCalled = true }
+	})
+
+	req, err := client.PreviewRequest(http.MethodPost, "/users", map[string]string{"name": "preview"},
+		WithHeaders(map[string]string{"X-Trace": "abc"}))
+	if err != nil {
+		t.Fatalf("PreviewRequest() error = %v", err)
+	}
+
+	if req.Method != http.MethodPost {
+		t.Errorf("Method = %q, want POST", req.Method)
+	}
+	if got := req.URL.String(); got != "https://api.example.com/api/v1/users" {
+		t.Errorf("URL = %q, want https://api.example.com/api/v1/users", got)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer test-token" {
+		t.Errorf("Authorization = %q, want the integration's token", got)
+	}
+	if got := req.Header.Get("X-Trace"); got != "abc" {
+		t.Errorf("X-Trace = %q, want abc", got)
+	}
+	if req.Header.Get("User-Agent") == "" {
+		t.Error("User-Agent not set")
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+	if string(body) != `{"name":"preview"}` {
+		t.Errorf("body = %q, want the marshalled payload", body)
+	}
+
+	if hookCalled {
+		t.Error("OnRequest hook ran for a preview")
+	}
+}
@@ -0,0 +1,51 @@
+// httpclient/protocol.go
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+)
+
+// validateProtocolFlags rejects ForceHTTP1 combined with EnableH2C, which ask for contradictory protocols.
+func (c *ClientConfig) validateProtocolFlags() error {
+	if c.ForceHTTP1 && c.EnableH2C {
+		return errors.New("ForceHTTP1 and EnableH2C cannot both be set")
+	}
+	return nil
+}
+
+// applyProtocolPolicy configures which HTTP versions transport negotiates and logs the effective policy. By default
+// HTTP/2 is negotiated over TLS through ALPN and cleartext requests use HTTP/1.1. ForceHTTP1 restricts TLS
+// connections to HTTP/1.1; EnableH2C sends cleartext ("http://") requests as HTTP/2 with prior knowledge.
+func (c *ClientConfig) applyProtocolPolicy(transport *http.Transport) {
+	switch {
+	case c.ForceHTTP1:
+		transport.ForceAttemptHTTP2 = false
+		// A non-nil, empty TLSNextProto stops the transport from upgrading TLS connections to HTTP/2.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		if transport.TLSClientConfig != nil {
+			transport.TLSClientConfig.NextProtos = []string{"http/1.1"}
+		}
+		c.Sugar.Infow("HTTP protocol policy", zap.String("tls", "HTTP/1.1"), zap.String("cleartext", "HTTP/1.1"))
+
+	case c.EnableH2C:
+		dial := transport.DialContext
+		transport.RegisterProtocol("http", &http2.Transport{
+			AllowHTTP: true,
+			// h2c runs HTTP/2 over a plain TCP connection, so "TLS" dialling is just the transport's own dialler.
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return dial(ctx, network, addr)
+			},
+		})
+		c.Sugar.Infow("HTTP protocol policy", zap.String("tls", "HTTP/2 when negotiated, else HTTP/1.1"), zap.String("cleartext", "HTTP/2 (h2c)"))
+
+	default:
+		c.Sugar.Infow("HTTP protocol policy", zap.String("tls", "HTTP/2 when negotiated, else HTTP/1.1"), zap.String("cleartext", "HTTP/1.1"))
+	}
+}
@@ -0,0 +1,71 @@
+// httpclient/protocol_test.go
+package httpclient
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// protoHandler replies with the protocol the request arrived over.
+var protoHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Proto", r.Proto)
+})
+
+func TestBuildTransport_ProtocolPolicy(t *testing.T) {
+	tlsServer := httptest.NewUnstartedServer(protoHandler)
+	tlsServer.EnableHTTP2 = true
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+	serverCAPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw})
+
+	h2cServer := httptest.NewServer(h2c.NewHandler(protoHandler, &http2.Server{}))
+	defer h2cServer.Close()
+
+	tests := []struct {
+		name      string
+		configure func(*ClientConfig)
+		url       string
+		wantProto string
+	}{
+		{name: "TLS negotiates HTTP/2 by default", url: tlsServer.URL, wantProto: "HTTP/2.0"},
+		{name: "ForceHTTP1 over TLS", configure: func(c *ClientConfig) { c.ForceHTTP1 = true }, url: tlsServer.URL, wantProto: "HTTP/1.1"},
+		{name: "cleartext uses HTTP/1.1 by default", url: h2cServer.URL, wantProto: "HTTP/1.1"},
+		{name: "EnableH2C over cleartext", configure: func(c *ClientConfig) { c.EnableH2C = true }, url: h2cServer.URL, wantProto: "HTTP/2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ClientConfig{Sugar: zap.NewNop().Sugar(), TLS: &TLSConfig{CAPEM: serverCAPEM}}
+			if tt.configure != nil {
+				tt.configure(config)
+			}
+			transport, err := config.buildTransport(nil, nil)
+			if err != nil {
+				t.Fatalf("buildTransport() error = %v", err)
+			}
+			defer transport.CloseIdleConnections()
+
+			resp, err := (&http.Client{Transport: transport}).Get(tt.url)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if got := resp.Header.Get("X-Proto"); got != tt.wantProto {
+				t.Errorf("server saw %s, want %s", got, tt.wantProto)
+			}
+		})
+	}
+}
+
+func TestClientConfig_validateProtocolFlags(t *testing.T) {
+	config := &ClientConfig{ForceHTTP1: true, EnableH2C: true}
+	if err := config.validateProtocolFlags(); err == nil {
+		t.Error("validateProtocolFlags() = nil, want an error for ForceHTTP1 with EnableH2C")
+	}
+}
@@ -0,0 +1,32 @@
+// httpclient/raw.go
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// DoRequestRaw sends body verbatim with the given Content-Type, bypassing the Integration's PrepRequestBody.
+// Use it to proxy already serialised payloads or to stream large uploads without buffering them into memory.
+// The response is handled exactly as for DoRequest, within RequestDeadline. As a reader can only be consumed once the request is never
+// retried; MaxRequestBodyBytes and CompressRequestBody do not apply to raw bodies.
+func (c *Client) DoRequestRaw(method, endpoint string, body io.Reader, contentType string, out interface{}, opts ...RequestOption) (*http.Response, error) {
+	method, err := normalizeHTTPMethod(method)
+	if err != nil {
+		return nil, err
+	}
+
+	ro := c.newRequestOptions(opts)
+	ro.rawBody = body
+	ro.rawContentType = contentType
+
+	if ro.rawBody == nil {
+		ro.rawBody = http.NoBody
+	}
+
+	parent := context.Background()
+	ctx, cancel := c.requestDeadlineContext(parent)
+	resp, err := c.requestNoRetries(ctx, method, endpoint, nil, out, ro)
+	return c.finishRequestDeadline(parent, ctx, cancel, resp, err)
+}
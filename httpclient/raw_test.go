@@ -0,0 +1,45 @@
+// httpclient/raw_test.go
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDoRequestRaw(t *testing.T) {
+	var gotBody, gotContentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		gotContentType = r.Header.Get("Content-Type")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":7}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, nil)
+
+	payload := `<computer><name>raw</name></computer>`
+	var out struct {
+		ID int `json:"id"`
+	}
+	resp, err := client.DoRequestRaw(http.MethodPost, "/computers", strings.NewReader(payload), "application/xml", &out)
+	if err != nil {
+		t.Fatalf("DoRequestRaw() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if gotBody != payload {
+		t.Errorf("body = %q, want %q sent verbatim", gotBody, payload)
+	}
+	if gotContentType != "application/xml" {
+		t.Errorf("Content-Type = %q, want application/xml", gotContentType)
+	}
+	if out.ID != 7 {
+		t.Errorf("out.ID = %d, want 7", out.ID)
+	}
+}
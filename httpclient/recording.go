@@ -0,0 +1,321 @@
+// httpclient/recording.go
+package httpclient
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrNoRecordedInteraction is returned, wrapped with the request's method and URL, when a replaying
+// RecordingExecutor has no unused recorded interaction matching a request.
+var ErrNoRecordedInteraction = errors.New("no recorded interaction matches request")
+
+// redactedHeaderValue replaces the value of credential headers in recorded cassettes.
+const redactedHeaderValue = "REDACTED"
+
+// redactedHeaders are never written to a cassette in clear text.
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// RecordingExecutor is an HTTPExecutor which records request/response pairs to a cassette file the first time a test
+// runs and replays them on later runs, so integration tests are reproducible without a network.
+//
+// When the cassette does not exist yet every request is sent through the wrapped executor and the interaction is
+// appended to the cassette. When it exists, requests are answered from it instead, matched on method, URL and body;
+// each recorded interaction is replayed once, in order, so repeated identical requests replay successive responses.
+// Authorization and cookie headers are redacted in the cassette. Delete the cassette file to record afresh.
+type RecordingExecutor struct {
+	next      HTTPExecutor
+	path      string
+	replaying bool
+
+	mu       sync.Mutex
+	cassette cassette
+	used     []bool
+}
+
+// cassette is the on-disk format of a RecordingExecutor recording.
+type cassette struct {
+	Interactions []cassetteInteraction `json:"interactions"`
+}
+
+// cassetteInteraction is a single recorded request and the response it received.
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+// cassetteRequest is the recorded form of a request.
+type cassetteRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	cassetteBody
+}
+
+// cassetteResponse is the recorded form of a response.
+type cassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	cassetteBody
+}
+
+// cassetteBody holds a payload as text, or as base64 when it is not valid UTF-8, so binary bodies survive intact.
+type cassetteBody struct {
+	Body         string `json:"body,omitempty"`
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+// newCassetteBody encodes data for storage in a cassette.
+func newCassetteBody(data []byte) cassetteBody {
+	if utf8.Valid(data) {
+		return cassetteBody{Body: string(data)}
+	}
+	return cassetteBody{Body: base64.StdEncoding.EncodeToString(data), BodyEncoding: "base64"}
+}
+
+// bytes decodes the stored payload.
+func (b cassetteBody) bytes() ([]byte, error) {
+	if b.BodyEncoding == "base64" {
+		return base64.StdEncoding.DecodeString(b.Body)
+	}
+	return []byte(b.Body), nil
+}
+
+// NewRecordingExecutor returns a RecordingExecutor using the cassette at path. If the file exists its interactions
+// are replayed and next may be nil; otherwise requests are recorded through next, which is required. Use the result
+// as ClientConfig.HTTPExecutor.
+func NewRecordingExecutor(path string, next HTTPExecutor) (*RecordingExecutor, error) {
+	r := &RecordingExecutor{next: next, path: path}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("could not parse cassette %s: %w", path, err)
+		}
+		r.replaying = true
+		r.used = make([]bool, len(r.cassette.Interactions))
+	case errors.Is(err, os.ErrNotExist):
+		if next == nil {
+			return nil, fmt.Errorf("cassette %s does not exist and no executor was supplied to record with", path)
+		}
+	default:
+		return nil, fmt.Errorf("could not read cassette %s: %w", path, err)
+	}
+
+	return r, nil
+}
+
+// Replaying reports whether requests are answered from the cassette rather than recorded.
+func (r *RecordingExecutor) Replaying() bool {
+	return r.replaying
+}
+
+// Do replays the recorded response for req, or sends req through the wrapped executor and records the interaction.
+func (r *RecordingExecutor) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.replaying {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+// replay answers req from the first unused interaction recorded for the same method, URL and body.
+func (r *RecordingExecutor) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !interaction.matches(req, body) {
+			continue
+		}
+
+		respBody, err := interaction.Response.bytes()
+		if err != nil {
+			return nil, fmt.Errorf("corrupt response body in cassette %s: %w", r.path, err)
+		}
+		r.used[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Headers.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(respBody)),
+			ContentLength: int64(len(respBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoRecordedInteraction, req.Method, req.URL)
+}
+
+// matches reports whether the interaction was recorded for the same method, URL and body as req.
+func (i cassetteInteraction) matches(req *http.Request, body []byte) bool {
+	if i.Request.Method != req.Method || i.Request.URL != req.URL.String() {
+		return false
+	}
+	recorded, err := i.Request.bytes()
+	return err == nil && bytes.Equal(recorded, body)
+}
+
+// record sends req through the wrapped executor and appends the interaction to the cassette on disk.
+func (r *RecordingExecutor) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := cassetteInteraction{
+		Request: cassetteRequest{
+			Method:       req.Method,
+			URL:          req.URL.String(),
+			Headers:      redactHeaders(req.Header),
+			cassetteBody: newCassetteBody(body),
+		},
+		Response: cassetteResponse{
+			StatusCode:   resp.StatusCode,
+			Headers:      redactHeaders(resp.Header),
+			cassetteBody: newCassetteBody(respBody),
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// save writes the cassette to disk. Callers must hold r.mu.
+func (r *RecordingExecutor) save() error {
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, data, 0o600); err != nil {
+		return fmt.Errorf("could not write cassette %s: %w", r.path, err)
+	}
+	return nil
+}
+
+// redactHeaders returns a copy of header with credential values replaced.
+func redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range redactedHeaders {
+		if _, ok := redacted[name]; ok {
+			redacted[name] = []string{redactedHeaderValue}
+		}
+	}
+	return redacted
+}
+
+// Get issues a GET request through Do.
+func (r *RecordingExecutor) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.Do(req)
+}
+
+// Head issues a HEAD request through Do.
+func (r *RecordingExecutor) Head(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.Do(req)
+}
+
+// Post issues a POST request through Do.
+func (r *RecordingExecutor) Post(url string, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return r.Do(req)
+}
+
+// PostForm issues a form encoded POST request through Do.
+func (r *RecordingExecutor) PostForm(url string, data url.Values) (*http.Response, error) {
+	return r.Post(url, FormContentType, strings.NewReader(data.Encode()))
+}
+
+// CloseIdleConnections closes the wrapped executor's idle connections, if there is one.
+func (r *RecordingExecutor) CloseIdleConnections() {
+	if r.next != nil {
+		r.next.CloseIdleConnections()
+	}
+}
+
+// SetCookieJar forwards to the wrapped executor, if there is one.
+func (r *RecordingExecutor) SetCookieJar(jar http.CookieJar) {
+	if r.next != nil {
+		r.next.SetCookieJar(jar)
+	}
+}
+
+// SetCookies forwards to the wrapped executor, if there is one.
+func (r *RecordingExecutor) SetCookies(url *url.URL, cookies []*http.Cookie) {
+	if r.next != nil {
+		r.next.SetCookies(url, cookies)
+	}
+}
+
+// SetCustomTimeout forwards to the wrapped executor, if there is one.
+func (r *RecordingExecutor) SetCustomTimeout(timeout time.Duration) {
+	if r.next != nil {
+		r.next.SetCustomTimeout(timeout)
+	}
+}
+
+// Cookies forwards to the wrapped executor, if there is one.
+func (r *RecordingExecutor) Cookies(url *url.URL) []*http.Cookie {
+	if r.next != nil {
+		return r.next.Cookies(url)
+	}
+	return nil
+}
+
+// SetRedirectPolicy forwards to the wrapped executor, if there is one.
+func (r *RecordingExecutor) SetRedirectPolicy(policy *func(req *http.Request, via []*http.Request) error) {
+	if r.next != nil {
+		r.next.SetRedirectPolicy(policy)
+	}
+}
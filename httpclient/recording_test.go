@@ -0,0 +1,155 @@
+// httpclient/recording_test.go
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRecordingExecutor_RecordThenReplay(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":99}`))
+			return
+		}
+		w.Write([]byte(`{"id":` + strconv.Itoa(int(n)) + `}`))
+	}))
+	defer server.Close()
+
+	cassettePath := filepath.Join(t.TempDir(), "users.json")
+
+	run := func(executor *RecordingExecutor) []int {
+		t.Helper()
+		client := newTestClient(t, server.URL, func(config *ClientConfig) {
+			config.HTTPExecutor = executor
+		})
+
+		var ids []int
+		for _, call := range []struct {
+			method string
+			body   interface{}
+		}{{http.MethodGet, nil}, {http.MethodGet, nil}, {http.MethodPost, map[string]string{"name": "new"}}} {
+			var out struct {
+				ID int `json:"id"`
+			}
+			resp, err := client.DoRequest(call.method, "/users", call.body, &out)
+			if err != nil {
+				t.Fatalf("%s DoRequest() error = %v", call.method, err)
+			}
+			resp.Body.Close()
+			ids = append(ids, out.ID)
+		}
+		return ids
+	}
+
+	recorder, err := NewRecordingExecutor(cassettePath, &ProdExecutor{Client: server.Client()})
+	if err != nil {
+		t.Fatalf("NewRecordingExecutor() error = %v", err)
+	}
+	if recorder.Replaying() {
+		t.Fatal("Replaying() = true for a missing cassette")
+	}
+	recorded := run(recorder)
+
+	cassetteData, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatalf("reading cassette: %v", err)
+	}
+	if strings.Contains(string(cassetteData), "test-token") {
+		t.Error("cassette contains the Authorization token in clear text")
+	}
+	if !strings.Contains(string(cassetteData), redactedHeaderValue) {
+		t.Error("cassette does not record the redacted Authorization header")
+	}
+
+	server.Close()
+	hitsBeforeReplay := hits.Load()
+
+	replayer, err := NewRecordingExecutor(cassettePath, nil)
+	if err != nil {
+		t.Fatalf("NewRecordingExecutor() error = %v", err)
+	}
+	if !replayer.Replaying() {
+		t.Fatal("Replaying() = false for an existing cassette")
+	}
+	replayed := run(replayer)
+
+	if hits.Load() != hitsBeforeReplay {
+		t.Error("replay reached the server")
+	}
+	for i := range recorded {
+		if recorded[i] != replayed[i] {
+			t.Errorf("call %d replayed id %d, recorded %d", i, replayed[i], recorded[i])
+		}
+	}
+	if recorded[0] == recorded[1] {
+		t.Errorf("repeated GETs both replayed id %d, want successive responses", recorded[0])
+	}
+
+	// Every interaction has been used up, and unrecorded requests are never sent.
+	client := newTestClient(t, server.URL, func(config *ClientConfig) { config.HTTPExecutor = replayer })
+	var out map[string]interface{}
+	if _, err := client.DoRequest(http.MethodGet, "/users", nil, &out); !errors.Is(err, ErrNoRecordedInteraction) {
+		t.Errorf("DoRequest() error = %v, want ErrNoRecordedInteraction", err)
+	}
+}
+
+func TestRecordingExecutor_BinaryBody(t *testing.T) {
+	data := []byte{0xff, 0x00, 0xfe}
+	body := newCassetteBody(data)
+	if body.BodyEncoding != "base64" {
+		t.Fatalf("BodyEncoding = %q, want base64 for invalid UTF-8", body.BodyEncoding)
+	}
+	decoded, err := body.bytes()
+	if err != nil || string(decoded) != string(data) {
+		t.Errorf("bytes() = %v, %v; want the original payload", decoded, err)
+	}
+}
+
+func TestNewRecordingExecutor_MissingCassetteWithoutExecutor(t *testing.T) {
+	if _, err := NewRecordingExecutor(filepath.Join(t.TempDir(), "missing.json"), nil); err == nil {
+		t.Error("NewRecordingExecutor() error = nil, want an error when there is nothing to record with")
+	}
+}
+
+func TestRecordingExecutor_RedactsCookies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "issued-session-secret"})
+	}))
+	defer server.Close()
+
+	cassettePath := filepath.Join(t.TempDir(), "cookies.json")
+	recorder, err := NewRecordingExecutor(cassettePath, &ProdExecutor{Client: server.Client()})
+	if err != nil {
+		t.Fatalf("NewRecordingExecutor() error = %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/login", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "sent-session-secret"})
+	resp, err := recorder.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	cassetteData, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatalf("reading cassette: %v", err)
+	}
+	for _, secret := range []string{"sent-session-secret", "issued-session-secret"} {
+		if strings.Contains(string(cassetteData), secret) {
+			t.Errorf("cassette contains cookie value %q in clear text", secret)
+		}
+	}
+}
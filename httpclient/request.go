@@ -2,7 +2,6 @@
 package httpclient
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"net/http"
@@ -23,13 +22,15 @@ import (
 // number of times.
 // Parameters:
 //   - method: A string representing the HTTP method to be used for the request. This method determines the execution path
-//     and whether the request will be retried in case of failures.
+//     and whether the request will be retried in case of failures. It is matched case-insensitively and ignoring
+//     surrounding whitespace; an unknown method fails with ErrUnsupportedMethod before anything is sent.
 //   - endpoint: The target API endpoint for the request. This should be a relative path that will be appended to the base URL
 //     configured for the HTTP client.
 //   - body: The payload for the request, which will be serialized into the request body. The serialization format (e.g., JSON, XML)
 //     is determined by the content-type header and the	 specific implementation of the API handler used by the client.
 //   - out: A pointer to an output variable where the response will be deserialized. The function expects this to be a pointer to
 //     a struct that matches the expected response schema.
+//   - opts: Optional RequestOptions which adjust the behaviour of this request only, e.g. WithEnvelopeDecoder.
 //
 // Returns:
 //   - *http.Response: The HTTP response received from the server. In case of successful execution, this response contains
@@ -37,7 +38,8 @@ import (
 //     idempotent methods, this response may contain the last received HTTP response that led to the failure.
 //   - error: An error object indicating failure during request execution. This could be due to network issues, server errors,
 //     or a failure in request serialization/deserialization. For idempotent methods, an error is returned if all retries are
-//     exhausted without success.
+//     exhausted without success. A 304 Not Modified reply returns the response with ErrNotModified and leaves out
+//     untouched; check for it with errors.Is and keep the value already held.
 //
 // Usage:
 // This function is the primary entry point for executing HTTP requests using the client. It abstracts away the details of
@@ -58,13 +60,38 @@ import (
 //     within the client's concurrency model.
 //   - The decision to retry requests is based on the idempotency of the HTTP method and the client's retry configuration,
 //     including maximum retry attempts and total retry duration.
-func (c *Client) DoRequest(method, endpoint string, body, out interface{}) (*http.Response, error) {
+func (c *Client) DoRequest(method, endpoint string, body, out interface{}, opts ...RequestOption) (*http.Response, error) {
+	return c.doRequestContext(context.Background(), method, endpoint, body, out, opts...)
+}
+
+// doRequestContext is DoRequest bounded by parent as well as RequestDeadline.
+func (c *Client) doRequestContext(parent context.Context, method, endpoint string, body, out interface{}, opts ...RequestOption) (*http.Response, error) {
+	method, err := normalizeHTTPMethod(method)
+	if err != nil {
+		return nil, err
+	}
 
+	ro := c.newRequestOptions(opts)
+
+	ctx, cancel := c.requestDeadlineContext(parent)
+
+	var resp *http.Response
+	if c.failover != nil {
+		resp, err = c.doRequestWithFailover(ctx, method, endpoint, body, out, ro)
+	} else {
+		resp, err = c.doRequest(ctx, method, endpoint, body, out, ro)
+	}
+
+	return c.finishRequestDeadline(parent, ctx, cancel, resp, err)
+}
+
+// doRequest dispatches a request to the retrying or non-retrying flow depending on the method's idempotency.
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, body, out interface{}, ro *requestOptions) (*http.Response, error) {
 	if !c.config.RetryEligiableRequests || !isIdempotentHTTPMethod(method) {
-		return c.requestNoRetries(method, endpoint, body, out)
+		return c.requestNoRetries(ctx, method, endpoint, body, out, ro)
 	}
 
-	return c.requestWithRetries(method, endpoint, body, out)
+	return c.requestWithRetries(ctx, method, endpoint, body, out, ro)
 }
 
 // requestWithRetries executes an HTTP request using the specified method, endpoint, request body, and output variable.
@@ -74,6 +101,7 @@ func (c *Client) DoRequest(method, endpoint string, body, out interface{}) (*htt
 // thundering herd problems. An instance of a logger (conforming to the logger.Logger interface) is used for logging the
 // request, retry attempts, and any errors encountered.
 // Parameters:
+// - ctx: Bounds every attempt and backoff; once it is done no further attempt is made.
 // - method: The HTTP method to be used for the request (e.g., "GET", "PUT", "DELETE").
 // - endpoint: The API endpoint to which the request will be sent. This should be a relative path that will be appended
 // to the base URL of the HTTP client.
@@ -96,14 +124,15 @@ func (c *Client) DoRequest(method, endpoint string, body, out interface{}) (*htt
 // - The function respects the client's concurrency token, acquiring and releasing it as needed to ensure safe concurrent
 // operations.
 // - The retry mechanism employs exponential backoff with jitter to mitigate the impact of retries on the server.
-func (c *Client) requestWithRetries(method, endpoint string, body, out interface{}) (*http.Response, error) {
+func (c *Client) requestWithRetries(ctx context.Context, method, endpoint string, body, out interface{}, ro *requestOptions) (*http.Response, error) {
 	var resp *http.Response
 	var err error
 	var retryCount int
 
-	ctx := context.Background()
+	ctx = c.withRequestID(ctx, ro)
+	log := c.requestLogger(ctx)
 
-	c.Sugar.Debug("Executing request with retries", zap.String("method", method), zap.String("endpoint", endpoint))
+	log.Debug("Executing request with retries", zap.String("method", method), zap.String("endpoint", endpoint))
 
 	// TODO removed the blocked comments
 	// Simplify this?
@@ -112,19 +141,43 @@ func (c *Client) requestWithRetries(method, endpoint string, body, out interface
 	for time.Now().Before(totalRetryDeadline) {
 
 		// Resp
-		resp, requestErr := c.request(ctx, method, endpoint, body)
+		var requestErr error
+		resp, requestErr = c.request(ctx, method, endpoint, body, ro)
+		if requestErr == nil && c.isSuccessStatus(resp.StatusCode) && ro.onRecord == nil {
+			requestErr = bufferResponseBody(resp)
+		}
 		if requestErr != nil {
-			return nil, requestErr
+			if ctx.Err() != nil || !isRetryableNetworkError(requestErr, method) {
+				return nil, requestErr
+			}
+
+			retryCount++
+			if retryCount > c.config.MaxRetryAttempts {
+				log.Warn("Max retry attempts reached", zap.String("method", method), zap.String("endpoint", endpoint))
+				return nil, requestErr
+			}
+			waitDuration := c.retryBackoff(retryCount)
+			c.warnSampled("Retrying request due to network error", zap.String("method", method), zap.String("endpoint", endpoint), zap.Int("retryCount", retryCount), zap.Duration("waitDuration", waitDuration), zap.Error(requestErr), zap.String("request_id", ro.requestID))
+			if err := c.backoff.Wait(ctx, waitDuration); err != nil {
+				return nil, err
+			}
+			err = requestErr
+			continue
 		}
+		err = nil
 
 		// Success
-		if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusBadRequest {
+		if c.isSuccessStatus(resp.StatusCode) {
 			if resp.StatusCode == http.StatusPermanentRedirect || resp.StatusCode == http.StatusTemporaryRedirect {
-				c.Sugar.Warn("Redirect response received", zap.Int("status_code", resp.StatusCode), zap.String("location", resp.Header.Get("Location")))
+				log.Warn("Redirect response received", zap.Int("status_code", resp.StatusCode), zap.String("location", resp.Header.Get("Location")))
+			}
+			log.Infof("%s request successful at %v", resp.Request.Method, resp.Request.URL)
+
+			if statusURL := c.asyncOperationURL(resp); statusURL != "" {
+				return c.followAsyncOperation(ctx, resp, statusURL, out, ro)
 			}
-			c.Sugar.Infof("%s request successful at %v", resp.Request.Method, resp.Request.URL)
 
-			return resp, response.HandleAPISuccessResponse(resp, out, c.Sugar)
+			return resp, c.handleSuccessResponse(resp, out, ro)
 		}
 
 		// Message
@@ -135,17 +188,20 @@ func (c *Client) requestWithRetries(method, endpoint string, body, out interface
 
 		// Non Retry
 		if response.IsNonRetryableStatusCode(resp.StatusCode) {
-			c.Sugar.Warn("Non-retryable error received", zap.Int("status_code", resp.StatusCode), zap.String("status_message", statusMessage))
+			log.Warn("Non-retryable error received", zap.Int("status_code", resp.StatusCode), zap.String("status_message", statusMessage))
 
-			return resp, response.HandleAPIErrorResponse(resp, c.Sugar)
+			return resp, c.handleErrorResponse(resp)
 		}
 
 		// Rate limited
 		if resp.StatusCode == http.StatusTooManyRequests {
-			waitDuration := ratehandler.ParseRateLimitHeaders(resp, c.Sugar)
+			waitDuration := ratehandler.ParseRateLimitHeaders(resp, c.Logger())
 			if waitDuration > 0 {
-				c.Sugar.Warn("Rate limit encountered, waiting before retrying", zap.Duration("waitDuration", waitDuration))
-				time.Sleep(waitDuration)
+				c.warnSampled("Rate limit encountered, waiting before retrying", zap.Duration("waitDuration", waitDuration), zap.String("request_id", ro.requestID))
+				releaseRetriedBody(resp)
+				if err := c.backoff.Wait(ctx, waitDuration); err != nil {
+					return nil, err
+				}
 				continue
 			}
 		}
@@ -154,36 +210,52 @@ func (c *Client) requestWithRetries(method, endpoint string, body, out interface
 		if response.IsTransientError(resp.StatusCode) {
 			retryCount++
 			if retryCount > c.config.MaxRetryAttempts {
-				c.Sugar.Warn("Max retry attempts reached", zap.String("method", method), zap.String("endpoint", endpoint))
+				log.Warn("Max retry attempts reached", zap.String("method", method), zap.String("endpoint", endpoint))
 				break
 			}
-			waitDuration := ratehandler.CalculateBackoff(retryCount)
-			c.Sugar.Warn("Retrying request due to transient error", zap.String("method", method), zap.String("endpoint", endpoint), zap.Int("retryCount", retryCount), zap.Duration("waitDuration", waitDuration), zap.Error(err))
-			time.Sleep(waitDuration)
+			waitDuration := c.retryBackoff(retryCount)
+			c.warnSampled("Retrying request due to transient error", zap.String("method", method), zap.String("endpoint", endpoint), zap.Int("retryCount", retryCount), zap.Duration("waitDuration", waitDuration), zap.Int("status_code", resp.StatusCode), zap.String("request_id", ro.requestID))
+			releaseRetriedBody(resp)
+			if err := c.backoff.Wait(ctx, waitDuration); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
 		// Retryable
 		if !response.IsRetryableStatusCode(resp.StatusCode) {
-			if apiErr := response.HandleAPIErrorResponse(resp, c.Sugar); apiErr != nil {
+			if apiErr := c.handleErrorResponse(resp); apiErr != nil {
 				err = apiErr
 			}
 			break
 		}
-
+		releaseRetriedBody(resp)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	return resp, response.HandleAPIErrorResponse(resp, c.Sugar)
+	if resp == nil {
+		return nil, fmt.Errorf("total retry duration of %v exceeded before a response was received", c.config.TotalRetryDuration)
+	}
+
+	return resp, c.handleErrorResponse(resp)
+}
+
+// releaseRetriedBody reads and closes the body of a response about to be retried, returning its connection to the
+// pool before the backoff wait. The buffered copy still serves the final error if no retry succeeds.
+func releaseRetriedBody(resp *http.Response) {
+	if err := bufferResponseBody(resp); err != nil {
+		resp.Body = http.NoBody
+	}
 }
 
 // requestNoRetries executes an HTTP request using the specified method, endpoint, and request body without implementing
 // retry logic. It is primarily designed for non-idempotent HTTP methods like POST and PATCH, where the request should
 // not be automatically retried within this function due to the potential side effects of re-submitting the same data.
 // Parameters:
+//   - ctx: The context the request is sent with; cancelling it abandons the request.
 //   - method: The HTTP method to be used for the request, typically "POST" or "PATCH".
 //   - endpoint: The API endpoint to which the request will be sent. This should be a relative path that will be appended
 //     to the base URL of the HTTP client.
@@ -207,86 +279,141 @@ func (c *Client) requestWithRetries(method, endpoint string, body, out interface
 //     execution.
 //   - The function logs detailed information about the request execution, including the method, endpoint, status code, and
 //     any errors encountered.
-func (c *Client) requestNoRetries(method, endpoint string, body, out interface{}) (*http.Response, error) {
-	ctx := context.Background()
+func (c *Client) requestNoRetries(ctx context.Context, method, endpoint string, body, out interface{}, ro *requestOptions) (*http.Response, error) {
+	ctx = c.withRequestID(ctx, ro)
+	log := c.requestLogger(ctx)
 
-	c.Sugar.Debugw("Executing request without retries", "method", method, "endpoint", endpoint)
+	log.Debugw("Executing request without retries", "method", method, "endpoint", endpoint)
 
-	resp, err := c.request(ctx, method, endpoint, body)
+	resp, err := c.request(ctx, method, endpoint, body, ro)
+	if err == nil && c.isSuccessStatus(resp.StatusCode) && ro.onRecord == nil {
+		err = bufferResponseBody(resp)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	c.Sugar.Debugf("Status Code: %v", resp.StatusCode)
+	log.Debugf("Status Code: %v", resp.StatusCode)
 
-	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusBadRequest {
+	if c.isSuccessStatus(resp.StatusCode) {
 		if resp.StatusCode == http.StatusPermanentRedirect || resp.StatusCode == http.StatusTemporaryRedirect {
-			c.Sugar.Warn("Redirect response received", zap.Int("status_code", resp.StatusCode), zap.String("location", resp.Header.Get("Location")))
+			log.Warn("Redirect response received", zap.Int("status_code", resp.StatusCode), zap.String("location", resp.Header.Get("Location")))
+		}
+		log.Infof("%s request successful at %v", resp.Request.Method, resp.Request.URL)
+
+		if statusURL := c.asyncOperationURL(resp); statusURL != "" {
+			return c.followAsyncOperation(ctx, resp, statusURL, out, ro)
 		}
-		c.Sugar.Infof("%s request successful at %v", resp.Request.Method, resp.Request.URL)
 
-		return resp, response.HandleAPISuccessResponse(resp, out, c.Sugar)
+		return resp, c.handleSuccessResponse(resp, out, ro)
 	}
 
-	return nil, response.HandleAPIErrorResponse(resp, c.Sugar)
+	return nil, c.handleErrorResponse(resp)
 }
 
 // request is a base leve private function which the contextual functions above utilise to make requests // TODO improve this comment probably.
-func (c *Client) request(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
+func (c *Client) request(ctx context.Context, method, endpoint string, body interface{}, ro *requestOptions) (*http.Response, error) {
+	ctx = c.withRequestID(ctx, ro)
+	log := c.requestLogger(ctx)
 
 	if c.config.EnableConcurrencyManagement {
-		_, requestID, err := c.Concurrency.AcquireConcurrencyPermit(ctx)
+		_, requestID, err := c.Concurrency.AcquireWeightedConcurrencyPermit(ctx, ro.weight)
 		if err != nil {
-			return nil, fmt.Errorf("failed to acquire concurrency permit: %v", err)
-
+			return nil, fmt.Errorf("failed to acquire concurrency permit: %w", err)
 		}
 
-		defer func() {
-			c.Concurrency.ReleaseConcurrencyPermit(requestID)
-		}()
+		// Registered before any fallible work so the permit is returned on every exit path, including panics
+		// raised by the integration or the concurrency metrics further down.
+		defer c.Concurrency.ReleaseConcurrencyPermit(requestID)
 
 		c.Concurrency.Metrics.Lock()
 		c.Concurrency.Metrics.TotalRequests++
 		c.Concurrency.Metrics.Unlock()
 	}
 
-	requestData, err := (*c.Integration).PrepRequestBody(body, method, endpoint)
+	req, err := c.buildRequest(ctx, method, endpoint, body, ro)
 	if err != nil {
 		return nil, err
 	}
-	requestDataBytes := bytes.NewBuffer(requestData)
 
-	url := (*c.Integration).ConstructURL(endpoint)
-
-	req, err := http.NewRequest(method, url, requestDataBytes)
-	if err != nil {
+	if err := c.runRequestHook(req); err != nil {
 		return nil, err
 	}
 
-	err = (*c.Integration).PrepRequestParamsAndAuth(req)
-	if err != nil {
+	if err := c.spacer.wait(ctx, req.URL.Host, c.mandatoryRequestDelay()); err != nil {
 		return nil, err
 	}
 
 	startTime := time.Now()
 
-	req = req.WithContext(ctx)
-	resp, err := c.http.Do(req)
+	resp, err := c.do(req)
+	if err != nil && c.config.ReResolveOnConnectionError && isConnectionError(err) && isIdempotentHTTPMethod(method) {
+		resp, err = c.retryWithFreshConnection(req, err)
+	}
 	if err != nil {
-		c.Sugar.Error("Failed to send request", zap.String("method", method), zap.String("endpoint", endpoint), zap.Error(err))
+		log.Error("Failed to send request", zap.String("method", method), zap.String("endpoint", endpoint), zap.Error(err))
+		return nil, err
+	}
+
+	if err := c.limitResponseBody(resp); err != nil {
+		return nil, err
+	}
+
+	if err := c.runResponseHook(resp); err != nil {
 		return nil, err
 	}
 
+	duration := time.Since(startTime)
+
 	if c.config.EnableConcurrencyManagement {
-		duration := time.Since(startTime)
 		c.Concurrency.EvaluateAndAdjustConcurrency(resp, duration)
 	}
 
-	c.CheckDeprecationHeader(resp)
+	c.checkSLA(endpoint, duration)
 
-	c.Sugar.Debug("Request sent successfully", zap.String("method", method), zap.String("endpoint", endpoint), zap.Int("status_code", resp.StatusCode), zap.Any("raw_response", resp))
+	c.CheckDeprecationHeader(resp)
 
-	time.Sleep(c.config.MandatoryRequestDelay)
+	log.Debugw("Request sent successfully", zap.String("method", method), zap.String("endpoint", endpoint), zap.Int("status_code", resp.StatusCode))
+	c.logResponseBody(method, endpoint, resp)
+	c.logRateLimitInfo(method, endpoint, resp)
 
 	return resp, nil
 }
+
+// buildRequest assembles the request exactly as it is sent: marshalled (and possibly compressed) body, URL, auth,
+// and headers. It does not run the OnRequest hook.
+func (c *Client) buildRequest(ctx context.Context, method, endpoint string, body interface{}, ro *requestOptions) (*http.Request, error) {
+	requestBody, compressed, err := c.prepareRequestBody(method, endpoint, body, ro)
+	if err != nil {
+		return nil, err
+	}
+
+	url := c.requestURL(endpoint, ro)
+	if err := c.checkHostPolicy(ctx, url); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.prepRequestAuth(req)
+	if err != nil {
+		return nil, err
+	}
+	c.setUserAgent(req, ro)
+	if ro.rawContentType != "" {
+		req.Header.Set("Content-Type", ro.rawContentType)
+	} else if ro.bodyContentType != "" && ro.rawBody == nil && ro.formBody == nil {
+		req.Header.Set("Content-Type", ro.bodyContentType)
+	}
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	c.setAcceptLanguage(req)
+	c.setRequestIDHeader(req)
+	c.setRequestHeaders(req, ro)
+
+	return req, nil
+}
@@ -0,0 +1,190 @@
+// httpclient/request_test.go
+package httpclient
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// panickingIntegration panics while marshalling the request body.
+type panickingIntegration struct {
+	MockIntegration
+}
+
+func (i *panickingIntegration) PrepRequestBody(body interface{}, method string, endpoint string) ([]byte, error) {
+	panic("marshal exploded")
+}
+
+func TestRequest_PanicReleasesPermit(t *testing.T) {
+	executor := &MockExecutor{LockedResponseCode: http.StatusOK}
+	client := newTestClient(t, "http://192.0.2.1", func(config *ClientConfig) {
+		config.Integration = &panickingIntegration{}
+		config.HTTPExecutor = executor
+		config.EnableConcurrencyManagement = true
+		config.MaxConcurrentRequests = 1
+	})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected the integration panic to propagate")
+			}
+		}()
+		client.DoRequest(http.MethodPost, "/items", map[string]string{"name": "x"}, nil)
+	}()
+
+	if inUse := client.Concurrency.PermitsInUse(); inUse != 0 {
+		t.Fatalf("permits in use after panic = %d, want 0", inUse)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() { recover() }()
+		client.DoRequest(http.MethodPost, "/items", nil, nil)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("second request blocked waiting for a leaked permit")
+	}
+}
+
+// permitCheckingExecutor records how many concurrency tokens are held while a request is in flight.
+type permitCheckingExecutor struct {
+	MockExecutor
+	client *Client
+	inUse  int
+}
+
+func (e *permitCheckingExecutor) Do(req *http.Request) (*http.Response, error) {
+	e.inUse = e.client.Concurrency.PermitsInUse()
+	return e.MockExecutor.Do(req)
+}
+
+func TestWithRequestWeight(t *testing.T) {
+	executor := &permitCheckingExecutor{MockExecutor: MockExecutor{LockedResponseCode: http.StatusNoContent}}
+	client := newTestClient(t, "http://192.0.2.1", func(config *ClientConfig) {
+		config.HTTPExecutor = executor
+		config.EnableConcurrencyManagement = true
+		config.MaxConcurrentRequests = 5
+	})
+	executor.client = client
+
+	for _, weight := range []int{1, 3} {
+		if _, err := client.DoRequest(http.MethodDelete, "/items/1", nil, nil, WithRequestWeight(weight)); err != nil {
+			t.Fatalf("DoRequest() error = %v", err)
+		}
+		if executor.inUse != weight {
+			t.Errorf("tokens held in flight = %d, want %d", executor.inUse, weight)
+		}
+		if inUse := client.Concurrency.PermitsInUse(); inUse != 0 {
+			t.Errorf("tokens held after request = %d, want 0", inUse)
+		}
+	}
+}
+
+func TestDoRequestDeleteWithBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		response    string
+		wantErr     bool
+		wantDeleted int
+	}{
+		{name: "json result decoded", status: http.StatusOK, contentType: "application/json", response: `{"deleted":2}`, wantDeleted: 2},
+		{name: "no content", status: http.StatusNoContent},
+		{name: "plain text acknowledgement", status: http.StatusOK, contentType: "text/plain", response: "ok"},
+		{name: "error reported", status: http.StatusNotFound, contentType: "application/json", response: `{"error":"index missing"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMethod, gotBody, gotContentType string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				gotMethod, gotBody, gotContentType = r.Method, string(data), r.Header.Get("Content-Type")
+
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client := newTestClient(t, server.URL, nil)
+
+			var out struct {
+				Deleted int `json:"deleted"`
+			}
+			query := map[string]interface{}{"query": map[string]string{"match": "stale"}}
+			_, err := client.DoRequest(http.MethodDelete, "/index/_delete_by_query", query, &out)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DoRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotMethod != http.MethodDelete {
+				t.Errorf("method = %s, want DELETE", gotMethod)
+			}
+			if gotBody != `{"query":{"match":"stale"}}` {
+				t.Errorf("body = %q, want the JSON query", gotBody)
+			}
+			if gotContentType != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", gotContentType)
+			}
+			if out.Deleted != tt.wantDeleted {
+				t.Errorf("out.Deleted = %d, want %d", out.Deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestRequestWithRetries_ReusesConnection(t *testing.T) {
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			var calls, connections atomic.Int32
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) <= 2 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(status)
+					w.Write([]byte(`{"error":"try again"}`))
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{}`))
+			}))
+			server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					connections.Add(1)
+				}
+			}
+			server.Start()
+			defer server.Close()
+
+			client := newTestClient(t, server.URL, func(config *ClientConfig) {
+				config.RetryEligiableRequests = true
+				config.MaxRetryAttempts = 3
+				config.TotalRetryDuration = 10 * time.Second
+			})
+
+			var out map[string]interface{}
+			resp, err := client.DoRequest(http.MethodGet, "/resource", nil, &out)
+			if err != nil {
+				t.Fatalf("DoRequest() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if got := connections.Load(); got != 1 {
+				t.Errorf("connections opened = %d, want 1 (retried responses must release their connection)", got)
+			}
+		})
+	}
+}
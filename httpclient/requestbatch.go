@@ -0,0 +1,105 @@
+// httpclient/requestbatch.go
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/deploymenttheory/go-api-http-client/response"
+	"go.uber.org/zap"
+)
+
+// BatchItem is one request sent by DoRequestBatch. Method, Endpoint, Body, Out and Options have the same meaning as
+// the arguments of DoRequest.
+type BatchItem struct {
+	Method   string
+	Endpoint string
+	Body     interface{}
+	Out      interface{}
+	Options  []RequestOption
+}
+
+// BatchItemResult is the outcome of one BatchItem.
+type BatchItemResult struct {
+	// StatusCode is the status of the final response, or 0 if none was received.
+	StatusCode int
+	// Err is nil when the request succeeded, in which case the item's Out has been populated.
+	Err error
+}
+
+// DoRequestBatch sends many independent requests concurrently, using at most as many workers as the client's
+// concurrency limit, and returns one result per item in input order. Each item goes through the normal DoRequest
+// machinery, so retries and concurrency permits apply per item, and a failure does not affect the other items.
+//
+// When ctx ends no further items are started; items not yet started report ctx's error, and items in flight are
+// abandoned with it as their requests are bound to ctx.
+func (c *Client) DoRequestBatch(ctx context.Context, reqs []BatchItem) []BatchItemResult {
+	results := make([]BatchItemResult, len(reqs))
+
+	select {
+	case <-c.done:
+		for i := range results {
+			results[i].Err = ErrClientClosed
+		}
+		return results
+	default:
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < c.bulkWorkers(len(reqs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = c.doBatchItem(ctx, reqs[i])
+			}
+		}()
+	}
+
+	launched := 0
+dispatch:
+	for ; launched < len(reqs); launched++ {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- launched:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := launched; i < len(reqs); i++ {
+		results[i].Err = ctx.Err()
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	c.Logger().Infow("Request batch completed", zap.Int("total", len(reqs)), zap.Int("launched", launched), zap.Int("failed", failed))
+
+	return results
+}
+
+// doBatchItem sends a single batch item and records its outcome.
+func (c *Client) doBatchItem(ctx context.Context, item BatchItem) BatchItemResult {
+	var result BatchItemResult
+
+	resp, err := c.doRequestContext(ctx, item.Method, item.Endpoint, item.Body, item.Out, item.Options...)
+	if resp != nil {
+		result.StatusCode = resp.StatusCode
+		resp.Body.Close()
+	}
+
+	var apiErr *response.APIError
+	if result.StatusCode == 0 && errors.As(err, &apiErr) {
+		result.StatusCode = apiErr.StatusCode
+	}
+	result.Err = err
+
+	return result
+}
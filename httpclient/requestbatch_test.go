@@ -0,0 +1,127 @@
+// httpclient/requestbatch_test.go
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoRequestBatch(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.MaxConcurrentRequests = 2
+	})
+
+	type payload struct {
+		Path string `json:"path"`
+	}
+	outs := make([]payload, 6)
+	items := []BatchItem{
+		{Method: http.MethodGet, Endpoint: "/a", Out: &outs[0]},
+		{Method: http.MethodPost, Endpoint: "/b", Body: map[string]string{"name": "b"}, Out: &outs[1]},
+		{Method: http.MethodGet, Endpoint: "/missing", Out: &outs[2]},
+		{Method: http.MethodGet, Endpoint: "/d", Out: &outs[3]},
+		{Method: http.MethodGet, Endpoint: "/e", Out: &outs[4], Options: []RequestOption{WithHeaders(map[string]string{"X-Test": "1"})}},
+		{Method: http.MethodGet, Endpoint: "/f", Out: &outs[5]},
+	}
+
+	results := client.DoRequestBatch(context.Background(), items)
+
+	if len(results) != len(items) {
+		t.Fatalf("got %d results, want %d", len(results), len(items))
+	}
+	for i, result := range results {
+		wantStatus := http.StatusOK
+		switch i {
+		case 1:
+			wantStatus = http.StatusCreated
+		case 2:
+			wantStatus = http.StatusNotFound
+		}
+		if result.StatusCode != wantStatus {
+			t.Errorf("results[%d].StatusCode = %d, want %d", i, result.StatusCode, wantStatus)
+		}
+		if (result.Err != nil) != (i == 2) {
+			t.Errorf("results[%d].Err = %v", i, result.Err)
+		}
+		if i != 2 && outs[i].Path != items[i].Endpoint {
+			t.Errorf("outs[%d].Path = %q, want %q", i, outs[i].Path, items[i].Endpoint)
+		}
+	}
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("max concurrent requests = %d, want at most MaxConcurrentRequests (2)", got)
+	}
+}
+
+func TestDoRequestBatch_ContextCancellationStopsLaunching(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		cancel()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.MaxConcurrentRequests = 1
+	})
+
+	items := make([]BatchItem, 10)
+	for i := range items {
+		items[i] = BatchItem{Method: http.MethodGet, Endpoint: "/resource", Out: &map[string]interface{}{}}
+	}
+
+	results := client.DoRequestBatch(ctx, items)
+
+	if got := hits.Load(); got != 1 {
+		t.Errorf("server hits = %d, want 1 after the context was cancelled", got)
+	}
+	for i, result := range results[1:] {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("results[%d].Err = %v, want context.Canceled", i+1, result.Err)
+		}
+	}
+}
+
+func TestDoRequestBatch_ClosedClient(t *testing.T) {
+	client := newTestClient(t, "http://192.0.2.1", nil)
+	client.Close()
+
+	results := client.DoRequestBatch(context.Background(), []BatchItem{{Method: http.MethodGet, Endpoint: "/a"}})
+	if len(results) != 1 || !errors.Is(results[0].Err, ErrClientClosed) {
+		t.Errorf("results = %+v, want ErrClientClosed", results)
+	}
+}
@@ -0,0 +1,102 @@
+// httpclient/requestencoding_test.go
+package httpclient
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type xmlComputer struct {
+	XMLName xml.Name `xml:"computer"`
+	Name    string   `xml:"name"`
+	Serial  string   `xml:"serial"`
+}
+
+func TestDoRequest_XMLRequestRoundTrip(t *testing.T) {
+	const namespace = "urn:example:inventory"
+
+	var gotBody, gotContentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		gotContentType = r.Header.Get("Content-Type")
+
+		var received struct {
+			XMLName xml.Name `xml:"urn:example:inventory computer"`
+			Name    string   `xml:"name"`
+			Serial  string   `xml:"serial"`
+		}
+		if err := xml.Unmarshal(body, &received); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/xml")
+		xml.NewEncoder(w).Encode(xmlComputer{Name: received.Name, Serial: strings.ToUpper(received.Serial)})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.XMLRootNamespace = namespace
+	})
+
+	var out xmlComputer
+	resp, err := client.DoRequest(http.MethodPost, "/computers", xmlComputer{Name: "mac-01", Serial: "c02abc"}, &out,
+		WithRequestContentType(XMLContentType))
+	if err != nil {
+		t.Fatalf("DoRequest() error = %v (server received %q)", err, gotBody)
+	}
+	resp.Body.Close()
+
+	if !strings.HasPrefix(gotBody, xml.Header) {
+		t.Errorf("body = %q, want it to start with the XML declaration", gotBody)
+	}
+	if !strings.Contains(gotBody, `<computer xmlns="`+namespace+`">`) {
+		t.Errorf("body = %q, want the root namespace declared", gotBody)
+	}
+	if gotContentType != XMLContentType {
+		t.Errorf("Content-Type = %q, want %q", gotContentType, XMLContentType)
+	}
+	if out.Name != "mac-01" || out.Serial != "C02ABC" {
+		t.Errorf("out = %+v, want the decoded response", out)
+	}
+}
+
+func TestEncodeXMLBody(t *testing.T) {
+	type plain struct {
+		Value int `xml:"value"`
+	}
+
+	tests := []struct {
+		name      string
+		body      interface{}
+		namespace string
+		want      string
+	}{
+		{name: "declaration without namespace", body: xmlComputer{Name: "a"}, want: xml.Header + "<computer><name>a</name><serial></serial></computer>"},
+		{name: "root name from type", body: plain{Value: 1}, namespace: "urn:x", want: xml.Header + `<plain xmlns="urn:x"><value>1</value></plain>`},
+		{name: "nil body", body: nil, namespace: "urn:x", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodeXMLBody(tt.body, tt.namespace)
+			if err != nil {
+				t.Fatalf("encodeXMLBody() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("encodeXMLBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeRequestBody_UnsupportedContentType(t *testing.T) {
+	client := newTestClient(t, "http://192.0.2.1", nil)
+	if _, err := client.encodeRequestBody(map[string]string{}, "application/yaml"); err == nil {
+		t.Error("encodeRequestBody() error = nil, want an error for an unsupported content type")
+	}
+}
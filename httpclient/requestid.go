@@ -0,0 +1,82 @@
+// httpclient/requestid.go
+package httpclient
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultRequestIDHeader is the header carrying the request id when ClientConfig.RequestIDHeader is not set.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the context key under which the request id is stored.
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying id. Requests sent with that context, e.g. through DoPole, use
+// id as their request id instead of generating one.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request id carried by ctx, or "" when there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestIDFromResponse returns the request id the client sent the request answered by resp with, or "" when resp
+// was not produced by the client.
+func RequestIDFromResponse(resp *http.Response) string {
+	if resp == nil || resp.Request == nil {
+		return ""
+	}
+	return RequestIDFromContext(resp.Request.Context())
+}
+
+// WithRequestID sends the request with id as its request id instead of a generated one, so the caller knows the id
+// before the call returns, including when it fails without a response.
+func WithRequestID(id string) RequestOption {
+	return func(ro *requestOptions) {
+		ro.requestID = id
+	}
+}
+
+// withRequestID resolves the request id, preferring WithRequestID, then one carried by ctx, then a new UUID, and
+// returns ctx carrying it. The id is kept in ro so every retry of the call shares it.
+func (c *Client) withRequestID(ctx context.Context, ro *requestOptions) context.Context {
+	if ro.requestID == "" {
+		ro.requestID = RequestIDFromContext(ctx)
+	}
+	if ro.requestID == "" {
+		ro.requestID = uuid.NewString()
+	}
+	if RequestIDFromContext(ctx) == ro.requestID {
+		return ctx
+	}
+	return ContextWithRequestID(ctx, ro.requestID)
+}
+
+// requestLogger returns the client logger annotated with the request id carried by ctx.
+func (c *Client) requestLogger(ctx context.Context) *zap.SugaredLogger {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return c.Logger().With(zap.String("request_id", id))
+	}
+	return c.Logger()
+}
+
+// setRequestIDHeader sends the request id carried by the request's context in the configured header.
+func (c *Client) setRequestIDHeader(req *http.Request) {
+	id := RequestIDFromContext(req.Context())
+	if id == "" {
+		return
+	}
+
+	header := c.config.RequestIDHeader
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	req.Header.Set(header, id)
+}
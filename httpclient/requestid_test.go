@@ -0,0 +1,234 @@
+// httpclient/requestid_test.go
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// requestIDServer answers with 503 until failures requests have been made, then 200, recording the header value
+// of every request.
+func requestIDServer(t *testing.T, header string, failures int32) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var seen []string
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Get(header))
+		mu.Unlock()
+
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+func TestDoRequest_GeneratedRequestIDSharedAcrossRetries(t *testing.T) {
+	server, seen := requestIDServer(t, DefaultRequestIDHeader, 1)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.Sugar = zap.New(core).Sugar()
+		config.RetryEligiableRequests = true
+		config.MaxRetryAttempts = 2
+		config.TotalRetryDuration = 10 * time.Second
+	})
+	logs.TakeAll()
+
+	var out map[string]interface{}
+	resp, err := client.DoRequest(http.MethodGet, "/resource", nil, &out)
+	if err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	resp.Body.Close()
+
+	ids := seen()
+	if len(ids) != 2 {
+		t.Fatalf("server saw %d requests, want 2", len(ids))
+	}
+	if _, err := uuid.Parse(ids[0]); err != nil {
+		t.Errorf("%s = %q, want a generated UUID", DefaultRequestIDHeader, ids[0])
+	}
+	if ids[1] != ids[0] {
+		t.Errorf("retry sent request id %q, want the original %q", ids[1], ids[0])
+	}
+	if got := RequestIDFromResponse(resp); got != ids[0] {
+		t.Errorf("RequestIDFromResponse() = %q, want %q", got, ids[0])
+	}
+
+	sent := logs.FilterMessage("Request sent successfully").All()
+	if len(sent) == 0 {
+		t.Fatal("no request log lines recorded")
+	}
+	for _, entry := range sent {
+		if entry.ContextMap()["request_id"] != ids[0] {
+			t.Errorf("log line %q request_id = %v, want %q", entry.Message, entry.ContextMap()["request_id"], ids[0])
+		}
+	}
+}
+
+func TestDoRequest_CallerSuppliedRequestID(t *testing.T) {
+	const header = "X-Correlation-ID"
+	server, seen := requestIDServer(t, header, 0)
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.RequestIDHeader = header
+	})
+
+	var out map[string]interface{}
+	result, err := client.DoRequestDetailed(http.MethodPost, "/resource", map[string]string{}, &out, WithRequestID("caller-id-1"))
+	if err != nil {
+		t.Fatalf("DoRequestDetailed() error = %v", err)
+	}
+	result.Response.Body.Close()
+
+	if ids := seen(); len(ids) != 1 || ids[0] != "caller-id-1" {
+		t.Errorf("%s values = %q, want [caller-id-1]", header, ids)
+	}
+	if result.RequestID != "caller-id-1" {
+		t.Errorf("Result.RequestID = %q, want caller-id-1", result.RequestID)
+	}
+}
+
+func TestDoPole_RequestIDFromContext(t *testing.T) {
+	server, seen := requestIDServer(t, DefaultRequestIDHeader, 0)
+	client := newTestClient(t, server.URL, nil)
+
+	var out map[string]interface{}
+	resp, err := client.DoPole(ContextWithRequestID(context.Background(), "ctx-id"), http.MethodGet, "/status", nil, &out)
+	if err != nil {
+		t.Fatalf("DoPole() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if ids := seen(); len(ids) != 1 || ids[0] != "ctx-id" {
+		t.Errorf("%s values = %q, want [ctx-id]", DefaultRequestIDHeader, ids)
+	}
+}
+
+func TestPreviewRequest_NoRequestID(t *testing.T) {
+	client := newTestClient(t, "https://example.com", nil)
+
+	req, err := client.PreviewRequest(http.MethodGet, "/resource", nil)
+	if err != nil {
+		t.Fatalf("PreviewRequest() error = %v", err)
+	}
+	if got := req.Header.Get(DefaultRequestIDHeader); got != "" {
+		t.Errorf("%s = %q on a preview, want none", DefaultRequestIDHeader, got)
+	}
+}
+
+func TestRequestIDOnUploadsAndHealthChecks(t *testing.T) {
+	var mu sync.Mutex
+	var ids []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ids = append(ids, r.Header.Get(DefaultRequestIDHeader))
+		mu.Unlock()
+
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/createUploadSession":
+			fmt.Fprintf(w, `{"uploadUrl":%q}`, server.URL+"/session")
+		case "/session":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "upload.bin")
+	if err := os.WriteFile(file, []byte("payload"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		run      func(client *Client) (*http.Response, error)
+		requests int
+	}{
+		{
+			name: "multipart upload",
+			run: func(client *Client) (*http.Response, error) {
+				var out map[string]interface{}
+				return client.DoMultiPartRequest(http.MethodPost, "/upload", map[string][]string{"file": {file}}, nil, nil, nil, "byte", &out)
+			},
+			requests: 1,
+		},
+		{
+			name: "resumable upload",
+			run: func(client *Client) (*http.Response, error) {
+				return client.DoResumableUpload("/createUploadSession", file, 0)
+			},
+			requests: 2,
+		},
+		{
+			name: "health check",
+			run: func(client *Client) (*http.Response, error) {
+				_, err := client.CheckHealth("/healthz")
+				return nil, err
+			},
+			requests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			ids = nil
+			mu.Unlock()
+			client := newTestClient(t, server.URL, nil)
+
+			resp, err := tt.run(client)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(ids) != tt.requests {
+				t.Fatalf("requests = %d, want %d", len(ids), tt.requests)
+			}
+			if _, err := uuid.Parse(ids[0]); err != nil {
+				t.Errorf("%s = %q, want a generated UUID", DefaultRequestIDHeader, ids[0])
+			}
+			for _, id := range ids[1:] {
+				if id != ids[0] {
+					t.Errorf("request ids = %q, want one id for the whole operation", ids)
+				}
+			}
+		})
+	}
+}
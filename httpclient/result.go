@@ -0,0 +1,44 @@
+// httpclient/result.go
+package httpclient
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/deploymenttheory/go-api-http-client/ratehandler"
+)
+
+// Result carries a request's decoded value together with the response metadata callers commonly need.
+type Result struct {
+	// Value is the out parameter passed to DoRequestDetailed, populated from the response.
+	Value interface{}
+	// RateLimit is the server's rate limit state parsed from the final response's headers.
+	RateLimit ratehandler.RateLimitInfo
+	// RequestID is the id the request was sent with, also carried in the RequestIDHeader and its log lines.
+	RequestID string
+	// Elapsed is the total time taken by the call, including any retries and backoff.
+	Elapsed time.Duration
+	// Response is the final HTTP response. The caller is responsible for closing its body.
+	Response *http.Response
+}
+
+// DoRequestDetailed sends a request exactly like DoRequest, returning a Result which also carries the parsed
+// rate limit headers and the elapsed time. A Result is returned whenever a response was received, including
+// alongside an API error, so rate limit state is available for failed calls too.
+func (c *Client) DoRequestDetailed(method, endpoint string, body, out interface{}, opts ...RequestOption) (*Result, error) {
+	startTime := time.Now()
+	resp, err := c.DoRequest(method, endpoint, body, out, opts...)
+	elapsed := time.Since(startTime)
+
+	if resp == nil {
+		return nil, err
+	}
+
+	return &Result{
+		Value:     out,
+		RateLimit: ratehandler.ParseRateLimitInfo(resp, c.Logger()),
+		RequestID: RequestIDFromResponse(resp),
+		Elapsed:   elapsed,
+		Response:  resp,
+	}, err
+}
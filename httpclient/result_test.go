@@ -0,0 +1,45 @@
+// httpclient/result_test.go
+package httpclient
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDoRequestDetailed(t *testing.T) {
+	executor := &MockExecutor{
+		LockedResponseCode: http.StatusOK,
+		ResponseBody:       `{"name":"detailed"}`,
+		ResponseHeaders: http.Header{
+			"Content-Type":          []string{"application/json"},
+			"X-Ratelimit-Limit":     []string{"100"},
+			"X-Ratelimit-Remaining": []string{"42"},
+			"X-Ratelimit-Reset":     []string{"1700000000"},
+		},
+	}
+	client := newTestClient(t, "http://192.0.2.1", func(config *ClientConfig) {
+		config.HTTPExecutor = executor
+	})
+
+	var out struct {
+		Name string `json:"name"`
+	}
+	result, err := client.DoRequestDetailed(http.MethodGet, "/users/1", nil, &out)
+	if err != nil {
+		t.Fatalf("DoRequestDetailed() error = %v", err)
+	}
+	defer result.Response.Body.Close()
+
+	if out.Name != "detailed" || result.Value != &out {
+		t.Errorf("value = %+v, want decoded out", result.Value)
+	}
+	if result.RateLimit.Limit != 100 || result.RateLimit.Remaining != 42 || result.RateLimit.Reset.Unix() != 1700000000 {
+		t.Errorf("rate limit = %+v", result.RateLimit)
+	}
+	if result.RateLimit.RetryAfter != 0 {
+		t.Errorf("retry after = %v, want 0", result.RateLimit.RetryAfter)
+	}
+	if result.Elapsed <= 0 {
+		t.Errorf("elapsed = %v, want > 0", result.Elapsed)
+	}
+}
@@ -0,0 +1,258 @@
+// httpclient/resumable.go
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-http-client/response"
+	"go.uber.org/zap"
+)
+
+// DefaultResumableChunkSize is the chunk size used by DoResumableUpload when none is given. It is a multiple of
+// 320 KiB, as required by Microsoft Graph, and of 256 KiB, as required by Google's resumable uploads.
+const DefaultResumableChunkSize = 10 * 320 * 1024
+
+// uploadSession is the JSON returned when creating a Graph style upload session.
+type uploadSession struct {
+	UploadURL          string   `json:"uploadUrl"`
+	NextExpectedRanges []string `json:"nextExpectedRanges"`
+}
+
+// DoResumableUpload uploads a file through a server driven resumable upload session, such as Microsoft Graph's
+// createUploadSession or Google's resumable uploads. A POST to initEndpoint creates the session, whose URL is read
+// from an "uploadUrl" JSON member or the Location header. The file is then sent in chunkSize ranges with
+// Content-Range headers, following the next byte the server expects after every chunk (202 with
+// nextExpectedRanges, or 308 Resume Incomplete with a Range header). When a chunk fails the client backs off, asks
+// the server how much it has received and resumes from there, giving up after MaxRetryAttempts consecutive
+// failures. A response that does not move the offset forward counts as a failure, and an upload whose every byte
+// is acknowledged without a completing response is reported as an error. Session URLs are pre-authorised, so chunks are sent without the integration's auth headers. Every
+// request of the upload carries the same request id.
+// The response that completes the upload is returned; the caller is responsible for closing its body.
+func (c *Client) DoResumableUpload(initEndpoint, filePath string, chunkSize int64) (*http.Response, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultResumableChunkSize
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file for upload: %w", err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+	fileSize := fileInfo.Size()
+	if fileSize == 0 {
+		return nil, errors.New("cannot upload an empty file through a resumable upload session")
+	}
+
+	ro := c.newRequestOptions(nil)
+	ctx := c.withRequestID(context.Background(), ro)
+	log := c.requestLogger(ctx)
+
+	sessionURL, err := c.createUploadSession(ctx, initEndpoint, ro)
+	if err != nil {
+		return nil, err
+	}
+	// The session URL comes from the server, so it is subject to the host policy like a redirect.
+	if err := c.checkHostPolicy(ctx, sessionURL); err != nil {
+		return nil, err
+	}
+	log.Infow("Created upload session", zap.String("file", filePath), zap.Int64("file_size", fileSize), zap.Int64("chunk_size", chunkSize))
+
+	updateProgress := logUploadProgress(file, fileSize, log)
+
+	var offset int64
+	failures := 0
+	for {
+		if offset >= fileSize {
+			return nil, fmt.Errorf("upload session acknowledged all %d bytes without completing the upload", fileSize)
+		}
+
+		end := offset + chunkSize
+		if end > fileSize {
+			end = fileSize
+		}
+
+		resp, err := c.uploadRange(ctx, sessionURL, file, offset, end-1, fileSize)
+		if err == nil {
+			switch {
+			case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated:
+				updateProgress(fileSize - offset)
+				log.Infow("Resumable upload complete", zap.String("file", filePath), zap.Int("status_code", resp.StatusCode))
+				return resp, nil
+
+			case resp.StatusCode == http.StatusAccepted || resp.StatusCode == http.StatusPermanentRedirect:
+				next := nextUploadOffset(resp, end)
+				resp.Body.Close()
+				if next > offset {
+					updateProgress(next - offset)
+					offset = next
+					failures = 0
+					continue
+				}
+				// A session that does not move forward is retried like a failed chunk, so it cannot loop forever.
+				err = fmt.Errorf("upload session did not advance past byte %d", offset)
+
+			case !response.IsTransientError(resp.StatusCode) && resp.StatusCode != http.StatusTooManyRequests:
+				return resp, c.handleErrorResponse(resp)
+
+			default:
+				resp.Body.Close()
+				err = fmt.Errorf("chunk upload failed with status code: %d", resp.StatusCode)
+			}
+		}
+
+		failures++
+		if failures > c.config.MaxRetryAttempts {
+			return nil, fmt.Errorf("resumable upload failed at byte %d after %d attempts: %w", offset, failures, err)
+		}
+
+		waitDuration := c.retryBackoff(failures)
+		c.warnSampled("Chunk upload failed, resuming from the server's offset", zap.Int64("offset", offset), zap.Int("attempt", failures), zap.Duration("wait", waitDuration), zap.Error(err))
+		if err := c.backoff.Wait(ctx, waitDuration); err != nil {
+			return nil, err
+		}
+
+		if next, err := c.queryUploadOffset(ctx, sessionURL, fileSize); err == nil {
+			if next > offset {
+				updateProgress(next - offset)
+			}
+			offset = next
+		} else {
+			log.Debugw("Failed to query upload session status, resending chunk", zap.Error(err))
+		}
+	}
+}
+
+// createUploadSession starts an upload session at initEndpoint and returns the session URL. A relative Location is
+// resolved against the session request's URL.
+func (c *Client) createUploadSession(ctx context.Context, initEndpoint string, ro *requestOptions) (string, error) {
+	resp, err := c.request(ctx, http.MethodPost, initEndpoint, nil, ro)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload session: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if !c.isSuccessStatus(resp.StatusCode) {
+		return "", c.handleErrorResponse(resp)
+	}
+
+	var session uploadSession
+	if err := json.NewDecoder(resp.Body).Decode(&session); err == nil && session.UploadURL != "" {
+		return session.UploadURL, nil
+	}
+	if location := resolveLocation(resp, resp.Header.Get("Location")); location != "" {
+		return location, nil
+	}
+
+	return "", errors.New("upload session response contained neither an uploadUrl nor a Location header")
+}
+
+// uploadRange sends bytes start to end (inclusive) of file to the session URL.
+func (c *Client) uploadRange(ctx context.Context, sessionURL string, file *os.File, start, end, total int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, sessionURL, io.NewSectionReader(file, start, end-start+1))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = end - start + 1
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, total))
+	c.setUserAgent(req, nil)
+	c.setRequestIDHeader(req)
+
+	c.requestLogger(ctx).Debugw("Uploading chunk", zap.Int64("start", start), zap.Int64("end", end), zap.Int64("total", total))
+	return c.do(req)
+}
+
+// queryUploadOffset asks the server which byte it expects next, first Graph style (GET returning
+// nextExpectedRanges), then Google style (an empty PUT with "Content-Range: bytes */total").
+func (c *Client) queryUploadOffset(ctx context.Context, sessionURL string, total int64) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sessionURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	c.setUserAgent(req, nil)
+	c.setRequestIDHeader(req)
+
+	if resp, err := c.do(req); err == nil {
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			if offset, ok := offsetFromNextExpectedRanges(resp.Body); ok {
+				return offset, nil
+			}
+		}
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodPut, sessionURL, http.NoBody)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", total))
+	c.setUserAgent(req, nil)
+	c.setRequestIDHeader(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		return total, nil
+	case http.StatusPermanentRedirect:
+		return offsetFromRangeHeader(resp.Header.Get("Range")), nil
+	default:
+		return 0, fmt.Errorf("unexpected upload status response: %d", resp.StatusCode)
+	}
+}
+
+// nextUploadOffset returns the next byte the server expects after an incomplete chunk response, falling back
+// to fallback (the end of the chunk just sent) when the response does not say.
+func nextUploadOffset(resp *http.Response, fallback int64) int64 {
+	if resp.StatusCode == http.StatusPermanentRedirect {
+		return offsetFromRangeHeader(resp.Header.Get("Range"))
+	}
+
+	if offset, ok := offsetFromNextExpectedRanges(resp.Body); ok {
+		return offset
+	}
+	return fallback
+}
+
+// offsetFromNextExpectedRanges reads the start of the first range in a Graph nextExpectedRanges document.
+func offsetFromNextExpectedRanges(body io.Reader) (int64, bool) {
+	var session uploadSession
+	if err := json.NewDecoder(body).Decode(&session); err != nil || len(session.NextExpectedRanges) == 0 {
+		return 0, false
+	}
+
+	start, _, _ := strings.Cut(session.NextExpectedRanges[0], "-")
+	offset, err := strconv.ParseInt(start, 10, 64)
+	return offset, err == nil
+}
+
+// offsetFromRangeHeader converts a 308 Resume Incomplete Range header ("bytes=0-1048575") to the next offset.
+// A missing header means the server has received nothing.
+func offsetFromRangeHeader(rangeHeader string) int64 {
+	_, end, ok := strings.Cut(strings.TrimPrefix(rangeHeader, "bytes="), "-")
+	if !ok {
+		return 0
+	}
+
+	last, err := strconv.ParseInt(end, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return last + 1
+}
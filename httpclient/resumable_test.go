@@ -0,0 +1,282 @@
+// httpclient/resumable_test.go
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDoResumableUpload(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+
+	var (
+		mu       sync.Mutex
+		received []byte
+		puts     int
+	)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/createUploadSession":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"uploadUrl":%q}`, server.URL+"/session")
+
+		case r.Method == http.MethodGet && r.URL.Path == "/session":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"nextExpectedRanges":["%d-"]}`, len(received))
+
+		case r.Method == http.MethodPut && r.URL.Path == "/session":
+			if r.Header.Get("Authorization") != "" {
+				t.Errorf("chunk sent with Authorization header; session URLs are pre-authorised")
+			}
+			var start, end, total int
+			if _, err := fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &total); err != nil {
+				t.Errorf("bad Content-Range %q: %v", r.Header.Get("Content-Range"), err)
+			}
+			body, _ := io.ReadAll(r.Body)
+			puts++
+
+			// The second chunk reaches the server but the response is lost.
+			if puts == 2 {
+				received = append(received[:start], body...)
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if start != len(received) {
+				t.Errorf("chunk starts at %d, server expects %d", start, len(received))
+			}
+			received = append(received[:start], body...)
+
+			if len(received) == total {
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id":"file"}`))
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprintf(w, `{"nextExpectedRanges":["%d-"]}`, len(received))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "upload.bin")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var intercepted []string
+	client := newTestClient(t, server.URL, func(c *ClientConfig) {
+		c.MaxRetryAttempts = 3
+		c.Interceptors = []Interceptor{func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+			mu.Lock()
+			intercepted = append(intercepted, req.Method)
+			mu.Unlock()
+			return next(req)
+		}}
+	})
+
+	resp, err := client.DoResumableUpload("/createUploadSession", path, 300)
+	if err != nil {
+		t.Fatalf("DoResumableUpload() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	if !bytes.Equal(received, content) {
+		t.Errorf("server received %d bytes that differ from the file", len(received))
+	}
+	// 4 chunks of up to 300 bytes, with the lost second response resumed from the server's offset rather than resent.
+	if puts != 4 {
+		t.Errorf("chunk PUTs = %d, want 4", puts)
+	}
+	// The session status query after the lost response goes through the interceptors like every other request.
+	if want := "[POST PUT PUT GET PUT PUT]"; fmt.Sprint(intercepted) != want {
+		t.Errorf("intercepted requests = %v, want %s", intercepted, want)
+	}
+}
+
+func TestDoResumableUpload_SessionCreation(t *testing.T) {
+	tests := []struct {
+		name         string
+		createStatus int
+		createBody   func(serverURL string) string
+		location     string
+		successCodes []StatusCodeRange
+		wantErr      bool
+	}{
+		{
+			name:         "status outside SuccessStatusCodes",
+			createStatus: http.StatusCreated,
+			createBody:   func(serverURL string) string { return fmt.Sprintf(`{"uploadUrl":%q}`, serverURL+"/session") },
+			successCodes: []StatusCodeRange{{Min: 200, Max: 200}},
+			wantErr:      true,
+		},
+		{
+			name:         "relative Location header",
+			createStatus: http.StatusOK,
+			location:     "/session",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var puts atomic.Int32
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/createUploadSession":
+					if tt.location != "" {
+						w.Header().Set("Location", tt.location)
+					}
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(tt.createStatus)
+					if tt.createBody != nil {
+						w.Write([]byte(tt.createBody(server.URL)))
+					}
+				case r.Method == http.MethodPut && r.URL.Path == "/session":
+					puts.Add(1)
+					io.Copy(io.Discard, r.Body)
+					w.WriteHeader(http.StatusCreated)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			path := filepath.Join(t.TempDir(), "upload.bin")
+			if err := os.WriteFile(path, []byte("payload"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			client := newTestClient(t, server.URL, func(c *ClientConfig) {
+				c.SuccessStatusCodes = tt.successCodes
+			})
+
+			resp, err := client.DoResumableUpload("/createUploadSession", path, 0)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("DoResumableUpload() error = nil, want the session creation rejected")
+				}
+				if puts.Load() != 0 {
+					t.Errorf("chunk PUTs = %d, want none", puts.Load())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DoResumableUpload() error = %v", err)
+			}
+			resp.Body.Close()
+			if puts.Load() != 1 {
+				t.Errorf("chunk PUTs = %d, want 1", puts.Load())
+			}
+		})
+	}
+}
+
+func TestDoResumableUpload_SessionNotAdvancing(t *testing.T) {
+	tests := []struct {
+		name     string
+		respond  func(w http.ResponseWriter, start, end int)
+		maxPuts  int32
+		wantPuts int32
+	}{
+		{
+			name: "308 that never moves the offset",
+			respond: func(w http.ResponseWriter, _, _ int) {
+				w.Header().Set("Range", "bytes=0-99")
+				w.WriteHeader(http.StatusPermanentRedirect)
+			},
+			maxPuts: 10,
+		},
+		{
+			name: "202 acknowledging every byte without completing",
+			respond: func(w http.ResponseWriter, _, end int) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusAccepted)
+				fmt.Fprintf(w, `{"nextExpectedRanges":["%d-"]}`, end+1)
+			},
+			wantPuts: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var puts atomic.Int32
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/createUploadSession":
+					w.Header().Set("Content-Type", "application/json")
+					fmt.Fprintf(w, `{"uploadUrl":%q}`, server.URL+"/session")
+				case r.Method == http.MethodPut && r.URL.Path == "/session":
+					io.Copy(io.Discard, r.Body)
+					contentRange := r.Header.Get("Content-Range")
+					if contentRange == "bytes */300" {
+						tt.respond(w, 0, -1)
+						return
+					}
+					puts.Add(1)
+					var start, end, total int
+					if _, err := fmt.Sscanf(contentRange, "bytes %d-%d/%d", &start, &end, &total); err != nil || end < start || end >= total {
+						t.Errorf("invalid Content-Range %q", contentRange)
+					}
+					tt.respond(w, start, end)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			path := filepath.Join(t.TempDir(), "upload.bin")
+			if err := os.WriteFile(path, bytes.Repeat([]byte("x"), 300), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			client := newTestClient(t, server.URL, func(c *ClientConfig) {
+				c.MaxRetryAttempts = 2
+			})
+
+			if _, err := client.DoResumableUpload("/createUploadSession", path, 100); err == nil {
+				t.Fatal("DoResumableUpload() error = nil, want the stalled session reported")
+			}
+			if tt.maxPuts > 0 && puts.Load() > tt.maxPuts {
+				t.Errorf("chunk PUTs = %d, want at most %d", puts.Load(), tt.maxPuts)
+			}
+			if tt.wantPuts > 0 && puts.Load() != tt.wantPuts {
+				t.Errorf("chunk PUTs = %d, want %d", puts.Load(), tt.wantPuts)
+			}
+		})
+	}
+}
+
+func TestOffsetFromRangeHeader(t *testing.T) {
+	tests := []struct {
+		header string
+		want   int64
+	}{
+		{"bytes=0-1048575", 1048576},
+		{"0-99", 100},
+		{"", 0},
+		{"bytes=garbage", 0},
+	}
+
+	for _, tt := range tests {
+		if got := offsetFromRangeHeader(tt.header); got != tt.want {
+			t.Errorf("offsetFromRangeHeader(%q) = %d, want %d", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// httpclient/signer.go
+package httpclient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// RequestSigner signs requests for APIs which authenticate each call with a signature, such as an HMAC or AWS SigV4,
+// computed over the method, URL, headers and payload. Sign typically sets an Authorization or signature header.
+//
+// Sign runs as the very last step before a request goes on the wire: after the body is marshalled (and compressed),
+// the integration has authenticated the request, the OnRequest hook has run and every Interceptor has had its turn.
+// It is called again for every retry, so time-stamped signatures stay fresh. Requests answered from the response
+// cache are not signed.
+//
+// body holds the exact bytes that will be sent, and is empty for requests without a body. It is nil when the body is
+// streamed and cannot be read twice, as for multipart and resumable uploads and raw bodies other than
+// *bytes.Buffer, *bytes.Reader and *strings.Reader; signers usually fall back to an unsigned-payload scheme then.
+type RequestSigner interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+// RequestSignerFunc adapts an ordinary function to a RequestSigner.
+type RequestSignerFunc func(req *http.Request, body []byte) error
+
+// Sign calls f(req, body).
+func (f RequestSignerFunc) Sign(req *http.Request, body []byte) error {
+	return f(req, body)
+}
+
+// signAndSend signs req with the configured RequestSigner and sends it with the HTTP executor.
+func (c *Client) signAndSend(req *http.Request) (*http.Response, error) {
+	body, err := signingBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.config.RequestSigner.Sign(req, body); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	return c.http.Do(req)
+}
+
+// signingBody returns a copy of the body req will send, without consuming it. See RequestSigner for when it is
+// empty or nil.
+func signingBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return []byte{}, nil
+	}
+	if req.GetBody == nil {
+		return nil, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy request body for signing: %w", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy request body for signing: %w", err)
+	}
+	return data, nil
+}
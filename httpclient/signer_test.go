@@ -0,0 +1,134 @@
+// httpclient/signer_test.go
+package httpclient
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// hmacSigner signs the method, path, a header added by an interceptor, and the payload.
+func hmacSigner(key []byte) RequestSignerFunc {
+	return func(req *http.Request, body []byte) error {
+		req.Header.Set("X-Signature", hmacSignature(key, req.Method, req.URL.Path, req.Header.Get("X-Tenant"), body))
+		return nil
+	}
+}
+
+func hmacSignature(key []byte, method, path, tenant string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(method + "\n" + path + "\n" + tenant + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestDoRequest_RequestSigner(t *testing.T) {
+	key := []byte("secret")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		want := hmacSignature(key, r.Method, r.URL.Path, r.Header.Get("X-Tenant"), body)
+		if r.Header.Get("X-Signature") != want {
+			http.Error(w, `{"error":"bad signature"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	tenant := func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+		req.Header.Set("X-Tenant", "acme")
+		return next(req)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		body   interface{}
+		config func(*ClientConfig)
+	}{
+		{name: "json body", method: http.MethodPost, body: map[string]string{"name": "widget"}},
+		{name: "no body", method: http.MethodGet},
+		{name: "compressed body", method: http.MethodPut, body: map[string]string{"name": strings.Repeat("w", 4096)}, config: func(config *ClientConfig) {
+			config.CompressRequestBody = true
+			config.CompressRequestBodyThreshold = 1
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, server.URL, func(config *ClientConfig) {
+				config.RequestSigner = hmacSigner(key)
+				config.Interceptors = []Interceptor{tenant}
+				if tt.config != nil {
+					tt.config(config)
+				}
+			})
+
+			var out map[string]interface{}
+			if _, err := client.DoRequest(tt.method, "/widgets", tt.body, &out); err != nil {
+				t.Fatalf("DoRequest() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestDoRequest_RequestSignerError(t *testing.T) {
+	var sent bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = true
+	}))
+	defer server.Close()
+
+	signErr := errors.New("key unavailable")
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.RequestSigner = RequestSignerFunc(func(req *http.Request, body []byte) error {
+			return signErr
+		})
+	})
+
+	if _, err := client.DoRequest(http.MethodPost, "/widgets", nil, nil); !errors.Is(err, signErr) {
+		t.Fatalf("DoRequest() error = %v, want %v", err, signErr)
+	}
+	if sent {
+		t.Error("request was sent despite the signing failure")
+	}
+}
+
+func TestSigningBody(t *testing.T) {
+	withBody, _ := http.NewRequest(http.MethodPost, "https://example.com", strings.NewReader("payload"))
+	withoutBody, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	streamed, _ := http.NewRequest(http.MethodPost, "https://example.com", io.NopCloser(strings.NewReader("stream")))
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		want []byte
+	}{
+		{name: "buffered", req: withBody, want: []byte("payload")},
+		{name: "no body", req: withoutBody, want: []byte{}},
+		{name: "streamed", req: streamed, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := signingBody(tt.req)
+			if err != nil {
+				t.Fatalf("signingBody() error = %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || string(got) != string(tt.want) {
+				t.Errorf("signingBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if data, _ := io.ReadAll(withBody.Body); string(data) != "payload" {
+		t.Errorf("signingBody consumed the request body, %q left", data)
+	}
+}
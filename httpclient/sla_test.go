@@ -0,0 +1,64 @@
+// httpclient/sla_test.go
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOnSLABreach(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	type breach struct {
+		endpoint            string
+		duration, threshold time.Duration
+	}
+	breaches := make(chan breach, 2)
+
+	client := newTestClient(t, server.URL, func(config *ClientConfig) {
+		config.SLAThreshold = time.Second
+		config.SLAEndpointThresholds = map[string]time.Duration{"/slow": 10 * time.Millisecond}
+		config.OnSLABreach = func(endpoint string, duration, threshold time.Duration) {
+			breaches <- breach{endpoint, duration, threshold}
+		}
+	})
+
+	var out map[string]interface{}
+	if _, err := client.DoRequest(http.MethodGet, "/slow", nil, &out); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+
+	select {
+	case got := <-breaches:
+		if got.endpoint != "/slow" {
+			t.Errorf("endpoint = %q, want /slow", got.endpoint)
+		}
+		if got.threshold != 10*time.Millisecond {
+			t.Errorf("threshold = %v, want 10ms", got.threshold)
+		}
+		if got.duration < 50*time.Millisecond {
+			t.Errorf("duration = %v, want at least 50ms", got.duration)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnSLABreach was not called for a slow request")
+	}
+
+	if _, err := client.DoRequest(http.MethodGet, "/fast", nil, &out); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+
+	select {
+	case got := <-breaches:
+		t.Errorf("OnSLABreach called for a fast request: %+v", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
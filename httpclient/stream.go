@@ -0,0 +1,22 @@
+// httpclient/stream.go
+package httpclient
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// DoStreamJSON sends a request like DoRequest but, instead of unmarshalling a single document, streams the
+// response body as a sequence of JSON documents (e.g. application/x-ndjson), calling onRecord with each record
+// as it is read. Decoding stops at the first error returned by onRecord, which is then returned to the caller.
+// Records are only streamed from a successful response, so onRecord is never called for a request that is retried.
+func (c *Client) DoStreamJSON(method, endpoint string, body interface{}, onRecord func(json.RawMessage) error, opts ...RequestOption) (*http.Response, error) {
+	return c.DoRequest(method, endpoint, body, nil, append(opts, withRecordHandler(onRecord))...)
+}
+
+// withRecordHandler streams the response body to onRecord instead of unmarshalling it into out.
+func withRecordHandler(onRecord func(json.RawMessage) error) RequestOption {
+	return func(ro *requestOptions) {
+		ro.onRecord = onRecord
+	}
+}
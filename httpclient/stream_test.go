@@ -0,0 +1,39 @@
+// httpclient/stream_test.go
+package httpclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDoStreamJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Write([]byte("{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n"))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, nil)
+
+	var ids []int
+	resp, err := client.DoStreamJSON(http.MethodGet, "/events", nil, func(record json.RawMessage) error {
+		var event struct {
+			ID int `json:"id"`
+		}
+		if err := json.Unmarshal(record, &event); err != nil {
+			return err
+		}
+		ids = append(ids, event.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DoStreamJSON() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Errorf("ids = %v, want [1 2 3]", ids)
+	}
+}
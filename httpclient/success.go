@@ -0,0 +1,124 @@
+// httpclient/success.go
+package httpclient
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-http-client/response"
+	"go.uber.org/zap"
+)
+
+// ErrNotModified is returned alongside the response when the server replies 304 Not Modified, typically to a
+// conditional request carrying If-None-Match or If-Modified-Since. It is not a failure as such: the resource is
+// unchanged, so out is left untouched and callers should keep the value they already hold.
+var ErrNotModified = errors.New("resource not modified")
+
+// defaultSuccessStatusCodes is the success classification used when ClientConfig.SuccessStatusCodes is empty:
+// every 2xx and 3xx status.
+var defaultSuccessStatusCodes = []StatusCodeRange{{Min: http.StatusOK, Max: http.StatusBadRequest - 1}}
+
+// StatusCodeRange is an inclusive range of HTTP status codes. A single code has Min equal to Max.
+type StatusCodeRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// Contains reports whether code lies within the range.
+func (r StatusCodeRange) Contains(code int) bool {
+	return code >= r.Min && code <= r.Max
+}
+
+// validate checks the range is ordered and made of valid status codes.
+func (r StatusCodeRange) validate() error {
+	if r.Min < 100 || r.Max > 599 || r.Min > r.Max {
+		return fmt.Errorf("invalid success status code range %d-%d", r.Min, r.Max)
+	}
+	return nil
+}
+
+// isSuccessStatus classifies a response status as success or failure for every request flow. Anything outside
+// the configured SuccessStatusCodes is handed to error handling and, where the flow retries, retry classification.
+func (c *Client) isSuccessStatus(code int) bool {
+	ranges := c.config.SuccessStatusCodes
+	if len(ranges) == 0 {
+		ranges = defaultSuccessStatusCodes
+	}
+
+	for _, r := range ranges {
+		if r.Contains(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleSuccessResponse applies any per-request response transformations and hands the response to the
+// response package to be unmarshalled into out.
+func (c *Client) handleSuccessResponse(resp *http.Response, out interface{}, ro *requestOptions) error {
+	if resp.StatusCode == http.StatusNotModified {
+		c.Logger().Debugw("Resource not modified, leaving output untouched", zap.String("url", resp.Request.URL.String()))
+		return ErrNotModified
+	}
+
+	if err := checkContentType(resp, ro.expectedContentType); err != nil {
+		return err
+	}
+
+	if ro.onRecord != nil {
+		return response.DecodeNDJSON(resp.Body, ro.onRecord)
+	}
+
+	if handled, err := c.handleMultiStatus(resp, out); handled || err != nil {
+		return err
+	}
+
+	return c.decodeSuccessBody(resp, out, ro)
+}
+
+// decodeSuccessBody unwraps any response envelope and unmarshals the body into out.
+func (c *Client) decodeSuccessBody(resp *http.Response, out interface{}, ro *requestOptions) error {
+	if ro.envelopeDecoder != nil && out != nil {
+		if err := c.decodeEnvelope(resp, out, ro.envelopeDecoder); err != nil {
+			return err
+		}
+	}
+
+	return response.HandleAPISuccessResponse(resp, out, c.Logger())
+}
+
+// decodeEnvelope replaces a JSON response body with the flattened document produced by decoder.
+// Non JSON responses are left untouched.
+func (c *Client) decodeEnvelope(resp *http.Response, out interface{}, decoder response.EnvelopeDecoder) error {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasSuffix(mediaType, "json") {
+		return nil
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read enveloped response body: %w", err)
+	}
+
+	if len(bodyBytes) == 0 {
+		resp.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		return nil
+	}
+
+	flattened, err := decoder.Decode(bodyBytes, out)
+	if err != nil {
+		c.Logger().Errorw("Failed to decode response envelope", zap.String("content_type", mediaType), zap.Error(err))
+		return err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(flattened))
+	resp.ContentLength = int64(len(flattened))
+
+	return nil
+}
@@ -0,0 +1,115 @@
+// httpclient/success_test.go
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-http-client/response"
+)
+
+func TestIsSuccessStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		ranges []StatusCodeRange
+		code   int
+		want   bool
+	}{
+		{name: "default 200", code: http.StatusOK, want: true},
+		{name: "default 304", code: http.StatusNotModified, want: true},
+		{name: "default 404", code: http.StatusNotFound, want: false},
+		{name: "default 101", code: http.StatusSwitchingProtocols, want: false},
+		{name: "2xx only rejects redirect", ranges: []StatusCodeRange{{Min: 200, Max: 299}}, code: http.StatusFound, want: false},
+		{name: "2xx only accepts 204", ranges: []StatusCodeRange{{Min: 200, Max: 299}}, code: http.StatusNoContent, want: true},
+		{name: "excluded 207", ranges: []StatusCodeRange{{Min: 200, Max: 206}, {Min: 208, Max: 299}}, code: http.StatusMultiStatus, want: false},
+		{name: "single code", ranges: []StatusCodeRange{{Min: 200, Max: 200}}, code: http.StatusOK, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{config: &ClientConfig{SuccessStatusCodes: tt.ranges}}
+			if got := client.isSuccessStatus(tt.code); got != tt.want {
+				t.Errorf("isSuccessStatus(%d) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoRequestSuccessStatusCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(`{"results":[]}`))
+	}))
+	defer server.Close()
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		t.Run(method, func(t *testing.T) {
+			defaults := newTestClient(t, server.URL, func(config *ClientConfig) {
+				config.RetryEligiableRequests = true
+				config.TotalRetryDuration = 10 * time.Second
+			})
+			var out map[string]interface{}
+			if _, err := defaults.DoRequest(method, "/bulk", nil, &out); err != nil {
+				t.Fatalf("DoRequest() with default success codes error = %v", err)
+			}
+
+			strict := newTestClient(t, server.URL, func(config *ClientConfig) {
+				config.RetryEligiableRequests = true
+				config.TotalRetryDuration = 10 * time.Second
+				config.SuccessStatusCodes = []StatusCodeRange{{Min: 200, Max: 206}, {Min: 208, Max: 299}}
+			})
+			if _, err := strict.DoRequest(method, "/bulk", nil, &out); err == nil {
+				t.Fatal("DoRequest() accepted 207 excluded from SuccessStatusCodes")
+			}
+		})
+	}
+}
+
+func TestValidateClientConfigSuccessStatusCodes(t *testing.T) {
+	for _, r := range []StatusCodeRange{{Min: 299, Max: 200}, {Min: 0, Max: 299}, {Min: 200, Max: 600}} {
+		config := ClientConfig{
+			Integration:        &MockIntegration{BaseURL: "https://example.com"},
+			SuccessStatusCodes: []StatusCodeRange{r},
+		}
+		if err := config.validateClientConfig(); err == nil {
+			t.Errorf("validateClientConfig() accepted range %+v", r)
+		}
+	}
+}
+
+func TestDoRequestEmptyBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", "0")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		opts []RequestOption
+	}{
+		{name: "plain"},
+		{name: "with envelope decoder", opts: []RequestOption{WithEnvelopeDecoder(response.HALDecoder{})}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, server.URL, nil)
+
+			out := struct {
+				Name string `json:"name"`
+			}{Name: "unchanged"}
+			resp, err := client.DoRequest(http.MethodGet, "/widgets/1", nil, &out, tt.opts...)
+			if err != nil {
+				t.Fatalf("DoRequest() error = %v", err)
+			}
+			if resp.StatusCode != http.StatusOK || out.Name != "unchanged" {
+				t.Errorf("status = %d, out.Name = %q; want 200 with out untouched", resp.StatusCode, out.Name)
+			}
+		})
+	}
+}
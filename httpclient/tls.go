@@ -0,0 +1,170 @@
+// httpclient/tls.go
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+)
+
+// TLSConfig holds the TLS settings applied to the transport built by ClientConfig.Build.
+// It supports client certificates for mutual TLS, either loaded from PEM files or supplied in memory,
+// an optional custom CA bundle used to verify the server, and hardening of the protocol version floor
+// and cipher suites. A nil TLSConfig still enforces DefaultMinTLSVersion.
+type TLSConfig struct {
+	// CertFile and KeyFile are paths to a PEM encoded client certificate and private key. Both must be set together.
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+
+	// Certificates are client certificates supplied in memory. They are used in addition to CertFile/KeyFile.
+	Certificates []tls.Certificate `json:"-"`
+
+	// CAFile is the path to a PEM encoded CA bundle used to verify the server certificate.
+	CAFile string `json:"ca_file"`
+
+	// CAPEM is a PEM encoded CA bundle supplied in memory. It is used in addition to CAFile.
+	CAPEM []byte `json:"-"`
+
+	// InsecureSkipVerify disables server certificate verification. Only ever use this against test environments.
+	InsecureSkipVerify bool `json:"insecure_skip_verify"`
+
+	// MinTLSVersion is the lowest TLS version the client will negotiate: "1.0", "1.1", "1.2" or "1.3".
+	// Defaults to DefaultMinTLSVersion.
+	MinTLSVersion string `json:"min_tls_version"`
+
+	// CipherSuites optionally restricts the TLS 1.0-1.2 cipher suites offered, by their IANA names
+	// (e.g. "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"). TLS 1.3 suites are not configurable in Go.
+	CipherSuites []string `json:"cipher_suites"`
+}
+
+// DefaultMinTLSVersion is the TLS floor applied when TLSConfig.MinTLSVersion is not set.
+const DefaultMinTLSVersion = "1.2"
+
+// tlsVersions maps the supported MinTLSVersion values to their crypto/tls constants.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// minVersion resolves MinTLSVersion to its crypto/tls constant, applying the default when unset.
+func (t *TLSConfig) minVersion() (uint16, error) {
+	version := t.MinTLSVersion
+	if version == "" {
+		version = DefaultMinTLSVersion
+	}
+
+	v, ok := tlsVersions[version]
+	if !ok {
+		return 0, fmt.Errorf("unsupported tls min_tls_version: %q, expected one of 1.0, 1.1, 1.2, 1.3", t.MinTLSVersion)
+	}
+
+	return v, nil
+}
+
+// cipherSuiteIDs resolves CipherSuites names to their crypto/tls IDs.
+func (t *TLSConfig) cipherSuiteIDs() ([]uint16, error) {
+	if len(t.CipherSuites) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		known[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(t.CipherSuites))
+	for _, name := range t.CipherSuites {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown tls cipher suite: %q", name)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// validate checks the TLS configuration for incomplete settings.
+func (t *TLSConfig) validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return errors.New("tls cert_file and key_file must be supplied together")
+	}
+
+	if _, err := t.minVersion(); err != nil {
+		return err
+	}
+
+	if _, err := t.cipherSuiteIDs(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// build constructs a *tls.Config from the TLS configuration, loading any certificates and CA bundles it references.
+func (t *TLSConfig) build(sugar *zap.SugaredLogger) (*tls.Config, error) {
+	minVersion, err := t.minVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	cipherSuites, err := t.cipherSuiteIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:   minVersion,
+		CipherSuites: cipherSuites,
+	}
+
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, cert)
+	}
+	tlsConfig.Certificates = append(tlsConfig.Certificates, t.Certificates...)
+
+	if t.CAFile != "" || len(t.CAPEM) > 0 {
+		pool := x509.NewCertPool()
+
+		if t.CAFile != "" {
+			caBytes, err := os.ReadFile(t.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(caBytes) {
+				return nil, fmt.Errorf("no valid certificates found in CA bundle: %s", t.CAFile)
+			}
+		}
+
+		if len(t.CAPEM) > 0 && !pool.AppendCertsFromPEM(t.CAPEM) {
+			return nil, errors.New("no valid certificates found in in-memory CA bundle")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if t.InsecureSkipVerify {
+		sugar.Warn("TLS certificate verification is DISABLED (InsecureSkipVerify). This must never be used in production")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	if len(tlsConfig.Certificates) > 0 {
+		sugar.Infow("Mutual TLS enabled", zap.Int("client_certificates", len(tlsConfig.Certificates)))
+	}
+
+	sugar.Infow("TLS policy configured",
+		zap.String("min_tls_version", tls.VersionName(minVersion)),
+		zap.Strings("cipher_suites", t.CipherSuites))
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,138 @@
+// httpclient/tls_test.go
+package httpclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// newTestClientCertificate generates a self signed client certificate for mutual TLS tests.
+func newTestClientCertificate(t *testing.T) (tls.Certificate, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "go-api-http-client test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, leaf
+}
+
+func TestBuildTransport_MutualTLS(t *testing.T) {
+	clientCert, clientLeaf := newTestClientCertificate(t)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientLeaf)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	serverCAPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	tests := []struct {
+		name    string
+		tls     *TLSConfig
+		wantErr bool
+	}{
+		{
+			name:    "client certificate presented",
+			tls:     &TLSConfig{Certificates: []tls.Certificate{clientCert}, CAPEM: serverCAPEM},
+			wantErr: false,
+		},
+		{
+			name:    "no client certificate",
+			tls:     &TLSConfig{CAPEM: serverCAPEM},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ClientConfig{Sugar: zap.NewNop().Sugar(), TLS: tt.tls}
+			transport, err := config.buildTransport(nil, nil)
+			if err != nil {
+				t.Fatalf("buildTransport() error = %v", err)
+			}
+			defer transport.CloseIdleConnections()
+
+			resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				resp.Body.Close()
+			}
+		})
+	}
+}
+
+func TestTLSConfig_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tls     *TLSConfig
+		wantErr bool
+	}{
+		{name: "empty config", tls: &TLSConfig{}, wantErr: false},
+		{name: "cert without key", tls: &TLSConfig{CertFile: "client.pem"}, wantErr: true},
+		{name: "supported min version", tls: &TLSConfig{MinTLSVersion: "1.3"}, wantErr: false},
+		{name: "unsupported min version", tls: &TLSConfig{MinTLSVersion: "1.4"}, wantErr: true},
+		{name: "known cipher suite", tls: &TLSConfig{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}}, wantErr: false},
+		{name: "unknown cipher suite", tls: &TLSConfig{CipherSuites: []string{"TLS_MADE_UP"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.tls.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildTransport_DefaultMinTLSVersion(t *testing.T) {
+	config := &ClientConfig{Sugar: zap.NewNop().Sugar()}
+	transport, err := config.buildTransport(nil, nil)
+	if err != nil {
+		t.Fatalf("buildTransport() error = %v", err)
+	}
+
+	if got := transport.TLSClientConfig.MinVersion; got != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %s, want %s", tls.VersionName(got), tls.VersionName(tls.VersionTLS12))
+	}
+}
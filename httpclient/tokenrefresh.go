@@ -0,0 +1,78 @@
+// httpclient/tokenrefresh.go
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// minTokenRefreshInterval is the shortest time the background refresher sleeps between checks.
+	minTokenRefreshInterval = time.Second
+
+	// tokenRefreshRetryInterval is how long the background refresher waits after a failed refresh.
+	tokenRefreshRetryInterval = 5 * time.Second
+)
+
+// prepRequestAuth applies the params and auth of the TokenSource scoped to req, by default the integration. When the
+// background refresher is running the call is serialised with it through tokenLock so a request never races a refresh.
+func (c *Client) prepRequestAuth(req *http.Request) error {
+	if c.config.ProactiveTokenRefresh {
+		c.tokenLock.Lock()
+		defer c.tokenLock.Unlock()
+	}
+
+	return c.tokenSourceFor(req).PrepRequestParamsAndAuth(req)
+}
+
+// startTokenRefresher runs the background token refresher until the client is closed.
+func (c *Client) startTokenRefresher() {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		timer := time.NewTimer(c.nextTokenRefresh())
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-c.done:
+				return
+			case <-timer.C:
+				wait := c.nextTokenRefresh()
+				if err := c.refreshToken(); err != nil {
+					c.Logger().Warnw("Background token refresh failed", zap.Error(err))
+					wait = tokenRefreshRetryInterval
+				}
+				timer.Reset(wait)
+			}
+		}
+	}()
+}
+
+// refreshToken asks the integration and any scoped TokenSources to refresh their tokens if it is within TokenRefreshBufferPeriod of expiring.
+func (c *Client) refreshToken() error {
+	c.tokenLock.Lock()
+	defer c.tokenLock.Unlock()
+
+	c.Logger().Debug("Checking token ahead of expiry")
+	return errors.Join(c.defaultTokenSource().CheckRefreshToken(), c.checkScopedTokens())
+}
+
+// nextTokenRefresh returns how long to sleep before the next refresh check. Integrations implementing
+// TokenExpiryReporter are woken TokenRefreshBufferPeriod before expiry, others are polled every half buffer period.
+func (c *Client) nextTokenRefresh() time.Duration {
+	wait := c.config.TokenRefreshBufferPeriod / 2
+	if reporter, ok := c.defaultTokenSource().(TokenExpiryReporter); ok {
+		wait = time.Until(reporter.TokenExpiry().Add(-c.config.TokenRefreshBufferPeriod))
+	}
+
+	if wait < minTokenRefreshInterval {
+		return minTokenRefreshInterval
+	}
+
+	return wait
+}
@@ -0,0 +1,64 @@
+// httpclient/tokenrefresh_test.go
+package httpclient
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// expiringIntegration reports a token expiry and extends it by an hour on every refresh.
+type expiringIntegration struct {
+	MockIntegration
+	expiry    time.Time
+	refreshes int
+	sync.Mutex
+}
+
+func (i *expiringIntegration) TokenExpiry() time.Time {
+	i.Lock()
+	defer i.Unlock()
+	return i.expiry
+}
+
+func (i *expiringIntegration) CheckRefreshToken() error {
+	i.Lock()
+	defer i.Unlock()
+	i.refreshes++
+	i.expiry = time.Now().Add(time.Hour)
+	return nil
+}
+
+func (i *expiringIntegration) refreshCount() int {
+	i.Lock()
+	defer i.Unlock()
+	return i.refreshes
+}
+
+func TestProactiveTokenRefresh(t *testing.T) {
+	integration := &expiringIntegration{expiry: time.Now().Add(time.Minute)}
+	client := newTestClient(t, "", func(config *ClientConfig) {
+		config.Integration = integration
+		config.TokenRefreshBufferPeriod = 2 * time.Minute
+		config.ProactiveTokenRefresh = true
+	})
+
+	deadline := time.Now().Add(3 * time.Second)
+	for integration.refreshCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if integration.refreshCount() != 1 {
+		t.Fatalf("refreshes = %d, want 1", integration.refreshCount())
+	}
+
+	if next := client.nextTokenRefresh(); next < 55*time.Minute {
+		t.Errorf("next refresh in %v, want about 58m", next)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+// httpclient/tokensource.go
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// scopedTokenSource is a TokenSource registered for requests under prefix.
+type scopedTokenSource struct {
+	prefix string
+	source TokenSource
+}
+
+// newScopedTokenSources orders the configured sources longest prefix first, so the most specific match wins.
+func newScopedTokenSources(sources map[string]TokenSource) []scopedTokenSource {
+	scoped := make([]scopedTokenSource, 0, len(sources))
+	for prefix, source := range sources {
+		if source != nil {
+			scoped = append(scoped, scopedTokenSource{prefix: prefix, source: source})
+		}
+	}
+
+	sort.Slice(scoped, func(i, j int) bool {
+		if len(scoped[i].prefix) != len(scoped[j].prefix) {
+			return len(scoped[i].prefix) > len(scoped[j].prefix)
+		}
+		return scoped[i].prefix < scoped[j].prefix
+	})
+
+	return scoped
+}
+
+// defaultTokenSource returns the TokenSource for requests without a scoped match: the OAuth2 client credentials
+// source when configured, otherwise the Integration.
+func (c *Client) defaultTokenSource() TokenSource {
+	if c.oauth2Auth != nil {
+		return c.oauth2Auth
+	}
+	return *c.Integration
+}
+
+// tokenSourceFor returns the TokenSource which authenticates req: the scoped source with the longest matching
+// prefix, or the default TokenSource when none matches.
+func (c *Client) tokenSourceFor(req *http.Request) TokenSource {
+	for _, scoped := range c.scopedAuth {
+		target := req.URL.Path
+		if strings.Contains(scoped.prefix, "://") {
+			target = req.URL.String()
+		}
+		if strings.HasPrefix(target, scoped.prefix) {
+			return scoped.source
+		}
+	}
+
+	return c.defaultTokenSource()
+}
+
+// checkScopedTokens refreshes every scoped TokenSource, returning their combined errors.
+func (c *Client) checkScopedTokens() error {
+	var errs []error
+	for _, scoped := range c.scopedAuth {
+		if err := scoped.source.CheckRefreshToken(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
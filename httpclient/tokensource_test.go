@@ -0,0 +1,68 @@
+// httpclient/tokensource_test.go
+package httpclient
+
+import (
+	"net/http"
+	"testing"
+)
+
+// staticTokenSource authenticates with a fixed bearer token.
+type staticTokenSource struct {
+	token     string
+	refreshed int
+}
+
+func (s *staticTokenSource) CheckRefreshToken() error {
+	s.refreshed++
+	return nil
+}
+
+func (s *staticTokenSource) PrepRequestParamsAndAuth(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	return nil
+}
+
+func TestScopedTokenSources(t *testing.T) {
+	security := &staticTokenSource{token: "security"}
+	alerts := &staticTokenSource{token: "alerts"}
+	vault := &staticTokenSource{token: "vault"}
+
+	client := newTestClient(t, "https://graph.example.com", func(c *ClientConfig) {
+		c.ScopedTokenSources = map[string]TokenSource{
+			"/beta/security/":           security,
+			"/beta/security/alerts":     alerts,
+			"https://vault.example.com": vault,
+		}
+	})
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://graph.example.com/v1.0/users", "Bearer test-token"},
+		{"https://graph.example.com/beta/security/incidents", "Bearer security"},
+		{"https://graph.example.com/beta/security/alerts/1", "Bearer alerts"},
+		{"https://vault.example.com/secrets/db", "Bearer vault"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err := client.prepRequestAuth(req); err != nil {
+				t.Fatalf("prepRequestAuth() error = %v", err)
+			}
+			if got := req.Header.Get("Authorization"); got != tt.want {
+				t.Errorf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := client.refreshToken(); err != nil {
+		t.Fatalf("refreshToken() error = %v", err)
+	}
+	for _, source := range []*staticTokenSource{security, alerts, vault} {
+		if source.refreshed != 1 {
+			t.Errorf("%s source refreshed %d times, want 1", source.token, source.refreshed)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// httpclient/transport.go
+package httpclient
+
+import (
+	"net"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// buildTransport constructs the http.Transport used by the default HTTPExecutor, starting from Go's default
+// transport settings and applying the transport related options in the client configuration.
+// When resolverCache is non-nil host names are resolved through it, letting the client flush stale addresses.
+// When policy has disallowed ranges they are enforced on the remote address of every connection dialed.
+func (c *ClientConfig) buildTransport(resolverCache *dnsCache, policy *hostPolicy) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsSettings := c.TLS
+	if tlsSettings == nil {
+		tlsSettings = &TLSConfig{}
+	}
+
+	tlsConfig, err := tlsSettings.build(c.Sugar)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	proxy, err := c.Proxy.proxyFunc(c.Sugar)
+	if err != nil {
+		return nil, err
+	}
+	transport.Proxy = proxy
+	transport.ProxyConnectHeader = c.Proxy.connectHeader()
+
+	dialer := &net.Dialer{
+		Timeout:   defaultDialTimeout,
+		KeepAlive: defaultDialKeepAlive,
+	}
+	dial := dialer.DialContext
+	var guard *dialGuard
+	if policy != nil && len(policy.disallowed) > 0 {
+		guard = &dialGuard{policy: policy}
+		dial = guard.dial(dialer)
+		transport.Proxy = guard.trackProxies(transport.Proxy)
+	}
+	if resolverCache != nil {
+		dial = resolverCache.wrapDialer(dial)
+	}
+	dial = c.AddressFamily.wrapDialer(dial)
+	if guard != nil {
+		dial = guard.markProxyDials(dial)
+	}
+	transport.DialContext = dial
+	if c.AddressFamily != AddressFamilyAuto {
+		c.Sugar.Infow("Restricting connections to a single address family", zap.String("address_family", string(c.AddressFamily)))
+	}
+
+	c.applyConnectionPoolSettings(transport)
+	c.applyProtocolPolicy(transport)
+
+	return transport, nil
+}
+
+// applyConnectionPoolSettings applies the connection pool limits from the client configuration to transport.
+func (c *ClientConfig) applyConnectionPoolSettings(transport *http.Transport) {
+	if c.MaxIdleConns > 0 {
+		transport.MaxIdleConns = c.MaxIdleConns
+	}
+
+	switch {
+	case c.MaxIdleConnsPerHost > 0:
+		transport.MaxIdleConnsPerHost = c.MaxIdleConnsPerHost
+	case c.MaxConcurrentRequests > 0:
+		transport.MaxIdleConnsPerHost = c.MaxConcurrentRequests
+	}
+
+	if c.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = c.MaxConnsPerHost
+	}
+
+	if c.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = c.IdleConnTimeout
+	}
+
+	c.Sugar.Debugw("Connection pool configured",
+		zap.Int("max_idle_conns", transport.MaxIdleConns),
+		zap.Int("max_idle_conns_per_host", transport.MaxIdleConnsPerHost),
+		zap.Int("max_conns_per_host", transport.MaxConnsPerHost),
+		zap.Duration("idle_conn_timeout", transport.IdleConnTimeout))
+}
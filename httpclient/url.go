@@ -0,0 +1,87 @@
+// httpclient/url.go
+package httpclient
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// constructURL builds the full request URL for endpoint. Absolute URLs are used verbatim; relative
+// endpoints have the configured BasePath prepended and are then appended to BaseURLOverride or, when it is
+// unset, resolved by the Integration.
+func (c *Client) constructURL(endpoint string) string {
+	if isAbsoluteURL(endpoint) {
+		return endpoint
+	}
+
+	path := joinBasePath(c.config.BasePath, endpoint)
+	if c.config.BaseURLOverride != "" {
+		if path != "" && !strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "?") {
+			path = "/" + path
+		}
+		return c.baseURL() + path
+	}
+
+	return (*c.Integration).ConstructURL(path)
+}
+
+// baseURL returns the scheme and host requests are sent to: BaseURLOverride when set, else the Integration's FQDN.
+func (c *Client) baseURL() string {
+	if c.config.BaseURLOverride != "" {
+		return strings.TrimRight(c.config.BaseURLOverride, "/")
+	}
+	return (*c.Integration).GetFQDN()
+}
+
+// validateBaseURLOverride checks BaseURLOverride, when set, is an absolute http or https URL.
+func validateBaseURLOverride(override string) error {
+	if override == "" {
+		return nil
+	}
+
+	u, err := url.Parse(override)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid base url override: %q, expected an absolute URL such as http://localhost:8443", override)
+	}
+	return nil
+}
+
+// requestURL builds the URL for endpoint like constructURL, then points relative endpoints at the request's
+// failover domain, if one is selected.
+func (c *Client) requestURL(endpoint string, ro *requestOptions) string {
+	target := c.constructURL(endpoint)
+	if ro == nil || ro.baseURL == nil || isAbsoluteURL(endpoint) {
+		return target
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return target
+	}
+	u.Scheme = ro.baseURL.Scheme
+	u.Host = ro.baseURL.Host
+
+	return u.String()
+}
+
+// isAbsoluteURL reports whether endpoint carries its own scheme and host.
+func isAbsoluteURL(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	return err == nil && u.IsAbs() && u.Host != ""
+}
+
+// joinBasePath prepends basePath to endpoint, ensuring exactly one slash between the two.
+func joinBasePath(basePath, endpoint string) string {
+	basePath = strings.Trim(basePath, "/")
+	if basePath == "" {
+		return endpoint
+	}
+
+	endpoint = strings.TrimLeft(endpoint, "/")
+	if endpoint == "" {
+		return "/" + basePath
+	}
+
+	return "/" + basePath + "/" + endpoint
+}
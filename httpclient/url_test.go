@@ -0,0 +1,72 @@
+// httpclient/url_test.go
+package httpclient
+
+import "testing"
+
+func TestClient_constructURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		basePath string
+		endpoint string
+		want     string
+	}{
+		{name: "no base path", basePath: "", endpoint: "/users", want: "https://api.example.com/users"},
+		{name: "plain join", basePath: "/api/v3", endpoint: "/users", want: "https://api.example.com/api/v3/users"},
+		{name: "trailing slash on base", basePath: "/api/v3/", endpoint: "/users", want: "https://api.example.com/api/v3/users"},
+		{name: "no slashes", basePath: "api/v3", endpoint: "users", want: "https://api.example.com/api/v3/users"},
+		{name: "both slashes doubled", basePath: "/api/v3//", endpoint: "//users", want: "https://api.example.com/api/v3/users"},
+		{name: "empty endpoint", basePath: "/api/v3", endpoint: "", want: "https://api.example.com/api/v3"},
+		{name: "query preserved", basePath: "/api/v3", endpoint: "/users?page=2", want: "https://api.example.com/api/v3/users?page=2"},
+		{name: "absolute endpoint bypasses base path", basePath: "/api/v3", endpoint: "https://other.example.com/users", want: "https://other.example.com/users"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, "https://api.example.com", func(config *ClientConfig) {
+				config.BasePath = tt.basePath
+			})
+
+			if got := client.constructURL(tt.endpoint); got != tt.want {
+				t.Errorf("constructURL(%q) = %q, want %q", tt.endpoint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_constructURL_BaseURLOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		override string
+		basePath string
+		endpoint string
+		want     string
+	}{
+		{name: "override replaces integration", override: "http://localhost:8443", endpoint: "/users", want: "http://localhost:8443/users"},
+		{name: "trailing slash on override", override: "http://localhost:8443/", endpoint: "/users", want: "http://localhost:8443/users"},
+		{name: "endpoint without slash", override: "http://localhost:8443", endpoint: "users", want: "http://localhost:8443/users"},
+		{name: "base path applied", override: "http://localhost:8443", basePath: "/api/v3", endpoint: "/users", want: "http://localhost:8443/api/v3/users"},
+		{name: "absolute endpoint wins", override: "http://localhost:8443", endpoint: "https://other.example.com/users", want: "https://other.example.com/users"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, "https://api.example.com", func(config *ClientConfig) {
+				config.BaseURLOverride = tt.override
+				config.BasePath = tt.basePath
+			})
+
+			if got := client.constructURL(tt.endpoint); got != tt.want {
+				t.Errorf("constructURL(%q) = %q, want %q", tt.endpoint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateBaseURLOverride(t *testing.T) {
+	for _, override := range []string{"localhost:8443", "ftp://files.example.com", "http://"} {
+		if err := validateBaseURLOverride(override); err == nil {
+			t.Errorf("validateBaseURLOverride(%q) error = nil, want an error", override)
+		}
+	}
+	if err := validateBaseURLOverride(""); err != nil {
+		t.Errorf("validateBaseURLOverride(\"\") error = %v, want nil", err)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"go.uber.org/zap"
 )
 
 // TODO all func comments in here
@@ -44,6 +46,20 @@ func getEnvAsString(name string, defaultVal string) string {
 	return defaultVal
 }
 
+// getEnvAsSecret reads a secret from the file named by <name>_FILE, the convention for Docker and Kubernetes
+// secrets mounted as files, falling back to the name variable itself and then defaultVal. A trailing newline in
+// the file is trimmed.
+func getEnvAsSecret(name string, defaultVal string) (string, error) {
+	if path, exists := os.LookupEnv(name + "_FILE"); exists && path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("could not read %s_FILE: %v", name, err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+	return getEnvAsString(name, defaultVal), nil
+}
+
 // getEnvAsBool reads an environment variable as a boolean, with a fallback default value.
 func getEnvAsBool(name string, defaultVal bool) bool {
 	if value, exists := os.LookupEnv(name); exists {
@@ -108,6 +124,30 @@ func setDefaultDuration(field *time.Duration, defaultValue time.Duration) {
 func (c *Client) CheckDeprecationHeader(resp *http.Response) {
 	deprecationHeader := resp.Header.Get("Deprecation")
 	if deprecationHeader != "" {
-		c.Sugar.Warn("API endpoint is deprecated", deprecationHeader, resp.Request.URL.String())
+		c.Logger().Warnw("API endpoint is deprecated", zap.String("deprecation", deprecationHeader), zap.String("url", resp.Request.URL.String()))
+	}
+}
+
+// slaThreshold returns the SLA threshold which applies to the given endpoint, or 0 if none is configured.
+func (c *Client) slaThreshold(endpoint string) time.Duration {
+	if threshold, ok := c.config.SLAEndpointThresholds[endpoint]; ok {
+		return threshold
+	}
+	return c.config.SLAThreshold
+}
+
+// checkSLA invokes the OnSLABreach callback in a new goroutine when a request's duration exceeds its SLA threshold,
+// so a slow callback never delays the request path.
+func (c *Client) checkSLA(endpoint string, duration time.Duration) {
+	if c.config.OnSLABreach == nil {
+		return
 	}
+
+	threshold := c.slaThreshold(endpoint)
+	if threshold <= 0 || duration <= threshold {
+		return
+	}
+
+	c.Logger().Warnw("Request exceeded SLA threshold", zap.String("endpoint", endpoint), zap.Duration("duration", duration), zap.Duration("threshold", threshold))
+	go c.config.OnSLABreach(endpoint, duration, threshold)
 }
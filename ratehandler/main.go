@@ -22,7 +22,6 @@ import (
 	"math"
 	"math/rand"
 	"net/http"
-	"strconv"
 	"time"
 
 	"go.uber.org/zap"
@@ -35,48 +34,49 @@ const (
 	jitterFactor = 0.5                    // Random jitter factor
 )
 
+// MaxBackoffDelay is the longest delay CalculateBackoff will return.
+const MaxBackoffDelay = maxDelay
+
 // CalculateBackoff calculates the next delay for retry with exponential backoff and jitter.
 // The baseDelay is the initial delay duration, which is exponentially increased on each retry.
 // The jitterFactor adds randomness to the delay to avoid simultaneous retries (thundering herd problem).
 // The delay is capped at maxDelay to prevent excessive wait times.
 func CalculateBackoff(retry int) time.Duration {
-	delay := float64(baseDelay) * math.Pow(2, float64(retry))
-
-	jitter := (rand.Float64() - 0.5) * jitterFactor * 2.0 // Random value between -jitterFactor and +jitterFactor
-	delayWithJitter := delay * (1.0 + jitter)
-
-	if delayWithJitter > float64(maxDelay) {
-		return maxDelay
-	}
-	return time.Duration(delayWithJitter)
+	return CalculateBackoffWithFloor(retry, baseDelay)
 }
 
-// ParseRateLimitHeaders parses common rate limit headers and adjusts behavior accordingly.
-// It handles both Retry-After (in seconds or HTTP-date format) and X-RateLimit-Reset headers.
-func ParseRateLimitHeaders(resp *http.Response, logger *zap.SugaredLogger) time.Duration {
-	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
-		if waitSeconds, err := strconv.Atoi(retryAfter); err == nil {
-			return time.Duration(waitSeconds) * time.Second
-
-		} else if retryAfterDate, err := time.Parse(time.RFC1123, retryAfter); err == nil {
-			return time.Until(retryAfterDate)
+// CalculateBackoffWithFloor is CalculateBackoff with a minimum delay: no retry sleeps less than minDelay, which
+// is itself capped at MaxBackoffDelay.
+func CalculateBackoffWithFloor(retry int, minDelay time.Duration) time.Duration {
+	return backoffDelay(retry, minDelay, rand.Float64())
+}
 
-		} else {
-			logger.Debug("Unable to parse Retry-After header", zap.String("value", retryAfter), zap.Error(err))
-		}
+// backoffDelay computes the delay for retry with jitter chosen by r, a random number in [0, 1). The jitter only
+// ever lengthens the exponential delay, by up to jitterFactor of it, so it cannot drop below the exponential
+// curve. The result is clamped to [minDelay, maxDelay].
+func backoffDelay(retry int, minDelay time.Duration, r float64) time.Duration {
+	if retry < 0 {
+		retry = 0
+	}
+	if minDelay > maxDelay {
+		minDelay = maxDelay
 	}
 
-	if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining == "0" {
-		if resetTimeStr := resp.Header.Get("X-RateLimit-Reset"); resetTimeStr != "" {
-			if resetTimeEpoch, err := strconv.ParseInt(resetTimeStr, 10, 64); err == nil {
-				resetTime := time.Unix(resetTimeEpoch, 0)
-				return time.Until(resetTime) + (5 * time.Second)
+	delay := float64(baseDelay) * math.Pow(2, float64(retry))
+	delay += delay * jitterFactor * r
 
-			} else {
-				logger.Debug("Unable to parse X-RateLimit-Reset header", zap.String("value", resetTimeStr), zap.Error(err))
-			}
-		}
+	switch {
+	case delay > float64(maxDelay):
+		return maxDelay
+	case delay < float64(minDelay):
+		return minDelay
 	}
+	return time.Duration(delay)
+}
 
-	return 0
+// ParseRateLimitHeaders returns how long to wait before the next request according to the response's rate limit
+// headers. It handles both Retry-After (in seconds or HTTP-date format) and X-RateLimit-Reset headers; use
+// ParseRateLimitInfo for the full parsed state.
+func ParseRateLimitHeaders(resp *http.Response, logger *zap.SugaredLogger) time.Duration {
+	return ParseRateLimitInfo(resp, logger).Wait()
 }
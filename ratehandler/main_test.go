@@ -0,0 +1,69 @@
+// ratehandler/main_test.go
+package ratehandler
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay_Bounds(t *testing.T) {
+	floors := []time.Duration{0, baseDelay, 750 * time.Millisecond, maxDelay, 2 * maxDelay}
+
+	for _, floor := range floors {
+		effectiveFloor := floor
+		if effectiveFloor > maxDelay {
+			effectiveFloor = maxDelay
+		}
+
+		for _, r := range []float64{0, 0.5, 0.999} {
+			previous := time.Duration(0)
+			for retry := 0; retry < 12; retry++ {
+				got := backoffDelay(retry, floor, r)
+
+				exponential := time.Duration(float64(baseDelay) * math.Pow(2, float64(retry)))
+				lower := exponential
+				if lower > maxDelay {
+					lower = maxDelay
+				}
+				if lower < effectiveFloor {
+					lower = effectiveFloor
+				}
+
+				if got < lower || got > maxDelay {
+					t.Errorf("backoffDelay(%d, %v, %v) = %v, want between %v and %v", retry, floor, r, got, lower, maxDelay)
+				}
+				if got < previous {
+					t.Errorf("backoffDelay(%d, %v, %v) = %v, shorter than the previous retry's %v", retry, floor, r, got, previous)
+				}
+				previous = got
+			}
+		}
+	}
+}
+
+func TestBackoffDelay_JitterOnlyLengthens(t *testing.T) {
+	if got := backoffDelay(0, 0, 0); got != baseDelay {
+		t.Errorf("backoffDelay(0, 0, 0) = %v, want %v", got, baseDelay)
+	}
+	if got, want := backoffDelay(1, 0, 0.5), time.Duration(float64(2*baseDelay)*(1+jitterFactor*0.5)); got != want {
+		t.Errorf("backoffDelay(1, 0, 0.5) = %v, want %v", got, want)
+	}
+	if got := backoffDelay(-3, 0, 0); got != baseDelay {
+		t.Errorf("backoffDelay(-3, 0, 0) = %v, want negative retries treated as the first", got)
+	}
+}
+
+func TestCalculateBackoffWithFloor(t *testing.T) {
+	const floor = 2 * time.Second
+	for retry := 0; retry < 10; retry++ {
+		for i := 0; i < 20; i++ {
+			if got := CalculateBackoffWithFloor(retry, floor); got < floor || got > MaxBackoffDelay {
+				t.Fatalf("CalculateBackoffWithFloor(%d, %v) = %v, want between %v and %v", retry, floor, got, floor, MaxBackoffDelay)
+			}
+			if got := CalculateBackoff(retry); got < baseDelay || got > MaxBackoffDelay {
+				t.Fatalf("CalculateBackoff(%d) = %v, want between %v and %v", retry, got, baseDelay, MaxBackoffDelay)
+			}
+		}
+	}
+}
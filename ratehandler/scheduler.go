@@ -0,0 +1,62 @@
+// ratehandler/scheduler.go
+package ratehandler
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrRetryCapacityExceeded is returned by BackoffScheduler.Wait when the maximum number of requests
+// are already backing off and the caller should fail fast rather than queue another sleep.
+var ErrRetryCapacityExceeded = errors.New("retry capacity exceeded: too many requests are already backing off")
+
+// BackoffScheduler bounds the number of requests which may be sleeping in a retry backoff at the same time.
+// During an outage every in-flight request would otherwise park a goroutine in a backoff sleep; capping the
+// number of active backoffs caps the memory and scheduler overhead of a retry storm.
+// A nil *BackoffScheduler, or one created with a limit of 0, does not bound backoffs.
+type BackoffScheduler struct {
+	slots chan struct{}
+}
+
+// NewBackoffScheduler returns a BackoffScheduler allowing at most maxActive concurrent backoffs.
+// A maxActive of 0 or less means unlimited.
+func NewBackoffScheduler(maxActive int) *BackoffScheduler {
+	if maxActive <= 0 {
+		return &BackoffScheduler{}
+	}
+
+	return &BackoffScheduler{slots: make(chan struct{}, maxActive)}
+}
+
+// Wait blocks for the given backoff duration while holding one of the scheduler's slots.
+// If all slots are taken it returns ErrRetryCapacityExceeded immediately. If ctx is done before the
+// duration elapses the context error is returned and the slot released.
+func (s *BackoffScheduler) Wait(ctx context.Context, d time.Duration) error {
+	if s != nil && s.slots != nil {
+		select {
+		case s.slots <- struct{}{}:
+			defer func() { <-s.slots }()
+		default:
+			return ErrRetryCapacityExceeded
+		}
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Active returns the number of requests currently backing off through the scheduler.
+func (s *BackoffScheduler) Active() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.slots)
+}
@@ -0,0 +1,90 @@
+// ratehandler/scheduler_test.go
+package ratehandler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBackoffScheduler_Saturation(t *testing.T) {
+	const capacity = 2
+	const backoff = 100 * time.Millisecond
+
+	scheduler := NewBackoffScheduler(capacity)
+
+	var wg sync.WaitGroup
+	results := make(chan error, capacity)
+	for i := 0; i < capacity; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- scheduler.Wait(context.Background(), backoff)
+		}()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for scheduler.Active() < capacity {
+		if time.Now().After(deadline) {
+			t.Fatalf("scheduler never saturated, active = %d", scheduler.Active())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	err := scheduler.Wait(context.Background(), backoff)
+	if !errors.Is(err, ErrRetryCapacityExceeded) {
+		t.Fatalf("Wait() on saturated scheduler error = %v, want ErrRetryCapacityExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed >= backoff {
+		t.Errorf("Wait() on saturated scheduler took %v, want it to fail fast", elapsed)
+	}
+
+	wg.Wait()
+	close(results)
+	for err := range results {
+		if err != nil {
+			t.Errorf("bounded Wait() error = %v, want nil", err)
+		}
+	}
+
+	if got := scheduler.Active(); got != 0 {
+		t.Errorf("Active() after backoffs completed = %d, want 0", got)
+	}
+	if err := scheduler.Wait(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("Wait() after slots freed error = %v, want nil", err)
+	}
+}
+
+func TestBackoffScheduler_Unlimited(t *testing.T) {
+	tests := []struct {
+		name      string
+		scheduler *BackoffScheduler
+	}{
+		{name: "zero limit", scheduler: NewBackoffScheduler(0)},
+		{name: "nil scheduler", scheduler: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.scheduler.Wait(context.Background(), time.Millisecond); err != nil {
+				t.Errorf("Wait() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestBackoffScheduler_ContextCancelled(t *testing.T) {
+	scheduler := NewBackoffScheduler(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := scheduler.Wait(ctx, time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+	if got := scheduler.Active(); got != 0 {
+		t.Errorf("Active() after cancelled wait = %d, want 0", got)
+	}
+}
@@ -0,0 +1,128 @@
+// ratehandler/state.go
+package ratehandler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// resetEpochThreshold separates the two X-RateLimit-Reset conventions: values at or above it are Unix epoch
+// timestamps (as sent by GitHub), smaller values are seconds until the window resets (as sent by many other
+// APIs). 1e9 seconds is September 2001, while no real quota window lasts anywhere near that long.
+const resetEpochThreshold = 1_000_000_000
+
+// maxResetHorizon bounds how far in the future a parsed X-RateLimit-Reset may lie. Anything further out is
+// treated as malformed rather than stalling the client for days.
+const maxResetHorizon = 24 * time.Hour
+
+// resetGrace is added to a wait derived from X-RateLimit-Reset to absorb clock skew between client and server.
+const resetGrace = 5 * time.Second
+
+// RateLimitInfo is the server's rate limit position as reported by a response's headers. It is parsed once by
+// ParseRateLimitInfo so the retry logic, the concurrency handler and logging all read the same values.
+type RateLimitInfo struct {
+	// Limit is the request quota for the current window (X-RateLimit-Limit), or -1 when not reported.
+	Limit int
+	// Remaining is the number of requests left in the current window (X-RateLimit-Remaining), or -1 when not reported.
+	Remaining int
+	// Reset is when the current window resets (X-RateLimit-Reset, as an epoch timestamp or seconds from now), or the
+	// zero time when not reported.
+	Reset time.Time
+	// RetryAfter is how long the Retry-After header asks the client to wait, given in seconds or as an HTTP-date.
+	RetryAfter time.Duration
+
+	// HasLimit, HasRemaining, HasReset and HasRetryAfter report whether the corresponding header was present and
+	// parsed successfully.
+	HasLimit      bool
+	HasRemaining  bool
+	HasReset      bool
+	HasRetryAfter bool
+}
+
+// ParseRateLimitInfo reads the common rate limit headers (X-RateLimit-Limit, X-RateLimit-Remaining,
+// X-RateLimit-Reset and Retry-After) from resp. Malformed headers are logged at debug level and treated as absent.
+func ParseRateLimitInfo(resp *http.Response, logger *zap.SugaredLogger) RateLimitInfo {
+	var info RateLimitInfo
+	info.Limit, info.HasLimit = headerInt(resp, "X-RateLimit-Limit", logger)
+	info.Remaining, info.HasRemaining = headerInt(resp, "X-RateLimit-Remaining", logger)
+
+	if reset := resp.Header.Get("X-RateLimit-Reset"); reset != "" {
+		if resetTime, err := parseRateLimitReset(reset, time.Now()); err == nil {
+			info.Reset = resetTime
+			info.HasReset = true
+		} else {
+			logger.Debugw("Unable to parse X-RateLimit-Reset header", zap.String("value", reset), zap.Error(err))
+		}
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if waitSeconds, err := strconv.Atoi(retryAfter); err == nil {
+			info.RetryAfter = time.Duration(waitSeconds) * time.Second
+			info.HasRetryAfter = true
+		} else if retryAfterDate, err := time.Parse(time.RFC1123, retryAfter); err == nil {
+			info.RetryAfter = time.Until(retryAfterDate)
+			info.HasRetryAfter = true
+		} else {
+			logger.Debugw("Unable to parse Retry-After header", zap.String("value", retryAfter), zap.Error(err))
+		}
+	}
+
+	return info
+}
+
+// Wait returns how long the client should wait before its next request. Retry-After takes precedence; otherwise an
+// exhausted quota waits until the window resets, plus a small grace period. 0 means no wait was requested.
+func (i RateLimitInfo) Wait() time.Duration {
+	if i.HasRetryAfter {
+		return i.RetryAfter
+	}
+	if i.HasRemaining && i.Remaining == 0 && i.HasReset {
+		return time.Until(i.Reset) + resetGrace
+	}
+	return 0
+}
+
+// Reported reports whether the response carried any rate limit headers.
+func (i RateLimitInfo) Reported() bool {
+	return i.HasLimit || i.HasRemaining || i.HasReset || i.HasRetryAfter
+}
+
+// parseRateLimitReset interprets an X-RateLimit-Reset value relative to now. Large values are Unix epoch timestamps
+// and small ones a delta in seconds; negative values and resets beyond maxResetHorizon are rejected.
+func parseRateLimitReset(value string, now time.Time) (time.Time, error) {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if seconds < 0 {
+		return time.Time{}, fmt.Errorf("negative reset value %d", seconds)
+	}
+
+	reset := now.Add(time.Duration(seconds) * time.Second)
+	if seconds >= resetEpochThreshold {
+		reset = time.Unix(seconds, 0)
+	}
+	if reset.Sub(now) > maxResetHorizon {
+		return time.Time{}, fmt.Errorf("reset %s is more than %s away", reset.Format(time.RFC3339), maxResetHorizon)
+	}
+
+	return reset, nil
+}
+
+// headerInt parses an integer header, returning -1 and false when it is missing or malformed.
+func headerInt(resp *http.Response, name string, logger *zap.SugaredLogger) (int, bool) {
+	raw := resp.Header.Get(name)
+	if raw == "" {
+		return -1, false
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		logger.Debugw("Unable to parse rate limit header", zap.String("header", name), zap.String("value", raw), zap.Error(err))
+		return -1, false
+	}
+	return value, true
+}
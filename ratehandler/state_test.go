@@ -0,0 +1,137 @@
+// ratehandler/state_test.go
+package ratehandler
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestParseRateLimitInfo(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Truncate(time.Second)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    RateLimitInfo
+	}{
+		{name: "no headers", want: RateLimitInfo{Limit: -1, Remaining: -1}},
+		{
+			name: "quota headers",
+			headers: map[string]string{
+				"X-RateLimit-Limit":     "100",
+				"X-RateLimit-Remaining": "42",
+				"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+			},
+			want: RateLimitInfo{Limit: 100, Remaining: 42, Reset: reset, HasLimit: true, HasRemaining: true, HasReset: true},
+		},
+		{
+			name:    "retry after seconds",
+			headers: map[string]string{"Retry-After": "30"},
+			want:    RateLimitInfo{Limit: -1, Remaining: -1, RetryAfter: 30 * time.Second, HasRetryAfter: true},
+		},
+		{
+			name:    "malformed headers are treated as absent",
+			headers: map[string]string{"X-RateLimit-Remaining": "lots", "X-RateLimit-Reset": "soon", "Retry-After": "later"},
+			want:    RateLimitInfo{Limit: -1, Remaining: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseRateLimitInfo(newResponse(tt.headers), zap.NewNop().Sugar())
+			if got != tt.want {
+				t.Errorf("ParseRateLimitInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRateLimitInfo_RetryAfterDate(t *testing.T) {
+	date := time.Now().Add(time.Minute).UTC().Format(time.RFC1123)
+	info := ParseRateLimitInfo(newResponse(map[string]string{"Retry-After": date}), zap.NewNop().Sugar())
+
+	if !info.HasRetryAfter {
+		t.Fatal("HasRetryAfter = false, want true for an HTTP-date")
+	}
+	if info.RetryAfter <= 58*time.Second || info.RetryAfter > time.Minute {
+		t.Errorf("RetryAfter = %v, want about a minute", info.RetryAfter)
+	}
+}
+
+func TestParseRateLimitHeaders(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		min, max time.Duration
+	}{
+		{name: "no headers", min: 0, max: 0},
+		{name: "retry after wins", headers: map[string]string{"Retry-After": "3", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset}, min: 3 * time.Second, max: 3 * time.Second},
+		{name: "exhausted quota waits for reset", headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset}, min: time.Minute, max: time.Minute + resetGrace},
+		{name: "quota remaining", headers: map[string]string{"X-RateLimit-Remaining": "5", "X-RateLimit-Reset": reset}, min: 0, max: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseRateLimitHeaders(newResponse(tt.headers), zap.NewNop().Sugar())
+			if got < tt.min || got > tt.max {
+				t.Errorf("ParseRateLimitHeaders() = %v, want between %v and %v", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func newResponse(headers map[string]string) *http.Response {
+	resp := &http.Response{Header: http.Header{}}
+	for name, value := range headers {
+		resp.Header.Set(name, value)
+	}
+	return resp
+}
+
+func TestParseRateLimitReset(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "epoch timestamp", value: "1700000060", want: now.Add(time.Minute)},
+		{name: "delta seconds", value: "60", want: now.Add(time.Minute)},
+		{name: "zero delta resets now", value: "0", want: now},
+		{name: "day long delta", value: "86400", want: now.Add(24 * time.Hour)},
+		{name: "epoch decades ahead", value: "4102444800", wantErr: true},
+		{name: "delta beyond a day", value: "999999999", wantErr: true},
+		{name: "negative", value: "-60", wantErr: true},
+		{name: "fractional", value: "60.5", wantErr: true},
+		{name: "not a number", value: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRateLimitReset(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRateLimitReset(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseRateLimitReset(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRateLimitHeaders_DeltaReset(t *testing.T) {
+	headers := map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "30"}
+
+	got := ParseRateLimitHeaders(newResponse(headers), zap.NewNop().Sugar())
+	if got < 30*time.Second || got > 30*time.Second+resetGrace {
+		t.Errorf("ParseRateLimitHeaders() = %v, want about 30s plus grace", got)
+	}
+}
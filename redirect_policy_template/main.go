@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 
 	"go.uber.org/zap"
@@ -11,14 +12,15 @@ import (
 
 // RedirectHandler contains configurations for handling HTTP redirects.
 type RedirectHandler struct {
-	Logger             *zap.SugaredLogger           // Logger instance for logging.
-	MaxRedirects       int                          // Maximum allowed redirects to prevent infinite loops.
-	VisitedURLs        map[string]int               // Tracks visited URLs to detect loops.
-	VisitedURLsMutex   sync.RWMutex                 // Mutex for safe concurrent access to VisitedURLs.
-	SensitiveHeaders   []string                     // Headers to be removed on cross-domain redirects.
-	PermanentRedirects map[string]string            // Cache for permanent redirects
-	PermRedirectsMutex sync.RWMutex                 // Mutex for safe concurrent access to PermanentRedirects
-	RedirectHistories  map[*http.Request][]*url.URL // Map to track redirect history for each request
+	Logger             *zap.SugaredLogger // Logger instance for logging.
+	MaxRedirects       int                // Maximum allowed redirects to prevent infinite loops.
+	SensitiveHeaders   []string           // Headers to be removed on cross-origin redirects.
+	PermanentRedirects map[string]string  // Cache for permanent redirects
+	PermRedirectsMutex sync.RWMutex       // Mutex for safe concurrent access to PermanentRedirects
+
+	// OnRedirect, if set, is called for every redirect about to be followed, before sensitive headers are stripped.
+	// Returning an error aborts the redirect chain with that error, e.g. to enforce an allowlist of redirect targets.
+	OnRedirect func(from, to *url.URL) error
 }
 
 // NewRedirectHandler creates a new instance of RedirectHandler.
@@ -26,10 +28,8 @@ func NewRedirectHandler(logger *zap.SugaredLogger, maxRedirects int) *RedirectHa
 	return &RedirectHandler{
 		Logger:             logger,
 		MaxRedirects:       maxRedirects,
-		VisitedURLs:        make(map[string]int),
 		SensitiveHeaders:   []string{"Authorization", "Cookie"},
 		PermanentRedirects: make(map[string]string),
-		RedirectHistories:  make(map[*http.Request][]*url.URL),
 	}
 }
 
@@ -43,100 +43,114 @@ func (r *RedirectHandler) WithRedirectHandling(client *http.Client) {
 	client.CheckRedirect = r.checkRedirect
 }
 
-// checkRedirect implements the redirect handling logic.
+// checkRedirect implements the redirect handling logic. net/http has already built req for the redirect target,
+// carrying the redirect response that caused it in req.Response; via holds the requests made so far, oldest first.
 func (r *RedirectHandler) checkRedirect(req *http.Request, via []*http.Request) error {
-	defer r.clearRedirectHistory(req)
-
 	// Enforce max redirects
 	if len(via) >= r.MaxRedirects {
-		r.Logger.Warn("Maximum redirects reached", zap.Int("maxRedirects", r.MaxRedirects))
+		r.Logger.Warnw("Stopped after maximum redirects", zap.Int("max_redirects", r.MaxRedirects))
 		return &MaxRedirectsError{MaxRedirects: r.MaxRedirects}
 	}
 
-	// Return if disallowed method
-	// TODO why?
+	lastResponse := req.Response
+	if lastResponse == nil {
+		return http.ErrUseLastResponse
+	}
+	previous := via[len(via)-1]
+
+	// net/http turns the method into GET for 301, 302 and 303 responses to anything but GET or HEAD; drop the
+	// body headers that went with the original method.
+	if req.Method != previous.Method {
+		r.adjustForSeeOther(req)
+		r.Logger.Infow("Changed request method to GET",
+			zap.String("original_method", previous.Method),
+			zap.Int("status_code", lastResponse.StatusCode))
+	}
+
+	// 307 and 308 preserve the method; don't replay non-idempotent requests against a new location.
 	if req.Method == http.MethodPost || req.Method == http.MethodPatch {
-		r.Logger.Warn("Redirect attempted on non-idempotent method, not following", zap.String("method", req.Method))
+		r.Logger.Warnw("Redirect attempted on non-idempotent method, not following", zap.String("method", req.Method))
 		return http.ErrUseLastResponse
 	}
 
-	// Check for cached permanent redirect
-	// TODO why do we need to cache these?
-	urlString, ok := r.checkPermanentRedirect(req.URL.String())
-	if ok && (req.Method == http.MethodGet || req.Method == http.MethodHead) {
+	// Jump straight to the end of a previously seen permanent redirect.
+	if urlString, ok := r.checkPermanentRedirect(req.URL.String()); ok && (req.Method == http.MethodGet || req.Method == http.MethodHead) {
 		parsedURL, err := url.Parse(urlString)
 		if err != nil {
-			// TODO is there ever a time where the cached one will be invalid?
-			r.Logger.Error("Failed to parse URL from cache", zap.String("url", urlString), zap.Error(err))
+			r.Logger.Errorw("Failed to parse URL from cache", zap.String("url", urlString), zap.Error(err))
 		} else {
+			r.Logger.Infow("Using cached permanent redirect", zap.String("original_url", req.URL.String()), zap.String("redirect_url", urlString))
 			req.URL = parsedURL
-			r.Logger.Info("Using cached permanent redirect", zap.String("originalURL", urlString), zap.String("redirectURL", parsedURL.String()))
-			return nil
 		}
 	}
 
-	// Track redirect history for the current request
-	r.RedirectHistories[req] = append(r.RedirectHistories[req], req.URL)
-
-	// Check for redirect loops by analyzing the history
-	if redirectLoop(r.RedirectHistories[req]) {
-		r.Logger.Error("Redirect loop detected", zap.Any("redirectHistory", r.RedirectHistories[req]))
-		return fmt.Errorf("redirect loop detected: %v", r.RedirectHistories[req])
+	// Check for redirect loops across the whole chain
+	history := make([]*url.URL, 0, len(via)+1)
+	for _, v := range via {
+		history = append(history, v.URL)
+	}
+	history = append(history, req.URL)
+	if redirectLoop(history) {
+		r.Logger.Errorw("Detected redirect loop", zap.Any("redirect_history", history))
+		return &RedirectLoopError{URL: req.URL.String()}
 	}
 
-	lastResponse := via[len(via)-1].Response
-	if lastResponse.StatusCode == http.StatusPermanentRedirect || lastResponse.StatusCode == http.StatusTemporaryRedirect {
-		location, err := lastResponse.Location()
-		if err != nil {
-			r.Logger.Error("Failed to get location from redirect response", zap.Error(err))
-			return err
-		}
-
-		newReqURL, err := r.resolveRedirectURL(req.URL, location)
-		if err != nil {
-			r.Logger.Error("Failed to resolve redirect URL", zap.Error(err))
+	if r.OnRedirect != nil {
+		if err := r.OnRedirect(previous.URL, req.URL); err != nil {
+			r.Logger.Warnw("Redirect rejected by OnRedirect hook",
+				zap.String("from", previous.URL.String()),
+				zap.String("to", req.URL.String()),
+				zap.Error(err))
 			return err
 		}
-
-		if newReqURL.Host != req.URL.Host {
-			r.secureRequest(req)
-		}
-
-		if lastResponse.StatusCode == http.StatusPermanentRedirect {
-			r.cachePermanentRedirect(req.URL.String(), newReqURL.String())
-		}
-
-		if lastResponse.StatusCode == http.StatusSeeOther {
-			r.adjustForSeeOther(req)
-		}
-
-		r.Logger.Info("Redirecting request", zap.String("originalURL", req.URL.String()), zap.String("newURL", newReqURL.String()), zap.Int("redirectCount", len(via)))
-		req.URL = newReqURL
-		return nil
 	}
 
-	// Clear redirect history if redirect is successful
-	if len(via) > 0 && lastResponse.StatusCode >= 200 && lastResponse.StatusCode < 400 {
-		redirectedReq := via[len(via)-1]
-		r.clearRedirectHistory(redirectedReq)
+	if !sameOrigin(previous.URL, req.URL) {
+		r.secureRequest(req)
 	}
 
-	return http.ErrUseLastResponse
-}
-
-// resolveRedirectURL resolves the redirect location URL against the current request URL.
-func (r *RedirectHandler) resolveRedirectURL(reqURL *url.URL, redirectURL *url.URL) (*url.URL, error) {
-	if !redirectURL.IsAbs() {
-		redirectURL.Scheme = reqURL.Scheme
+	if lastResponse.StatusCode == http.StatusMovedPermanently || lastResponse.StatusCode == http.StatusPermanentRedirect {
+		r.cachePermanentRedirect(previous.URL.String(), req.URL.String())
 	}
-	return redirectURL, nil
+
+	r.Logger.Infow("Redirecting request",
+		zap.String("original_url", previous.URL.String()),
+		zap.String("new_url", req.URL.String()),
+		zap.Int("redirect_count", len(via)))
+	return nil
 }
 
-// secureRequest removes sensitive headers from the request if the new destination is a different domain.
+// secureRequest removes sensitive headers from the request if the new destination is a different origin.
 func (r *RedirectHandler) secureRequest(req *http.Request) {
 	for _, header := range r.SensitiveHeaders {
+		if req.Header.Get(header) == "" {
+			continue
+		}
 		req.Header.Del(header)
+		r.Logger.Infow("Removed sensitive header", zap.String("header", header), zap.String("host", req.URL.Host))
+	}
+}
+
+// sameOrigin reports whether a and b share scheme, host and port, so that an https to http downgrade or a move to
+// another port on the same host counts as leaving the origin. A missing port means the scheme's default.
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) &&
+		strings.EqualFold(a.Hostname(), b.Hostname()) &&
+		originPort(a) == originPort(b)
+}
+
+// originPort returns the port of u, falling back to the default port of its scheme.
+func originPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		return "443"
+	case "http":
+		return "80"
 	}
+	return ""
 }
 
 // adjustForSeeOther adjusts the request for "303 See Other" responses.
@@ -206,25 +220,22 @@ func redirectLoop(history []*url.URL) bool {
 	return false
 }
 
-// clearRedirectHistory clears the redirect history for a given request to prevent memory leaks.
-func (r *RedirectHandler) clearRedirectHistory(req *http.Request) {
-	r.VisitedURLsMutex.Lock()
-	delete(r.RedirectHistories, req)
-	r.VisitedURLsMutex.Unlock()
-}
-
-// GetRedirectHistory returns the redirect history for a given request.
-func (r *RedirectHandler) GetRedirectHistory(req *http.Request) []*url.URL {
-	r.VisitedURLsMutex.RLock()
-	defer r.VisitedURLsMutex.RUnlock()
-
-	return r.RedirectHistories[req]
-}
-
 // SetupRedirectHandler configures the HTTP client for redirect handling based on the client configuration.
-func SetCustomRedirect(client *http.Client, maxRedirects int, log *zap.SugaredLogger) {
+// When followRedirects is false the client is left untouched, keeping Go's default redirect policy.
+func SetupRedirectHandler(client *http.Client, followRedirects bool, maxRedirects int, log *zap.SugaredLogger) {
+	if !followRedirects {
+		log.Info("Redirect handling disabled")
+		return
+	}
+
 	redirectHandler := NewRedirectHandler(log, maxRedirects)
 	redirectHandler.WithRedirectHandling(client)
 	log.Info("Redirect handling enabled", zap.Int("MaxRedirects", maxRedirects))
+}
 
+// SetCustomRedirect enables redirect handling on client.
+//
+// Deprecated: use SetupRedirectHandler.
+func SetCustomRedirect(client *http.Client, maxRedirects int, log *zap.SugaredLogger) {
+	SetupRedirectHandler(client, true, maxRedirects, log)
 }
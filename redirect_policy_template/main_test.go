@@ -0,0 +1,256 @@
+package redirect
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSetupRedirectHandler_SensitiveHeaders(t *testing.T) {
+	var gotAuth string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer target.Close()
+
+	tests := []struct {
+		name     string
+		location func(origin string) string
+		wantAuth string
+	}{
+		// Both servers listen on 127.0.0.1, which net/http treats as the same domain, so only the handler strips.
+		{"same host keeps Authorization", func(origin string) string { return origin + "/final" }, "Bearer token"},
+		{"cross host strips Authorization", func(string) string { return target.URL + "/final" }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAuth = "unset"
+			var origin *httptest.Server
+			origin = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/final" {
+					gotAuth = r.Header.Get("Authorization")
+					return
+				}
+				http.Redirect(w, r, tt.location(origin.URL), http.StatusTemporaryRedirect)
+			}))
+			defer origin.Close()
+
+			client := &http.Client{}
+			SetupRedirectHandler(client, true, 5, zap.NewNop().Sugar())
+
+			req, _ := http.NewRequest(http.MethodGet, origin.URL+"/start", nil)
+			req.Header.Set("Authorization", "Bearer token")
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if gotAuth != tt.wantAuth {
+				t.Errorf("Authorization at redirect target = %q, want %q", gotAuth, tt.wantAuth)
+			}
+		})
+	}
+}
+
+func TestSetupRedirectHandler_Disabled(t *testing.T) {
+	client := &http.Client{}
+	SetupRedirectHandler(client, false, 5, zap.NewNop().Sugar())
+
+	if client.CheckRedirect != nil {
+		t.Error("CheckRedirect set although followRedirects is false")
+	}
+}
+
+func TestCheckRedirect_Behaviours(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		handler    func(w http.ResponseWriter, r *http.Request)
+		wantLog    string
+		wantErr    func(error) bool
+		wantMethod string
+	}{
+		{
+			name:   "redirect loop",
+			method: http.MethodGet,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/a" {
+					http.Redirect(w, r, "/b", http.StatusFound)
+					return
+				}
+				http.Redirect(w, r, "/a", http.StatusFound)
+			},
+			wantLog: "Detected redirect loop",
+			wantErr: func(err error) bool { var e *RedirectLoopError; return errors.As(err, &e) },
+		},
+		{
+			name:   "maximum redirects",
+			method: http.MethodGet,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, r.URL.Path+"x", http.StatusFound)
+			},
+			wantLog: "Stopped after maximum redirects",
+			wantErr: func(err error) bool { var e *MaxRedirectsError; return errors.As(err, &e) },
+		},
+		{
+			name:   "see other changes method",
+			method: http.MethodPost,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/a" {
+					http.Redirect(w, r, "/result", http.StatusSeeOther)
+				}
+			},
+			wantLog:    "Changed request method to GET",
+			wantMethod: http.MethodGet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMethod string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotMethod = r.Method
+				tt.handler(w, r)
+			}))
+			defer server.Close()
+
+			core, logs := observer.New(zapcore.DebugLevel)
+			client := &http.Client{}
+			SetupRedirectHandler(client, true, 3, zap.New(core).Sugar())
+
+			req, _ := http.NewRequest(tt.method, server.URL+"/a", strings.NewReader("payload"))
+			resp, err := client.Do(req)
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Errorf("Do() error = %v, want a %s error", err, tt.name)
+				}
+			} else if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			if tt.wantMethod != "" && gotMethod != tt.wantMethod {
+				t.Errorf("method at redirect target = %s, want %s", gotMethod, tt.wantMethod)
+			}
+			if logs.FilterMessage(tt.wantLog).Len() == 0 {
+				t.Errorf("no %q log entry", tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestCheckRedirect_SensitiveHeadersByOrigin(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		wantAuth string
+	}{
+		{"same origin", "https://api.example.com/a", "https://api.example.com/b", "Bearer token"},
+		{"explicit default port", "https://api.example.com/a", "https://api.example.com:443/b", "Bearer token"},
+		{"https to http downgrade", "https://api.example.com/a", "http://api.example.com/b", ""},
+		{"different port", "https://api.example.com/a", "https://api.example.com:8443/b", ""},
+		{"different host", "https://api.example.com/a", "https://other.example.com/b", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewRedirectHandler(zap.NewNop().Sugar(), 5)
+
+			previous, _ := http.NewRequest(http.MethodGet, tt.from, nil)
+			req, _ := http.NewRequest(http.MethodGet, tt.to, nil)
+			req.Header.Set("Authorization", "Bearer token")
+			req.Response = &http.Response{StatusCode: http.StatusTemporaryRedirect, Request: previous}
+
+			if err := handler.checkRedirect(req, []*http.Request{previous}); err != nil {
+				t.Fatalf("checkRedirect() error = %v", err)
+			}
+			if got := req.Header.Get("Authorization"); got != tt.wantAuth {
+				t.Errorf("Authorization after redirect = %q, want %q", got, tt.wantAuth)
+			}
+		})
+	}
+}
+
+func TestSecureRequest_LogsRemovedHeaders(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	handler := NewRedirectHandler(zap.New(core).Sugar(), 5)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://other.example.com", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	handler.secureRequest(req)
+
+	if req.Header.Get("Authorization") != "" {
+		t.Error("Authorization header not removed")
+	}
+	if logs.FilterMessage("Removed sensitive header").Len() != 1 {
+		t.Errorf("got %d \"Removed sensitive header\" entries, want 1 (Cookie was not set)", logs.FilterMessage("Removed sensitive header").Len())
+	}
+}
+
+func TestCheckRedirect_OnRedirect(t *testing.T) {
+	errDisallowed := errors.New("redirect target not allowed")
+
+	tests := []struct {
+		name    string
+		hookErr error
+		wantErr bool
+	}{
+		{"allowed", nil, false},
+		{"vetoed", errDisallowed, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reachedTarget := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/final" {
+					reachedTarget = true
+					return
+				}
+				http.Redirect(w, r, "/final", http.StatusTemporaryRedirect)
+			}))
+			defer server.Close()
+
+			var from, to *url.URL
+			handler := NewRedirectHandler(zap.NewNop().Sugar(), 5)
+			client := &http.Client{}
+			handler.WithRedirectHandling(client)
+			handler.OnRedirect = func(f, tgt *url.URL) error {
+				from, to = f, tgt
+				return tt.hookErr
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/start", nil)
+			resp, err := client.Do(req)
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			if tt.wantErr {
+				if !errors.Is(err, errDisallowed) {
+					t.Errorf("Do() error = %v, want %v", err, errDisallowed)
+				}
+				if reachedTarget {
+					t.Error("vetoed redirect was followed")
+				}
+			} else if err != nil || !reachedTarget {
+				t.Errorf("Do() error = %v, reached target = %v", err, reachedTarget)
+			}
+
+			if from == nil || from.Path != "/start" || to == nil || to.Path != "/final" {
+				t.Errorf("OnRedirect(from, to) = (%v, %v), want /start -> /final", from, to)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// response/envelope.go
+/* Envelope decoders flatten hypermedia style JSON documents (HAL and JSON:API) into the plain shape the
+caller's output struct expects, before the regular JSON unmarshaller runs. */
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// EnvelopeDecoder transforms an enveloped JSON response body into plain JSON matching the shape of out.
+// The returned bytes are handed to the regular JSON unmarshaller in place of the original body.
+type EnvelopeDecoder interface {
+	Decode(body []byte, out interface{}) ([]byte, error)
+}
+
+// HALDecoder flattens HAL (application/hal+json) documents. The "_links" member is dropped and every
+// "_embedded" resource is lifted into its parent under its relation name. When out points to a slice and
+// the document embeds exactly one relation (or the relation named by Collection), that relation's resources
+// are returned directly so a collection resource can be decoded straight into a slice.
+type HALDecoder struct {
+	// Collection optionally names the embedded relation returned when decoding into a slice.
+	Collection string
+}
+
+// Decode implements EnvelopeDecoder for HAL documents.
+func (d HALDecoder) Decode(body []byte, out interface{}) ([]byte, error) {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse HAL document: %w", err)
+	}
+
+	if root, ok := doc.(map[string]interface{}); ok && isSliceTarget(out) {
+		if embedded, ok := root["_embedded"].(map[string]interface{}); ok {
+			relation := d.Collection
+			if relation == "" && len(embedded) == 1 {
+				for name := range embedded {
+					relation = name
+				}
+			}
+			if items, ok := embedded[relation]; ok {
+				return json.Marshal(flattenHAL(items))
+			}
+		}
+	}
+
+	return json.Marshal(flattenHAL(doc))
+}
+
+// flattenHAL recursively removes HAL control members from a decoded document.
+func flattenHAL(node interface{}) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		flat := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if key == "_links" || key == "_embedded" {
+				continue
+			}
+			flat[key] = flattenHAL(value)
+		}
+		if embedded, ok := v["_embedded"].(map[string]interface{}); ok {
+			for relation, resources := range embedded {
+				flat[relation] = flattenHAL(resources)
+			}
+		}
+		return flat
+	case []interface{}:
+		flat := make([]interface{}, len(v))
+		for i, item := range v {
+			flat[i] = flattenHAL(item)
+		}
+		return flat
+	default:
+		return v
+	}
+}
+
+// JSONAPIDecoder flattens JSON:API (application/vnd.api+json) documents. Each resource object is
+// collapsed into a single object holding its "id", "type" and attributes, and relationships are
+// replaced by the related resources, resolved from the "included" member when present.
+type JSONAPIDecoder struct{}
+
+// jsonAPIResource is a JSON:API resource object or resource identifier.
+type jsonAPIResource struct {
+	ID            string                         `json:"id"`
+	Type          string                         `json:"type"`
+	Attributes    map[string]interface{}         `json:"attributes"`
+	Relationships map[string]jsonAPIRelationship `json:"relationships"`
+	Meta          map[string]interface{}         `json:"meta,omitempty"`
+	Links         map[string]interface{}         `json:"links,omitempty"`
+}
+
+// jsonAPIRelationship is a JSON:API relationship object. Data holds either a single
+// resource identifier, an array of them, or null.
+type jsonAPIRelationship struct {
+	Data json.RawMessage `json:"data"`
+}
+
+// jsonAPIDocument is the top level JSON:API document.
+type jsonAPIDocument struct {
+	Data     json.RawMessage   `json:"data"`
+	Included []jsonAPIResource `json:"included"`
+}
+
+// Decode implements EnvelopeDecoder for JSON:API documents.
+func (JSONAPIDecoder) Decode(body []byte, out interface{}) ([]byte, error) {
+	var doc jsonAPIDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON:API document: %w", err)
+	}
+
+	included := make(map[string]jsonAPIResource, len(doc.Included))
+	for _, resource := range doc.Included {
+		included[resource.Type+"/"+resource.ID] = resource
+	}
+
+	flat, err := flattenJSONAPIData(doc.Data, included, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(flat)
+}
+
+// maxJSONAPIDepth bounds relationship resolution so circular references in "included" terminate.
+const maxJSONAPIDepth = 5
+
+// flattenJSONAPIData flattens the primary data of a document or relationship, which may be a single
+// resource, an array of resources or null.
+func flattenJSONAPIData(data json.RawMessage, included map[string]jsonAPIResource, depth int) (interface{}, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	if data[0] == '[' {
+		var resources []jsonAPIResource
+		if err := json.Unmarshal(data, &resources); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON:API resource collection: %w", err)
+		}
+		flat := make([]interface{}, 0, len(resources))
+		for _, resource := range resources {
+			item, err := flattenJSONAPIResource(resource, included, depth)
+			if err != nil {
+				return nil, err
+			}
+			flat = append(flat, item)
+		}
+		return flat, nil
+	}
+
+	var resource jsonAPIResource
+	if err := json.Unmarshal(data, &resource); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON:API resource: %w", err)
+	}
+	return flattenJSONAPIResource(resource, included, depth)
+}
+
+// flattenJSONAPIResource merges a resource's identity, attributes and resolved relationships into one object.
+func flattenJSONAPIResource(resource jsonAPIResource, included map[string]jsonAPIResource, depth int) (map[string]interface{}, error) {
+	if full, ok := included[resource.Type+"/"+resource.ID]; ok && resource.Attributes == nil && depth > 0 {
+		resource = full
+	}
+
+	flat := make(map[string]interface{}, len(resource.Attributes)+len(resource.Relationships)+2)
+	for key, value := range resource.Attributes {
+		flat[key] = value
+	}
+	flat["id"] = resource.ID
+	flat["type"] = resource.Type
+
+	if depth >= maxJSONAPIDepth {
+		return flat, nil
+	}
+
+	for name, relationship := range resource.Relationships {
+		related, err := flattenJSONAPIData(relationship.Data, included, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		flat[name] = related
+	}
+
+	return flat, nil
+}
+
+// isSliceTarget reports whether out is a pointer to a slice.
+func isSliceTarget(out interface{}) bool {
+	t := reflect.TypeOf(out)
+	return t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Slice
+}
@@ -0,0 +1,122 @@
+// response/envelope_test.go
+package response
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type halCustomer struct {
+	Name string `json:"name"`
+}
+
+type halOrder struct {
+	ID       int         `json:"id"`
+	Total    float64     `json:"total"`
+	Customer halCustomer `json:"customer"`
+}
+
+const halOrdersDocument = `{
+	"_links": {"self": {"href": "/orders"}, "next": {"href": "/orders?page=2"}},
+	"count": 2,
+	"_embedded": {
+		"orders": [
+			{"_links": {"self": {"href": "/orders/123"}}, "id": 123, "total": 30.0,
+			 "_embedded": {"customer": {"_links": {"self": {"href": "/customers/7"}}, "name": "Ada"}}},
+			{"_links": {"self": {"href": "/orders/124"}}, "id": 124, "total": 20.0,
+			 "_embedded": {"customer": {"_links": {"self": {"href": "/customers/8"}}, "name": "Grace"}}}
+		]
+	}
+}`
+
+func TestHALDecoder_Decode(t *testing.T) {
+	t.Run("collection into slice", func(t *testing.T) {
+		var out []halOrder
+		flat, err := HALDecoder{}.Decode([]byte(halOrdersDocument), &out)
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if err := json.Unmarshal(flat, &out); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+
+		want := []halOrder{
+			{ID: 123, Total: 30, Customer: halCustomer{Name: "Ada"}},
+			{ID: 124, Total: 20, Customer: halCustomer{Name: "Grace"}},
+		}
+		if !reflect.DeepEqual(out, want) {
+			t.Errorf("Decode() = %+v, want %+v", out, want)
+		}
+	})
+
+	t.Run("single resource into struct", func(t *testing.T) {
+		doc := `{"_links": {"self": {"href": "/orders/123"}}, "id": 123, "total": 30.0,
+			"_embedded": {"customer": {"_links": {"self": {"href": "/customers/7"}}, "name": "Ada"}}}`
+
+		var out halOrder
+		flat, err := HALDecoder{}.Decode([]byte(doc), &out)
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if err := json.Unmarshal(flat, &out); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+
+		want := halOrder{ID: 123, Total: 30, Customer: halCustomer{Name: "Ada"}}
+		if out != want {
+			t.Errorf("Decode() = %+v, want %+v", out, want)
+		}
+	})
+}
+
+type jsonAPIPerson struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type jsonAPIArticle struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Title    string          `json:"title"`
+	Author   jsonAPIPerson   `json:"author"`
+	Comments []jsonAPIPerson `json:"comments"`
+}
+
+const jsonAPIArticlesDocument = `{
+	"data": [{
+		"type": "articles",
+		"id": "1",
+		"attributes": {"title": "JSON:API paints my bikeshed!"},
+		"relationships": {
+			"author": {"data": {"type": "people", "id": "9"}},
+			"comments": {"data": [{"type": "people", "id": "9"}, {"type": "people", "id": "10"}]}
+		}
+	}],
+	"included": [
+		{"type": "people", "id": "9", "attributes": {"name": "Dan"}},
+		{"type": "people", "id": "10", "attributes": {"name": "Eve"}}
+	]
+}`
+
+func TestJSONAPIDecoder_Decode(t *testing.T) {
+	var out []jsonAPIArticle
+	flat, err := JSONAPIDecoder{}.Decode([]byte(jsonAPIArticlesDocument), &out)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if err := json.Unmarshal(flat, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := []jsonAPIArticle{{
+		ID:       "1",
+		Type:     "articles",
+		Title:    "JSON:API paints my bikeshed!",
+		Author:   jsonAPIPerson{ID: "9", Name: "Dan"},
+		Comments: []jsonAPIPerson{{ID: "9", Name: "Dan"}, {ID: "10", Name: "Eve"}},
+	}}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("Decode() = %+v, want %+v", out, want)
+	}
+}
@@ -12,7 +12,6 @@ import (
 
 	"github.com/antchfx/xmlquery"
 	"go.uber.org/zap"
-	"golang.org/x/net/html"
 )
 
 // APIError represents an api error response.
@@ -22,11 +21,38 @@ type APIError struct {
 	URL         string   `json:"url"`               // The URL of the HTTP request
 	Message     string   `json:"message"`           // Summary of the error
 	Details     []string `json:"details,omitempty"` // Detailed error messages, if any
-	RawResponse string   `json:"raw_response"`      // Raw response body for debugging
+	RawResponse string   `json:"raw_response"`      // Raw response body for debugging, truncated to the configured maximum
+
+	rawBody       []byte
+	hideRawOutput bool
+}
+
+// DefaultMaxRawErrorBodyBytes is the amount of an error response body kept on APIError when no limit is configured.
+const DefaultMaxRawErrorBodyBytes = 64 * 1024
+
+// ErrorResponseOptions controls how HandleAPIErrorResponseWithOptions records the error response body.
+type ErrorResponseOptions struct {
+	// MaxRawBodyBytes caps how much of the body is kept in RawResponse and RawBody. 0 uses DefaultMaxRawErrorBodyBytes.
+	MaxRawBodyBytes int
+	// HideSensitiveData leaves the raw body out of Error() so it is not written to logs with the error.
+	// It remains available to the caller through RawResponse and RawBody.
+	HideSensitiveData bool
+}
+
+// RawBody returns the original error response body, truncated to the configured maximum.
+func (e *APIError) RawBody() []byte {
+	return e.rawBody
 }
 
 // Error returns a string representation of the APIError, making it compatible with the error interface.
 func (e *APIError) Error() string {
+	if e.hideRawOutput && e.RawResponse != "" {
+		redacted := *e
+		redacted.RawResponse = "[hidden]"
+		redacted.hideRawOutput = false
+		return redacted.Error()
+	}
+
 	data, err := json.Marshal(e)
 	if err == nil {
 		return string(data)
@@ -41,11 +67,18 @@ func (e *APIError) Error() string {
 
 // HandleAPIErrorResponse handles the HTTP error response from an API and logs the error.
 func HandleAPIErrorResponse(resp *http.Response, sugar *zap.SugaredLogger) *APIError {
+	return HandleAPIErrorResponseWithOptions(resp, sugar, ErrorResponseOptions{})
+}
+
+// HandleAPIErrorResponseWithOptions handles the HTTP error response from an API like HandleAPIErrorResponse,
+// always keeping the original body, truncated to opts.MaxRawBodyBytes, whatever the outcome of parsing it.
+func HandleAPIErrorResponseWithOptions(resp *http.Response, sugar *zap.SugaredLogger, opts ErrorResponseOptions) *APIError {
 	apiError := &APIError{
-		StatusCode: resp.StatusCode,
-		Method:     resp.Request.Method,
-		URL:        resp.Request.URL.String(),
-		Message:    "API Error Response",
+		StatusCode:    resp.StatusCode,
+		Method:        resp.Request.Method,
+		URL:           resp.Request.URL.String(),
+		Message:       "API Error Response",
+		hideRawOutput: opts.HideSensitiveData,
 	}
 
 	bodyBytes, err := io.ReadAll(resp.Body)
@@ -65,29 +98,34 @@ func HandleAPIErrorResponse(resp *http.Response, sugar *zap.SugaredLogger) *APIE
 	case "text/plain":
 		parseTextResponse(bodyBytes, apiError)
 	default:
-		apiError.RawResponse = string(bodyBytes)
 		apiError.Message = "Unknown content type error"
 	}
 
+	maxRaw := opts.MaxRawBodyBytes
+	if maxRaw <= 0 {
+		maxRaw = DefaultMaxRawErrorBodyBytes
+	}
+	if len(bodyBytes) > maxRaw {
+		bodyBytes = bodyBytes[:maxRaw]
+	}
+	apiError.rawBody = bodyBytes
+	apiError.RawResponse = string(bodyBytes)
+
 	return apiError
 }
 
 // parseJSONResponse attempts to parse the JSON error response and update the APIError structure.
+// The raw body is recorded by the caller whether or not parsing succeeds.
 func parseJSONResponse(bodyBytes []byte, apiError *APIError) {
-	if err := json.Unmarshal(bodyBytes, apiError); err != nil {
-		apiError.RawResponse = string(bodyBytes)
-	} else {
+	if err := json.Unmarshal(bodyBytes, apiError); err == nil {
 		if apiError.Message == "" {
 			apiError.Message = "An unknown error occurred"
 		}
-
 	}
 }
 
 // parseXMLResponse dynamically parses XML error responses and accumulates potential error messages.
 func parseXMLResponse(bodyBytes []byte, apiError *APIError) {
-	apiError.RawResponse = string(bodyBytes)
-
 	doc, err := xmlquery.Parse(bytes.NewReader(bodyBytes))
 	if err != nil {
 		return
@@ -116,70 +154,14 @@ func parseXMLResponse(bodyBytes []byte, apiError *APIError) {
 
 // parseTextResponse updates the APIError structure based on a plain text error response and logs it.
 func parseTextResponse(bodyBytes []byte, apiError *APIError) {
-	bodyText := string(bodyBytes)
-	apiError.RawResponse = bodyText
-	apiError.Message = bodyText
+	apiError.Message = string(bodyBytes)
 }
 
-// parseHTMLResponse extracts meaningful information from an HTML error response,
-// concatenating all text within <p> tags and links found within them.
+// parseHTMLResponse sets the APIError message from an HTML error page (see ExtractErrorMessageFromHTML).
 func parseHTMLResponse(bodyBytes []byte, apiError *APIError) {
-	apiError.RawResponse = string(bodyBytes)
-
-	reader := bytes.NewReader(bodyBytes)
-	doc, err := html.Parse(reader)
-	if err != nil {
-		return
-	}
-
-	var messages []string
-	var parse func(*html.Node)
-	parse = func(n *html.Node) {
-		if n.Type == html.ElementNode && n.Data == "p" {
-			var pContent strings.Builder
-			// Define a function to traverse child nodes of the <p> tag.
-			var traverseChildren func(*html.Node)
-			traverseChildren = func(c *html.Node) {
-				if c.Type == html.TextNode {
-					// Append text content directly.
-					pContent.WriteString(strings.TrimSpace(c.Data) + " ")
-				} else if c.Type == html.ElementNode && c.Data == "a" {
-					// Extract href attribute value for links.
-					for _, attr := range c.Attr {
-						if attr.Key == "href" {
-							// Append the link to the pContent builder.
-							pContent.WriteString("[Link: " + attr.Val + "] ")
-							break
-						}
-					}
-				}
-				// Recursively traverse all children of the current node.
-				for child := c.FirstChild; child != nil; child = child.NextSibling {
-					traverseChildren(child)
-				}
-			}
-			// Start traversing child nodes of the current <p> tag.
-			for child := n.FirstChild; child != nil; child = child.NextSibling {
-				traverseChildren(child)
-			}
-			finalContent := strings.TrimSpace(pContent.String())
-			if finalContent != "" {
-				// Add the content of the <p> tag to messages.
-				messages = append(messages, finalContent)
-			}
-		}
-		// Continue traversing the document.
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			parse(c)
-		}
-	}
-
-	parse(doc)
-
-	if len(messages) > 0 {
-		apiError.Message = strings.Join(messages, "; ")
+	if message := ExtractErrorMessageFromHTML(bodyBytes); message != "" {
+		apiError.Message = message
 	} else {
 		apiError.Message = "HTML Error: See 'Raw' field for details."
 	}
-
 }
@@ -0,0 +1,64 @@
+// response/error_test.go
+package response
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func newErrorResponse(contentType, body string) *http.Response {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/users/1", nil)
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{"Content-Type": []string{contentType}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func TestHandleAPIErrorResponseWithOptions_RawBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		maxRaw      int
+		wantRaw     string
+		wantMessage string
+	}{
+		{"parsed json keeps raw body", "application/json", `{"message":"bad id"}`, 0, `{"message":"bad id"}`, "bad id"},
+		{"invalid json keeps raw body", "application/json", `{not json`, 0, `{not json`, "API Error Response"},
+		{"truncated", "text/plain", "0123456789", 4, "0123", "0123456789"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := HandleAPIErrorResponseWithOptions(newErrorResponse(tt.contentType, tt.body), zap.NewNop().Sugar(), ErrorResponseOptions{MaxRawBodyBytes: tt.maxRaw})
+
+			if string(apiErr.RawBody()) != tt.wantRaw || apiErr.RawResponse != tt.wantRaw {
+				t.Errorf("raw body = %q / %q, want %q", apiErr.RawBody(), apiErr.RawResponse, tt.wantRaw)
+			}
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", apiErr.Message, tt.wantMessage)
+			}
+			if apiErr.StatusCode != http.StatusBadRequest {
+				t.Errorf("status code = %d, want 400", apiErr.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAPIError_HideSensitiveData(t *testing.T) {
+	body := `{"message":"denied","token":"secret-value"}`
+	apiErr := HandleAPIErrorResponseWithOptions(newErrorResponse("application/json", body), zap.NewNop().Sugar(), ErrorResponseOptions{HideSensitiveData: true})
+
+	if strings.Contains(apiErr.Error(), "secret-value") {
+		t.Errorf("Error() leaks raw body: %s", apiErr.Error())
+	}
+	if string(apiErr.RawBody()) != body {
+		t.Errorf("RawBody() = %q, want original body", apiErr.RawBody())
+	}
+}
@@ -0,0 +1,84 @@
+// response/html.go
+package response
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ExtractErrorMessageFromHTML pulls a readable error message out of an HTML error page, such as those served by
+// proxies, load balancers and login redirects. The text of every <p> element, including nested markup, is joined
+// with "; ", and links inside paragraphs are kept as "[Link: href]". Pages without paragraphs fall back to their
+// <title> and then their first heading. Invalid UTF-8 is replaced rather than propagated. Returns "" when the page
+// contains no usable text.
+func ExtractErrorMessageFromHTML(body []byte) string {
+	doc, err := html.Parse(bytes.NewReader(bytes.ToValidUTF8(body, []byte("�"))))
+	if err != nil {
+		return ""
+	}
+
+	if paragraphs := collectElementText(doc, "p"); len(paragraphs) > 0 {
+		return strings.Join(paragraphs, "; ")
+	}
+
+	for _, tag := range []string{"title", "h1", "h2", "h3"} {
+		if texts := collectElementText(doc, tag); len(texts) > 0 {
+			return texts[0]
+		}
+	}
+
+	return ""
+}
+
+// collectElementText returns the non-empty, whitespace collapsed text of every tag element below n, in document
+// order. Elements nested in a matching element are part of its text rather than reported separately.
+func collectElementText(n *html.Node, tag string) []string {
+	var texts []string
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == tag {
+			var b strings.Builder
+			writeNodeText(&b, n)
+			if text := strings.Join(strings.Fields(b.String()), " "); text != "" {
+				texts = append(texts, text)
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+
+	return texts
+}
+
+// writeNodeText writes the text below n to b, annotating links with their target and skipping scripts and styles.
+func writeNodeText(b *strings.Builder, n *html.Node) {
+	switch {
+	case n.Type == html.TextNode:
+		b.WriteString(n.Data)
+		return
+	case n.Type == html.ElementNode && n.Data == "br":
+		b.WriteString(" ")
+		return
+	case n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style"):
+		return
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeNodeText(b, c)
+	}
+
+	if n.Type == html.ElementNode && n.Data == "a" {
+		for _, attr := range n.Attr {
+			if attr.Key == "href" {
+				b.WriteString(" [Link: " + attr.Val + "] ")
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// response/html_test.go
+package response
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+func TestExtractErrorMessageFromHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "paragraphs joined",
+			body: `<html><body><p>Service unavailable.</p><p>Try again later.</p></body></html>`,
+			want: "Service unavailable.; Try again later.",
+		},
+		{
+			name: "nested tags and links",
+			body: `<div><p>Access <b>denied</b> for <i>this <span>resource</span></i>. <a href="/login">Sign in</a></p></div>`,
+			want: "Access denied for this resource. Sign in [Link: /login]",
+		},
+		{
+			name: "line breaks",
+			body: "<p>Bad<br>Gateway</p>",
+			want: "Bad Gateway",
+		},
+		{
+			name: "whitespace collapsed",
+			body: "<p>\n\t  Bad\n   Gateway  \n</p>",
+			want: "Bad Gateway",
+		},
+		{
+			name: "scripts ignored",
+			body: `<p>Error<script>var x = 1;</script></p>`,
+			want: "Error",
+		},
+		{
+			name: "no paragraphs falls back to title",
+			body: `<html><head><title>502 Bad Gateway</title></head><body><center><h1>502 Bad Gateway</h1></center></body></html>`,
+			want: "502 Bad Gateway",
+		},
+		{
+			name: "no paragraphs or title falls back to heading",
+			body: `<body><h1>Forbidden</h1><hr></body>`,
+			want: "Forbidden",
+		},
+		{
+			name: "empty paragraphs skipped",
+			body: `<p>  </p><p>Not found</p>`,
+			want: "Not found",
+		},
+		{
+			name: "no text",
+			body: `<html><body><div></div></body></html>`,
+			want: "",
+		},
+		{
+			name: "empty body",
+			body: ``,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractErrorMessageFromHTML([]byte(tt.body)); got != tt.want {
+				t.Errorf("ExtractErrorMessageFromHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractErrorMessageFromHTML_InvalidUTF8(t *testing.T) {
+	// "Caf\xe9" is Latin-1 encoded, which is invalid UTF-8.
+	got := ExtractErrorMessageFromHTML([]byte("<p>Caf\xe9 closed</p>"))
+
+	if !utf8.ValidString(got) {
+		t.Fatalf("ExtractErrorMessageFromHTML() = %q, want valid UTF-8", got)
+	}
+	if got != "Caf� closed" {
+		t.Errorf("ExtractErrorMessageFromHTML() = %q, want %q", got, "Caf� closed")
+	}
+}
+
+func TestHandleAPIErrorResponse_HTML(t *testing.T) {
+	apiErr := HandleAPIErrorResponseWithOptions(newErrorResponse("text/html; charset=utf-8", `<title>Oops</title>`), zap.NewNop().Sugar(), ErrorResponseOptions{})
+	if apiErr.Message != "Oops" {
+		t.Errorf("Message = %q, want Oops", apiErr.Message)
+	}
+
+	apiErr = HandleAPIErrorResponseWithOptions(newErrorResponse("text/html", `<div></div>`), zap.NewNop().Sugar(), ErrorResponseOptions{})
+	if apiErr.Message != "HTML Error: See 'Raw' field for details." {
+		t.Errorf("Message = %q, want the raw field hint", apiErr.Message)
+	}
+}
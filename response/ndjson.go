@@ -0,0 +1,61 @@
+// response/ndjson.go
+/* Decoding of newline-delimited JSON (application/x-ndjson) streams, where every line is a separate JSON document. */
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"go.uber.org/zap"
+)
+
+// DecodeNDJSON reads successive JSON documents from reader, calling onRecord with each one in turn.
+// Decoding stops at the end of the stream or at the first error returned by onRecord.
+func DecodeNDJSON(reader io.Reader, onRecord func(json.RawMessage) error) error {
+	decoder := json.NewDecoder(reader)
+	for line := 1; ; line++ {
+		var record json.RawMessage
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode NDJSON record %d: %w", line, err)
+		}
+
+		if err := onRecord(record); err != nil {
+			return err
+		}
+	}
+}
+
+// handlerUnmarshalNDJSON decodes an NDJSON stream into out, which must be a pointer to a slice.
+// Each record is unmarshalled into a new element appended to the slice.
+func handlerUnmarshalNDJSON(reader io.Reader, out interface{}, sugar *zap.SugaredLogger, mimeType string) error {
+	target := reflect.ValueOf(out)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("output parameter for %s must be a pointer to a slice, got %T", mimeType, out)
+	}
+
+	slice := target.Elem()
+	elemType := slice.Type().Elem()
+
+	err := DecodeNDJSON(reader, func(record json.RawMessage) error {
+		elem := reflect.New(elemType)
+		if err := json.Unmarshal(record, elem.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, elem.Elem()))
+		return nil
+	})
+	if err != nil {
+		sugar.Errorw("NDJSON Unmarshal error", zap.Error(err))
+		return err
+	}
+
+	sugar.Infow("Successfully unmarshalled NDJSON response", zap.String("content type", mimeType), zap.Int("records", slice.Len()))
+	return nil
+}
@@ -0,0 +1,81 @@
+// response/ndjson_test.go
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+type ndjsonEvent struct {
+	ID   int    `json:"id"`
+	Type string `json:"type"`
+}
+
+const ndjsonEvents = "{\"id\":1,\"type\":\"created\"}\n{\"id\":2,\"type\":\"updated\"}\n\n{\"id\":3,\"type\":\"deleted\"}\n"
+
+func TestDecodeNDJSON(t *testing.T) {
+	t.Run("calls onRecord per line", func(t *testing.T) {
+		var records []string
+		err := DecodeNDJSON(strings.NewReader(ndjsonEvents), func(record json.RawMessage) error {
+			records = append(records, string(record))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("DecodeNDJSON() error = %v", err)
+		}
+		if len(records) != 3 || records[2] != `{"id":3,"type":"deleted"}` {
+			t.Errorf("records = %q", records)
+		}
+	})
+
+	t.Run("stops on callback error", func(t *testing.T) {
+		stop := errors.New("stop")
+		calls := 0
+		err := DecodeNDJSON(strings.NewReader(ndjsonEvents), func(record json.RawMessage) error {
+			calls++
+			return stop
+		})
+		if !errors.Is(err, stop) || calls != 1 {
+			t.Errorf("err = %v, calls = %d; want stop after 1 call", err, calls)
+		}
+	})
+
+	t.Run("malformed record", func(t *testing.T) {
+		err := DecodeNDJSON(strings.NewReader("{\"id\":1}\n{bad\n"), func(json.RawMessage) error { return nil })
+		if err == nil || !strings.Contains(err.Error(), "record 2") {
+			t.Errorf("err = %v, want error for record 2", err)
+		}
+	})
+}
+
+func TestHandleAPISuccessResponse_NDJSON(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/x-ndjson; charset=utf-8"}},
+		Body:       io.NopCloser(strings.NewReader(ndjsonEvents)),
+		Request:    &http.Request{Method: http.MethodGet},
+	}
+
+	var out []ndjsonEvent
+	if err := HandleAPISuccessResponse(resp, &out, zap.NewNop().Sugar()); err != nil {
+		t.Fatalf("HandleAPISuccessResponse() error = %v", err)
+	}
+
+	want := []ndjsonEvent{{1, "created"}, {2, "updated"}, {3, "deleted"}}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("out = %+v, want %+v", out, want)
+	}
+
+	var notSlice ndjsonEvent
+	resp.Body = io.NopCloser(strings.NewReader(ndjsonEvents))
+	if err := HandleAPISuccessResponse(resp, &notSlice, zap.NewNop().Sugar()); err == nil {
+		t.Error("expected error decoding NDJSON into a non-slice")
+	}
+}
@@ -21,14 +21,21 @@ type contentHandler func(io.Reader, interface{}, *zap.SugaredLogger, string) err
 
 // responseUnmarshallers maps MIME types to the corresponding contentHandler functions.
 var responseUnmarshallers = map[string]contentHandler{
-	"application/json": handlerUnmarshalJSON,
-	"application/xml":  handlerUnmarshalXML,
-	"text/xml":         handlerUnmarshalXML,
+	"application/json":         handlerUnmarshalJSON,
+	"application/hal+json":     handlerUnmarshalJSON,
+	"application/vnd.api+json": handlerUnmarshalJSON,
+	"application/x-ndjson":     handlerUnmarshalNDJSON,
+	"application/xml":          handlerUnmarshalXML,
+	"text/xml":                 handlerUnmarshalXML,
 }
 
 // HandleAPISuccessResponse reads the response body, logs the raw response details, and unmarshals the response based on the content type.
+// An empty body leaves out untouched and is not an error.
+// Besides typed pointers, out may be a *[]byte, which receives the body verbatim whatever its content type, a
+// *map[string]interface{} for a generic decoding of JSON or XML, or a *json.RawMessage, which receives the JSON body
+// undecoded or, for XML, the generic decoding re-encoded as JSON.
 func HandleAPISuccessResponse(resp *http.Response, out interface{}, sugar *zap.SugaredLogger) error {
-	if resp.Request.Method == "DELETE" {
+	if resp.Request.Method == http.MethodDelete && out == nil {
 		return successfulDeleteRequest(resp, sugar)
 	}
 
@@ -38,9 +45,26 @@ func HandleAPISuccessResponse(resp *http.Response, out interface{}, sugar *zap.S
 		return err
 	}
 
+	// DELETE replies frequently carry no body; only decode one when the caller asked for it and there is one.
+	if resp.Request.Method == http.MethodDelete && len(bodyBytes) == 0 {
+		return successfulDeleteRequest(resp, sugar)
+	}
+
+	// An empty body is a legitimate success with nothing to decode, whatever the content type claims.
+	if len(bodyBytes) == 0 {
+		sugar.Debug("Empty response body, leaving output untouched", zap.Int("Status Code", resp.StatusCode))
+		return nil
+	}
+
 	// TODO do we need to redact some auth headers here? I think so.
 	// sugar.Debugw("HTTP Response Headers", zap.Any("Headers", resp.Header))
-	sugar.Debugw("Raw HTTP Response", zap.String("Body", string(bodyBytes)))
+	// The raw body is logged, subject to a size threshold, by the http client before it reaches this handler.
+
+	if raw, ok := out.(*[]byte); ok {
+		*raw = bodyBytes
+		sugar.Debug("Returning undecoded response body", zap.Int("bytes", len(bodyBytes)))
+		return nil
+	}
 
 	bodyReader := bytes.NewReader(bodyBytes)
 	contentType := resp.Header.Get("Content-Type")
@@ -59,13 +83,17 @@ func HandleAPISuccessResponse(resp *http.Response, out interface{}, sugar *zap.S
 		return handleBinaryData(bodyReader, sugar, out, contentDisposition)
 	}
 
+	if resp.Request.Method == http.MethodDelete {
+		return successfulDeleteRequest(resp, sugar)
+	}
+
 	errMsg := fmt.Sprintf("unexpected MIME type: %s", contentType)
 	sugar.Errorw("Unmarshal error", zap.String("content type", contentType), zap.Error(errors.New(errMsg)))
 	return errors.New(errMsg)
 
 }
 
-// handleDeleteRequest handles the special case for DELETE requests, where a successful response might not contain a body.
+// successfulDeleteRequest handles the special case for DELETE requests, where a successful response might not contain a body.
 func successfulDeleteRequest(resp *http.Response, sugar *zap.SugaredLogger) error {
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 		sugar.Info("Successfully processed DELETE request", zap.String("URL", resp.Request.URL.String()), zap.Int("Status Code", resp.StatusCode))
@@ -85,8 +113,33 @@ func handlerUnmarshalJSON(reader io.Reader, out interface{}, sugar *zap.SugaredL
 	return nil
 }
 
-// unmarshalXML unmarshals XML content from an io.Reader into the provided output structure.
+// unmarshalXML unmarshals XML content from an io.Reader into the provided output structure. A *map[string]interface{}
+// or *json.RawMessage receives the generic decoding described on decodeXMLMap.
 func handlerUnmarshalXML(reader io.Reader, out interface{}, sugar *zap.SugaredLogger, mimeType string) error {
+	switch target := out.(type) {
+	case *map[string]interface{}:
+		fields, err := decodeXMLMap(reader)
+		if err != nil {
+			sugar.Error("XML Unmarshal error", zap.Error(err))
+			return err
+		}
+		*target = fields
+		return nil
+
+	case *json.RawMessage:
+		fields, err := decodeXMLMap(reader)
+		if err != nil {
+			sugar.Error("XML Unmarshal error", zap.Error(err))
+			return err
+		}
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		*target = data
+		return nil
+	}
+
 	decoder := xml.NewDecoder(reader)
 	if err := decoder.Decode(out); err != nil {
 		sugar.Error("XML Unmarshal error", zap.Error(err))
@@ -0,0 +1,141 @@
+// response/success_test.go
+package response
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func newSuccessResponse(contentType, body string) *http.Response {
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/widgets/1", nil)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{contentType}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func TestHandleAPISuccessResponseGenericTargets(t *testing.T) {
+	const (
+		jsonBody = `{"id":1,"name":"widget","tags":["a","b"]}`
+		xmlBody  = `<widget kind="gear"><id>1</id><name>widget</name><tag>a</tag><tag>b</tag></widget>`
+	)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		newOut      func() interface{}
+		want        interface{}
+	}{
+		{
+			name:        "json into raw bytes",
+			contentType: "application/json",
+			body:        jsonBody,
+			newOut:      func() interface{} { return new([]byte) },
+			want:        []byte(jsonBody),
+		},
+		{
+			name:        "json into raw message",
+			contentType: "application/json; charset=utf-8",
+			body:        jsonBody,
+			newOut:      func() interface{} { return new(json.RawMessage) },
+			want:        json.RawMessage(jsonBody),
+		},
+		{
+			name:        "json into map",
+			contentType: "application/json",
+			body:        jsonBody,
+			newOut:      func() interface{} { return new(map[string]interface{}) },
+			want:        map[string]interface{}{"id": float64(1), "name": "widget", "tags": []interface{}{"a", "b"}},
+		},
+		{
+			name:        "xml into raw bytes",
+			contentType: "application/xml",
+			body:        xmlBody,
+			newOut:      func() interface{} { return new([]byte) },
+			want:        []byte(xmlBody),
+		},
+		{
+			name:        "xml into raw message",
+			contentType: "text/xml",
+			body:        xmlBody,
+			newOut:      func() interface{} { return new(json.RawMessage) },
+			want:        json.RawMessage(`{"@kind":"gear","id":"1","name":"widget","tag":["a","b"]}`),
+		},
+		{
+			name:        "xml into map",
+			contentType: "application/xml",
+			body:        xmlBody,
+			newOut:      func() interface{} { return new(map[string]interface{}) },
+			want:        map[string]interface{}{"@kind": "gear", "id": "1", "name": "widget", "tag": []interface{}{"a", "b"}},
+		},
+		{
+			name:        "unknown content type into raw bytes",
+			contentType: "text/csv",
+			body:        "id,name\n1,widget\n",
+			newOut:      func() interface{} { return new([]byte) },
+			want:        []byte("id,name\n1,widget\n"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := tt.newOut()
+			if err := HandleAPISuccessResponse(newSuccessResponse(tt.contentType, tt.body), out, zap.NewNop().Sugar()); err != nil {
+				t.Fatalf("HandleAPISuccessResponse() error = %v", err)
+			}
+
+			got := reflect.ValueOf(out).Elem().Interface()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("out = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeXMLMap(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<computer>
+	<general id="7">Mac <name>studio</name></general>
+	<empty/>
+</computer>`
+
+	got, err := decodeXMLMap(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("decodeXMLMap() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"general": map[string]interface{}{"@id": "7", "#text": "Mac", "name": "studio"},
+		"empty":   "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeXMLMap() = %#v, want %#v", got, want)
+	}
+
+	if _, err := decodeXMLMap(strings.NewReader("")); err == nil {
+		t.Error("decodeXMLMap() accepted an empty document")
+	}
+}
+
+func TestHandleAPISuccessResponseEmptyBody(t *testing.T) {
+	for _, contentType := range []string{"application/json", "application/xml", ""} {
+		t.Run(contentType, func(t *testing.T) {
+			out := map[string]interface{}{"kept": true}
+			if err := HandleAPISuccessResponse(newSuccessResponse(contentType, ""), &out, zap.NewNop().Sugar()); err != nil {
+				t.Fatalf("HandleAPISuccessResponse() error = %v", err)
+			}
+			if !reflect.DeepEqual(out, map[string]interface{}{"kept": true}) {
+				t.Errorf("out = %v, want it untouched", out)
+			}
+		})
+	}
+}
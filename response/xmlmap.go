@@ -0,0 +1,93 @@
+// response/xmlmap.go
+/* Generic decoding of XML documents into map[string]interface{}, for callers which want to inspect a response
+without declaring a struct for it. */
+package response
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// decodeXMLMap decodes the XML document read from reader into a map of the root element's contents. Child
+// elements become keys holding either their text, when they have no children or attributes, or a nested map.
+// Repeated elements are collected into a []interface{}, attributes are keyed "@name" and text alongside child
+// elements or attributes is keyed "#text". The root element's own name is not included, as when unmarshalling
+// into a struct.
+func decodeXMLMap(reader io.Reader) (map[string]interface{}, error) {
+	decoder := xml.NewDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("xml document has no root element")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		value, err := decodeXMLElement(decoder, start)
+		if err != nil {
+			return nil, err
+		}
+		if fields, ok := value.(map[string]interface{}); ok {
+			return fields, nil
+		}
+		return map[string]interface{}{"#text": value}, nil
+	}
+}
+
+// decodeXMLElement decodes the element opened by start, returning its text or a map of its attributes and children.
+func decodeXMLElement(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	fields := make(map[string]interface{})
+	for _, attr := range start.Attr {
+		fields["@"+attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(decoder, token)
+			if err != nil {
+				return nil, err
+			}
+			addXMLField(fields, token.Name.Local, child)
+		case xml.CharData:
+			text.Write(token)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+			if len(fields) == 0 {
+				return content, nil
+			}
+			if content != "" {
+				fields["#text"] = content
+			}
+			return fields, nil
+		}
+	}
+}
+
+// addXMLField stores value under name, turning the entry into a list when the element repeats.
+func addXMLField(fields map[string]interface{}, name string, value interface{}) {
+	existing, ok := fields[name]
+	if !ok {
+		fields[name] = value
+		return
+	}
+	if list, ok := existing.([]interface{}); ok {
+		fields[name] = append(list, value)
+		return
+	}
+	fields[name] = []interface{}{existing, value}
+}